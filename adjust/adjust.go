@@ -0,0 +1,161 @@
+package adjust
+
+import (
+	"defcor/iex"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Mode selects which corporate actions an adjusted series accounts for
+type Mode int
+
+const (
+	// SplitOnly adjusts for splits
+	SplitOnly Mode = iota
+	// TotalReturn adjusts for splits and dividends
+	TotalReturn
+)
+
+// ParseMode converts split or total into a Mode
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "split":
+		return SplitOnly, nil
+	case "total":
+		return TotalReturn, nil
+	}
+	return SplitOnly, fmt.Errorf("unknown adjustment mode %q", s)
+}
+
+// Factor holds the cumulative multipliers applied to the raw bar of Date
+type Factor struct {
+	Date     string
+	Split    float64
+	Dividend float64
+}
+
+// Total is the combined split and dividend multiplier
+func (f Factor) Total() float64 {
+	return f.Split * f.Dividend
+}
+
+// Factors builds cumulative adjustment factors for every raw bar, counting the
+// corporate actions with an exdate after the bar and on or before asOf.
+// prices must be unadjusted bars ordered by date; an empty asOf means no cut off.
+func Factors(prices []iex.Prices, splits []iex.Split, dividends []iex.Dividend, asOf string) []Factor {
+	// the dividend is measured against the last close before the exdate
+	closeBefore := func(exdate string) float64 {
+		i := sort.Search(len(prices), func(i int) bool { return prices[i].Date >= exdate }) - 1
+		if i < 0 {
+			return 0
+		}
+		return prices[i].Uclose
+	}
+	return factors(prices, splits, dividends, asOf, closeBefore)
+}
+
+// PageFactors builds the factors of a run of bars cut from a longer history.
+// closes holds the last unadjusted close before each dividend exdate, which
+// may fall outside the page; dividends without one are skipped.
+func PageFactors(prices []iex.Prices, splits []iex.Split, dividends []iex.Dividend, asOf string, closes map[string]float64) []Factor {
+	return factors(prices, splits, dividends, asOf, func(exdate string) float64 { return closes[exdate] })
+}
+
+func factors(prices []iex.Prices, splits []iex.Split, dividends []iex.Dividend, asOf string, closeBefore func(exdate string) float64) []Factor {
+	// per exdate multipliers apply to every bar strictly before the exdate
+	splitAt := make(map[string]float64)
+	for _, s := range splits {
+		if s.ToFactor == 0 || s.FromFactor == 0 || !onOrBefore(s.ExDate, asOf) {
+			continue
+		}
+		if _, ok := splitAt[s.ExDate]; !ok {
+			splitAt[s.ExDate] = 1
+		}
+		splitAt[s.ExDate] *= s.FromFactor / s.ToFactor
+	}
+	divAt := make(map[string]float64)
+	for _, d := range dividends {
+		if !d.Amount.Valid || d.Amount.Float64 <= 0 || !onOrBefore(d.ExDate, asOf) {
+			continue
+		}
+		prior := closeBefore(d.ExDate)
+		if prior <= 0 {
+			continue
+		}
+		if _, ok := divAt[d.ExDate]; !ok {
+			divAt[d.ExDate] = 1
+		}
+		divAt[d.ExDate] *= 1 - d.Amount.Float64/prior
+	}
+
+	events := make([]string, 0, len(splitAt)+len(divAt))
+	for date := range splitAt {
+		events = append(events, date)
+	}
+	for date := range divAt {
+		if _, ok := splitAt[date]; !ok {
+			events = append(events, date)
+		}
+	}
+	sort.Strings(events)
+
+	// walk backwards so each bar picks up every later event
+	factors := make([]Factor, len(prices))
+	split, div := 1.0, 1.0
+	next := len(events) - 1
+	for i := len(prices) - 1; i >= 0; i-- {
+		for next >= 0 && events[next] > prices[i].Date {
+			if m, ok := splitAt[events[next]]; ok {
+				split *= m
+			}
+			if m, ok := divAt[events[next]]; ok {
+				div *= m
+			}
+			next--
+		}
+		factors[i] = Factor{Date: prices[i].Date, Split: split, Dividend: div}
+	}
+	return factors
+}
+
+// Adjust returns a copy of prices whose adjusted columns are recomputed from
+// the unadjusted columns and factors; AdjustedOnly bars are copied as stored
+func Adjust(prices []iex.Prices, factors []Factor, mode Mode) []iex.Prices {
+	out := make([]iex.Prices, len(prices))
+	for i, p := range prices {
+		// without unadjusted columns the vendor's adjusted ones are all there is
+		if p.AdjustedOnly {
+			out[i] = p
+			continue
+		}
+		f := factors[i]
+		m := f.Split
+		if mode == TotalReturn {
+			m = f.Total()
+		}
+		p.Aopen = p.Uopen * m
+		p.Ahigh = p.Uhigh * m
+		p.Alow = p.Ulow * m
+		p.Aclose = p.Uclose * m
+		// share counts only change with splits
+		p.Avolume = int(math.Round(float64(p.Uvolume) / f.Split))
+		out[i] = p
+	}
+	return out
+}
+
+// Series computes the adjusted history of a security as of asOf
+func Series(ph *iex.PriceHistory, sh *iex.SplitHistory, dh *iex.DividendHistory, asOf string, mode Mode) *iex.PriceHistory {
+	factors := Factors(ph.Prices, sh.Splits, dh.Dividends, asOf)
+	prices := ph.Prices
+	if asOf != "" {
+		n := sort.Search(len(prices), func(i int) bool { return prices[i].Date > asOf })
+		prices, factors = prices[:n], factors[:n]
+	}
+	return &iex.PriceHistory{Symbol: ph.Symbol, Prices: Adjust(prices, factors, mode)}
+}
+
+func onOrBefore(date, asOf string) bool {
+	return asOf == "" || date <= asOf
+}
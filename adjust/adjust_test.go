@@ -0,0 +1,164 @@
+package adjust
+
+import (
+	"database/sql"
+	"defcor/iex"
+	"math"
+	"testing"
+)
+
+func bars(closes map[string]float64, dates ...string) []iex.Prices {
+	prices := make([]iex.Prices, len(dates))
+	for i, d := range dates {
+		prices[i] = iex.Prices{Date: d, Uclose: closes[d]}
+	}
+	return prices
+}
+
+func dividend(exdate string, amount float64) iex.Dividend {
+	return iex.Dividend{ExDate: exdate, Amount: iex.NullNumber{NullFloat64: sql.NullFloat64{Float64: amount, Valid: true}}}
+}
+
+func TestFactors(t *testing.T) {
+	dates := []string{"2020-01-02", "2020-01-03", "2020-01-06", "2020-01-07"}
+	prices := bars(map[string]float64{
+		"2020-01-02": 100, "2020-01-03": 100, "2020-01-06": 50, "2020-01-07": 50,
+	}, dates...)
+	tests := []struct {
+		name      string
+		splits    []iex.Split
+		dividends []iex.Dividend
+		asOf      string
+		split     []float64
+		dividend  []float64
+	}{
+		{
+			name:     "no actions",
+			split:    []float64{1, 1, 1, 1},
+			dividend: []float64{1, 1, 1, 1},
+		},
+		{
+			name:     "two for one split",
+			splits:   []iex.Split{{ExDate: "2020-01-06", ToFactor: 2, FromFactor: 1}},
+			split:    []float64{0.5, 0.5, 1, 1},
+			dividend: []float64{1, 1, 1, 1},
+		},
+		{
+			name:     "split on a weekend applies to the bars before it",
+			splits:   []iex.Split{{ExDate: "2020-01-04", ToFactor: 2, FromFactor: 1}},
+			split:    []float64{0.5, 0.5, 1, 1},
+			dividend: []float64{1, 1, 1, 1},
+		},
+		{
+			name:     "reverse split",
+			splits:   []iex.Split{{ExDate: "2020-01-07", ToFactor: 1, FromFactor: 4}},
+			split:    []float64{4, 4, 4, 1},
+			dividend: []float64{1, 1, 1, 1},
+		},
+		{
+			name:     "zero factor is ignored",
+			splits:   []iex.Split{{ExDate: "2020-01-06", ToFactor: 0, FromFactor: 1}},
+			split:    []float64{1, 1, 1, 1},
+			dividend: []float64{1, 1, 1, 1},
+		},
+		{
+			name:      "dividend measured against the prior close",
+			dividends: []iex.Dividend{dividend("2020-01-07", 1)},
+			split:     []float64{1, 1, 1, 1},
+			dividend:  []float64{0.98, 0.98, 0.98, 1},
+		},
+		{
+			name:      "dividends sharing an exdate compound",
+			dividends: []iex.Dividend{dividend("2020-01-07", 1), dividend("2020-01-07", 2.5)},
+			split:     []float64{1, 1, 1, 1},
+			dividend:  []float64{0.98 * 0.95, 0.98 * 0.95, 0.98 * 0.95, 1},
+		},
+		{
+			name:      "dividend before the first bar is skipped",
+			dividends: []iex.Dividend{dividend("2020-01-02", 1)},
+			split:     []float64{1, 1, 1, 1},
+			dividend:  []float64{1, 1, 1, 1},
+		},
+		{
+			name:      "split and dividend",
+			splits:    []iex.Split{{ExDate: "2020-01-06", ToFactor: 2, FromFactor: 1}},
+			dividends: []iex.Dividend{dividend("2020-01-03", 2)},
+			split:     []float64{0.5, 0.5, 1, 1},
+			dividend:  []float64{0.98, 1, 1, 1},
+		},
+		{
+			name:      "actions after asOf are left out",
+			splits:    []iex.Split{{ExDate: "2020-01-06", ToFactor: 2, FromFactor: 1}},
+			dividends: []iex.Dividend{dividend("2020-01-03", 2)},
+			asOf:      "2020-01-03",
+			split:     []float64{1, 1, 1, 1},
+			dividend:  []float64{0.98, 1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factors := Factors(prices, tt.splits, tt.dividends, tt.asOf)
+			if len(factors) != len(prices) {
+				t.Fatalf("got %d factors, want %d", len(factors), len(prices))
+			}
+			for i, f := range factors {
+				if f.Date != dates[i] {
+					t.Errorf("factor %d: date %s, want %s", i, f.Date, dates[i])
+				}
+				if math.Abs(f.Split-tt.split[i]) > 1e-9 {
+					t.Errorf("%s: split %v, want %v", f.Date, f.Split, tt.split[i])
+				}
+				if math.Abs(f.Dividend-tt.dividend[i]) > 1e-9 {
+					t.Errorf("%s: dividend %v, want %v", f.Date, f.Dividend, tt.dividend[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	prices := []iex.Prices{{Date: "2020-01-03", Uopen: 98, Uhigh: 102, Ulow: 96, Uclose: 100, Uvolume: 1000}}
+	factors := []Factor{{Date: "2020-01-03", Split: 0.5, Dividend: 0.98}}
+	tests := []struct {
+		mode   Mode
+		close  float64
+		volume int
+	}{
+		{SplitOnly, 50, 2000},
+		{TotalReturn, 49, 2000},
+	}
+	for _, tt := range tests {
+		p := Adjust(prices, factors, tt.mode)[0]
+		if math.Abs(p.Aclose-tt.close) > 1e-9 {
+			t.Errorf("mode %d: close %v, want %v", tt.mode, p.Aclose, tt.close)
+		}
+		if p.Avolume != tt.volume {
+			t.Errorf("mode %d: volume %d, want %d", tt.mode, p.Avolume, tt.volume)
+		}
+		if p.Uclose != 100 {
+			t.Errorf("mode %d: unadjusted close changed to %v", tt.mode, p.Uclose)
+		}
+	}
+}
+
+func TestPageFactorsMatchFullHistory(t *testing.T) {
+	dates := []string{"2020-01-02", "2020-01-03", "2020-01-06", "2020-01-07"}
+	prices := bars(map[string]float64{
+		"2020-01-02": 100, "2020-01-03": 100, "2020-01-06": 50, "2020-01-07": 50,
+	}, dates...)
+	splits := []iex.Split{{ExDate: "2020-01-06", ToFactor: 2, FromFactor: 1}}
+	dividends := []iex.Dividend{dividend("2020-01-03", 2), dividend("2020-01-07", 1)}
+	full := Factors(prices, splits, dividends, "")
+	// the page starts after the close the first dividend is measured against
+	closes := map[string]float64{"2020-01-03": 100, "2020-01-07": 50}
+	for lo := 0; lo < len(prices); lo++ {
+		for hi := lo + 1; hi <= len(prices); hi++ {
+			page := PageFactors(prices[lo:hi], splits, dividends, "", closes)
+			for i, f := range page {
+				if f != full[lo+i] {
+					t.Errorf("page [%d:%d] %s: %+v, want %+v", lo, hi, f.Date, f, full[lo+i])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package adjust
+
+import "defcor/iex"
+
+// IndexPoint is a single value of a total return index
+type IndexPoint struct {
+	Date  string
+	Value float64
+}
+
+// TotalReturnIndex reinvests dividends at the exdate close. prices must be
+// unadjusted bars ordered by date; prices[0] anchors the index at base, which
+// lets a stored index be extended from its last point. Splits and dividends
+// with an exdate on a non trading day apply to the next bar.
+func TotalReturnIndex(prices []iex.Prices, splits []iex.Split, dividends []iex.Dividend, base float64) []IndexPoint {
+	if len(prices) == 0 {
+		return nil
+	}
+	points := make([]IndexPoint, len(prices))
+	points[0] = IndexPoint{Date: prices[0].Date, Value: base}
+	for i := 1; i < len(prices); i++ {
+		prev, cur := prices[i-1], prices[i]
+		// shares held per share owned at the previous close
+		shares := 1.0
+		for _, s := range splits {
+			if s.ExDate > prev.Date && s.ExDate <= cur.Date && s.FromFactor != 0 {
+				shares *= s.ToFactor / s.FromFactor
+			}
+		}
+		var div float64
+		for _, d := range dividends {
+			if d.Amount.Valid && d.ExDate > prev.Date && d.ExDate <= cur.Date {
+				div += d.Amount.Float64
+			}
+		}
+		value := points[i-1].Value
+		switch {
+		case prev.AdjustedOnly && cur.AdjustedOnly:
+			// the vendor's adjusted closes already carry the splits and dividends
+			if prev.Aclose > 0 && cur.Aclose > 0 {
+				value *= cur.Aclose / prev.Aclose
+			}
+		case prev.Uclose > 0 && cur.Uclose > 0:
+			value *= shares * (cur.Uclose + div) / prev.Uclose
+		}
+		points[i] = IndexPoint{Date: cur.Date, Value: value}
+	}
+	return points
+}
@@ -0,0 +1,97 @@
+package adjust
+
+import (
+	"defcor/iex"
+	"fmt"
+	"math"
+)
+
+// Finding kinds reported by Verify
+const (
+	// MissingAction is a jump in the stored ratio with no local corporate action
+	MissingAction = "missing"
+	// ExtraAction is a local corporate action the stored ratio does not reflect
+	ExtraAction = "extra"
+	// Mismatch is a jump whose size disagrees with the local corporate action
+	Mismatch = "mismatch"
+)
+
+// Finding is a date where the stored adjusted close disagrees with local factors
+type Finding struct {
+	Symbol     string
+	Date       string
+	Kind       string
+	Observed   float64
+	Expected   float64
+	Suggestion string
+}
+
+// Verify compares the jumps in the stored aclose/uclose ratio with the jumps
+// in the locally derived factors. tol is the relative difference tolerated on
+// top of the rounding error of two decimal prices.
+func Verify(ph *iex.PriceHistory, sh *iex.SplitHistory, dh *iex.DividendHistory, mode Mode, tol float64) []Finding {
+	prices := ph.Prices
+	factors := Factors(prices, sh.Splits, dh.Dividends, "")
+	var findings []Finding
+	for i := 1; i < len(prices); i++ {
+		prev, cur := prices[i-1], prices[i]
+		if prev.Uclose <= 0 || cur.Uclose <= 0 || prev.Aclose <= 0 || cur.Aclose <= 0 {
+			continue
+		}
+		observed := (prev.Aclose / prev.Uclose) / (cur.Aclose / cur.Uclose)
+		expected := factors[i-1].Split / factors[i].Split
+		if mode == TotalReturn {
+			expected = factors[i-1].Total() / factors[i].Total()
+		}
+		allowance := tol + 0.01/prev.Uclose + 0.01/cur.Uclose + 0.01/prev.Aclose + 0.01/cur.Aclose
+		if math.Abs(observed/expected-1) <= allowance {
+			continue
+		}
+		f := Finding{
+			Symbol:   ph.Symbol,
+			Date:     cur.Date,
+			Observed: observed,
+			Expected: expected,
+		}
+		switch {
+		case math.Abs(expected-1) <= allowance:
+			f.Kind = MissingAction
+			f.Suggestion = suggest(observed, prev.Uclose, allowance)
+		case math.Abs(observed-1) <= allowance:
+			f.Kind = ExtraAction
+			f.Suggestion = fmt.Sprintf("remove the split/dividend record with exdate %s", cur.Date)
+		default:
+			f.Kind = Mismatch
+			f.Suggestion = "check the " + suggest(observed/expected, prev.Uclose, allowance)
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// suggest describes the corporate action that would explain a ratio jump
+func suggest(jump, prevClose, allowance float64) string {
+	if to, from, ok := splitRatio(jump, allowance); ok {
+		return fmt.Sprintf("split with toFactor %d, fromFactor %d", to, from)
+	}
+	if jump < 1 && jump > 0.5 {
+		return fmt.Sprintf("dividend of about %.4f", (1-jump)*prevClose)
+	}
+	return fmt.Sprintf("unexplained factor %.6f", jump)
+}
+
+// splitRatio finds small integers with from/to close to jump
+func splitRatio(jump, allowance float64) (to, from int, ok bool) {
+	best := math.Inf(1)
+	for t := 1; t <= 20; t++ {
+		for f := 1; f <= 20; f++ {
+			if t == f {
+				continue
+			}
+			if d := math.Abs(float64(f)/float64(t)/jump - 1); d < best {
+				best, to, from = d, t, f
+			}
+		}
+	}
+	return to, from, best <= allowance
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"defcor/adjust"
+	"defcor/db"
+	"defcor/iex"
+)
+
+// AdjustedPrices derives an adjusted series for symbol from the stored raw
+// bars, splits and dividends as of asOf (empty for the latest data)
+func (app *Application) AdjustedPrices(symbol, asOf string, mode adjust.Mode) (*iex.PriceHistory, error) {
+	ph, sh, dh, err := app.history(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return adjust.Series(ph, sh, dh, asOf, mode), nil
+}
+
+// history loads the raw bars and corporate actions of symbol
+func (app *Application) history(symbol string) (*iex.PriceHistory, *iex.SplitHistory, *iex.DividendHistory, error) {
+	ph, err := app.DB.PriceHistory(symbol, "-infinity", "infinity")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	sh, err := app.DB.SplitHistory(symbol)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dh, err := app.DB.DividendHistory(symbol)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return ph, sh, dh, nil
+}
+
+// Verify checks the stored adjusted closes of symbol against local factors
+func (app *Application) Verify(symbol string, mode adjust.Mode, tol float64) ([]adjust.Finding, error) {
+	ph, sh, dh, err := app.history(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return adjust.Verify(ph, sh, dh, mode, tol), nil
+}
+
+// TotalReturnBase is the value a new total return index starts at
+const TotalReturnBase = 100
+
+// extendTotalReturn brings the stored index of symbol up to date after an
+// ingest when total_return_index is materialized
+func (app *Application) extendTotalReturn(symbol string) error {
+	if !app.tri {
+		return nil
+	}
+	return app.UpdateTotalReturn(symbol, false)
+}
+
+// UpdateTotalReturn extends the stored total return index of symbol from its
+// last point, or rebuilds it from the first bar when full is set. Bars,
+// dividends and splits that land before the last point truncate the index
+// from their date (see db), so extending also recomputes what they changed.
+func (app *Application) UpdateTotalReturn(symbol string, full bool) error {
+	if full {
+		if err := app.DB.DeleteTotalReturn(symbol); err != nil {
+			return err
+		}
+	}
+	last, found, err := app.DB.LastTotalReturn(symbol)
+	if err != nil {
+		return err
+	}
+	from, base := "-infinity", float64(TotalReturnBase)
+	if found {
+		from, base = last.Date, last.Value
+	}
+	ph, err := app.DB.PriceHistory(symbol, from, "infinity")
+	if err != nil {
+		return err
+	}
+	sh, err := app.DB.SplitHistory(symbol)
+	if err != nil {
+		return err
+	}
+	dh, err := app.DB.DividendHistory(symbol)
+	if err != nil {
+		return err
+	}
+	points := adjust.TotalReturnIndex(ph.Prices, sh.Splits, dh.Dividends, base)
+	if found && len(points) > 0 {
+		// the anchor is already stored
+		points = points[1:]
+	}
+	stored := make([]db.IndexPoint, len(points))
+	for i, p := range points {
+		stored[i] = db.IndexPoint{Date: p.Date, Value: p.Value}
+	}
+	return app.DB.InsertTotalReturn(symbol, stored)
+}
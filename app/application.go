@@ -2,16 +2,32 @@ package app
 
 import (
 	"context"
+	"defcor/calendar"
 	"defcor/db"
 	"defcor/iex"
-	"log"
+	"defcor/logging"
+	"defcor/provider"
+	"defcor/quality"
+	"fmt"
 	"time"
 )
 
 // Application combines db with api
 type Application struct {
-	DB  *db.Conn
-	api *iex.APIConnection
+	DB       *db.Conn
+	RunID    string
+	ctx      context.Context
+	log      *logging.Logger
+	api      *iex.APIConnection
+	source   provider.Provider
+	sources  provider.Registry
+	tri      bool
+	cal      *calendar.Calendar
+	lookback string
+	interval time.Duration
+	burst    int
+	staging  bool
+	rules    []quality.Rule
 }
 
 // Environment outlines the environment
@@ -21,6 +37,26 @@ type Environment struct {
 	Lookback string
 	Duration time.Duration
 	DbURL    string
+	// CacheDir enables the on disk response cache when set
+	CacheDir  string
+	CacheMode iex.CacheMode
+	// Archive stores every raw response in raw_payloads
+	Archive bool
+	// StooqURL registers the stooq source when set
+	StooqURL     string
+	StooqSymbols map[string]string
+	// Priority orders sources per data type; iex alone when empty
+	Priority provider.Priority
+	// TotalReturn extends total_return_index after each symbol is seeded
+	TotalReturn bool
+	// Staging validates fetched batches in the staging area before promoting them
+	Staging bool
+	// SharedLimit paces requests with a token bucket in the database shared by
+	// every process instead of the in-process limiter; Burst sizes the bucket
+	SharedLimit bool
+	Burst       int
+	// Logger receives every record of the app, api and database; Default when nil
+	Logger *logging.Logger
 }
 
 // Start creates an app
@@ -30,10 +66,51 @@ func Start(env Environment) (*Application, error) {
 		return nil, err
 	}
 	api := iex.NewAPIConnection(env.Host, env.APIKey, env.Lookback, env.Duration)
-	return &Application{
-		DB:  conn,
-		api: api,
-	}, nil
+	if env.CacheDir != "" {
+		api.SetTransport(iex.NewCachingTransport(env.CacheDir, env.CacheMode))
+	}
+	sources := provider.Registry{"iex": api}
+	if env.StooqURL != "" {
+		sources["stooq"] = provider.NewStooq(env.StooqURL, env.Lookback, env.StooqSymbols)
+	}
+	source, err := provider.NewFallback(sources, env.Priority, "iex")
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	runID := time.Now().UTC().Format("20060102T150405.000")
+	logger := env.Logger.With("run_id", runID)
+	conn.SetLogger(logger)
+	api.SetLogger(logger)
+	source.SetLogger(logger)
+	app := &Application{
+		DB:       conn,
+		RunID:    runID,
+		ctx:      context.Background(),
+		log:      logger,
+		api:      api,
+		source:   source,
+		sources:  sources,
+		tri:      env.TotalReturn,
+		cal:      calendar.NYSE,
+		lookback: env.Lookback,
+		interval: env.Duration,
+		burst:    env.Burst,
+		staging:  env.Staging,
+	}
+	// batches are checked bar by bar; gaps and stale runs need the stored history
+	app.rules, err = quality.Rules(quality.DefaultConfig(), "ohlc", "ohlc_adjusted", "nonnegative", "ratio")
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if env.Archive {
+		api.SetArchiver(payloadArchive{app})
+	}
+	if env.SharedLimit {
+		app.ShareRateLimit()
+	}
+	return app, nil
 }
 
 // End closes the database
@@ -41,13 +118,68 @@ func (app *Application) End() error {
 	return app.DB.Close()
 }
 
+// ShareRateLimit paces iex requests with the database bucket every
+// process shares, keeping the configured interval
+func (app *Application) ShareRateLimit() {
+	app.api.SetLimiter(app.DB.Bucket("iex", app.interval, app.burst))
+}
+
+// SetContext bounds api calls by ctx; symbol loops stop between symbols
+// once it is done, leaving the last symbol's transaction committed
+func (app *Application) SetContext(ctx context.Context) {
+	app.ctx = ctx
+}
+
+// UseSource replaces the provider Seed reads from (iex by default)
+func (app *Application) UseSource(p provider.Provider) {
+	app.source = p
+}
+
+// CompleteStocks upserts the source's stock master for symbols so their
+// history has a security to attach to; stored stocks are updated in place
+func (app *Application) CompleteStocks(symbols []string) error {
+	stks, err := app.source.Stocks(app.ctx)
+	if err != nil {
+		return err
+	}
+	existing, err := app.DB.Stocks()
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]iex.Stock, len(existing))
+	for _, s := range existing {
+		stored[s.Symbol] = s
+	}
+	wanted := make(map[string]bool, len(symbols))
+	for _, symb := range symbols {
+		wanted[symb] = true
+	}
+	var added []iex.Stock
+	for _, s := range stks {
+		if !wanted[s.Symbol] {
+			continue
+		}
+		prev, ok := stored[s.Symbol]
+		if !ok {
+			added = append(added, s)
+			continue
+		}
+		if prev != s {
+			if err := app.DB.UpdateStock(prev, s); err != nil {
+				return fmt.Errorf("updating %s: %w", s.Symbol, err)
+			}
+		}
+	}
+	return app.DB.InsertStocks(added)
+}
+
 // CompletePrices fetches prices and inserts them into the database
 func (app *Application) CompletePrices(symbol string) error {
-	securityPrices, err := app.api.Prices(context.Background(), symbol)
+	securityPrices, err := app.source.Prices(app.ctx, symbol)
 	if err != nil {
 		return err
 	}
-	if err := app.DB.InsertPriceHistory(securityPrices); err != nil {
+	if err := app.ingestPrices(securityPrices); err != nil {
 		return err
 	}
 	return nil
@@ -55,11 +187,11 @@ func (app *Application) CompletePrices(symbol string) error {
 
 // CompleteDividends fetches dividends and inserts them into the database
 func (app *Application) CompleteDividends(symbol string) error {
-	securityDivs, err := app.api.Dividends(context.Background(), symbol)
+	securityDivs, err := app.source.Dividends(app.ctx, symbol)
 	if err != nil {
 		return err
 	}
-	if err := app.DB.InsertDividendHistory(securityDivs); err != nil {
+	if err := app.ingestFetchedDividends(securityDivs); err != nil {
 		return err
 	}
 	return nil
@@ -67,11 +199,11 @@ func (app *Application) CompleteDividends(symbol string) error {
 
 // CompleteSplits fetches splits and inserts them into the database
 func (app *Application) CompleteSplits(symbol string) error {
-	securitySplits, err := app.api.Splits(context.Background(), symbol)
+	securitySplits, err := app.source.Splits(app.ctx, symbol)
 	if err != nil {
 		return err
 	}
-	if err := app.DB.InsertSplitHistory(securitySplits); err != nil {
+	if err := app.ingestFetchedSplits(securitySplits); err != nil {
 		return err
 	}
 	return nil
@@ -80,42 +212,71 @@ func (app *Application) CompleteSplits(symbol string) error {
 // Seed populates the application database
 func (app *Application) Seed(symbols []string) error {
 	for _, symb := range symbols {
-		log.Printf("working on %s...\n", symb)
+		if err := app.ctx.Err(); err != nil {
+			return err
+		}
+		l := app.log.With("symbol", symb)
+		l.Info("seeding")
 		if err := app.CompletePrices(symb); err != nil {
 			return err
 		}
-		log.Println("prices complete")
+		l.Debug("prices complete")
 		if err := app.CompleteDividends(symb); err != nil {
 			return err
 		}
-		log.Println("dividends complete")
+		l.Debug("dividends complete")
 		if err := app.CompleteSplits(symb); err != nil {
 			return err
 		}
-		log.Println("splits complete")
+		l.Debug("splits complete")
+		if app.tri {
+			if err := app.UpdateTotalReturn(symb, false); err != nil {
+				return err
+			}
+			l.Debug("total return index complete")
+		}
 	}
-	log.Println("Done!")
+	app.log.Info("seed complete", "symbols", len(symbols))
 	return nil
 }
 
-// RefreshStocks add only new securities to the stocks table
+// RefreshStocks reconciles the stocks table with the iex security master:
+// changed securities are updated, ended ones deactivated and new ones added
 func (app *Application) RefreshStocks() error {
 	existing, err := app.DB.Stocks()
 	if err != nil {
 		return err
 	}
-	refreshed, err := app.api.AllStocks(context.Background())
+	refreshed, err := app.api.AllStocks(app.ctx)
 	if err != nil {
 		return err
 	}
-	// newStocks := setDifference(existing, refreshed)
-	// if err := app.DB.InsertStocks(newStocks); err != nil {
-	// 	return err
-	// }
-	getSome(existing, refreshed)
-	return nil
+	updates, deletes, additions := iex.Resolve(existing, refreshed)
+	app.logReconcile(updates, deletes, additions)
+	for prev, curr := range updates {
+		if err := app.DB.UpdateStock(prev, curr); err != nil {
+			return fmt.Errorf("updating %s: %w", prev.Symbol, err)
+		}
+	}
+	for _, s := range deletes {
+		if err := app.DB.DeactivateStock(s.Symbol); err != nil {
+			return fmt.Errorf("deactivating %s: %w", s.Symbol, err)
+		}
+	}
+	return app.DB.InsertStocks(additions)
 }
 
-func getSome(A, B iex.StockGroup) {
-	iex.FormatOutput(A, B)
+// logReconcile records how the refreshed reference data differs from the stored stocks
+func (app *Application) logReconcile(updates map[iex.Stock]iex.Stock, deletes, additions []iex.Stock) {
+	for prev, curr := range updates {
+		app.log.Info("stock changed", "symbol", prev.Symbol, "iexid", prev.IexID,
+			"new_symbol", curr.Symbol, "name", prev.Name, "new_name", curr.Name)
+	}
+	for _, s := range deletes {
+		app.log.Info("stock ended", "symbol", s.Symbol, "iexid", s.IexID, "name", s.Name)
+	}
+	for _, s := range additions {
+		app.log.Info("stock added", "symbol", s.Symbol, "iexid", s.IexID, "name", s.Name)
+	}
+	app.log.Info("stocks reconciled", "changed", len(updates), "ended", len(deletes), "added", len(additions))
 }
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"defcor/backfill"
+	"defcor/calendar"
+	"defcor/iex"
+	"sort"
+	"time"
+)
+
+// PlanBackfill finds the holes in the stored history of symbols and the
+// calls that would fill them; callCost is the credit equivalent of one request
+func (app *Application) PlanBackfill(symbols []string, callCost int) ([]backfill.Plan, error) {
+	latest := app.cal.LastSession(time.Now())
+	var plans []backfill.Plan
+	for _, symb := range symbols {
+		dates, err := app.DB.PriceDates(symb)
+		if err != nil {
+			return nil, err
+		}
+		// sessions the vendor already confirmed empty are not gaps to pay for
+		empty, err := app.DB.EmptySessions(symb)
+		if err != nil {
+			return nil, err
+		}
+		dates = append(dates, empty...)
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+		gaps := backfill.Gaps(app.cal, dates, latest)
+		if len(gaps) == 0 {
+			continue
+		}
+		plans = append(plans, backfill.Plan{
+			Symbol: symb,
+			Gaps:   gaps,
+			Calls:  backfill.Calls(app.cal, symb, gaps, latest, callCost),
+		})
+	}
+	return plans, nil
+}
+
+// Backfill executes the calls of plans. Gap sessions none of the calls
+// returned a bar for are remembered as empty, except the latest session which
+// the vendor may not have published yet.
+func (app *Application) Backfill(plans []backfill.Plan) error {
+	latest := app.cal.LastSession(time.Now())
+	for _, plan := range plans {
+		if err := app.ctx.Err(); err != nil {
+			return err
+		}
+		app.log.Info("backfilling", "symbol", plan.Symbol, "gaps", len(plan.Gaps), "calls", len(plan.Calls))
+		returned := make(map[string]bool)
+		for _, call := range plan.Calls {
+			var ph *iex.PriceHistory
+			var err error
+			if call.Date != "" {
+				ph, err = app.api.ChartDate(app.ctx, call.Symbol, call.Date)
+			} else {
+				ph, err = app.api.Chart(app.ctx, call.Symbol, call.Range)
+			}
+			if err != nil {
+				return err
+			}
+			if len(ph.Prices) == 0 {
+				continue
+			}
+			for _, p := range ph.Prices {
+				returned[p.Date] = true
+			}
+			if err := app.ingestPrices(ph); err != nil {
+				return err
+			}
+		}
+		var empty []time.Time
+		for _, g := range plan.Gaps {
+			for _, d := range app.cal.TradingDaysBetween(g.From, g.To) {
+				if d.Before(latest) && !returned[d.Format(calendar.DateFormat)] {
+					empty = append(empty, d)
+				}
+			}
+		}
+		if len(empty) > 0 {
+			app.log.Info("sessions confirmed empty", "symbol", plan.Symbol, "sessions", len(empty))
+		}
+		if err := app.DB.MarkEmptySessions(plan.Symbol, empty); err != nil {
+			return err
+		}
+		if err := app.extendTotalReturn(plan.Symbol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"defcor/db"
+	"fmt"
+)
+
+// Collect fetches prices from a named source into source_prices
+func (app *Application) Collect(source string, symbols []string) error {
+	p, ok := app.sources[source]
+	if !ok {
+		return fmt.Errorf("unknown source %q", source)
+	}
+	for _, symb := range symbols {
+		ph, err := p.Prices(app.ctx, symb)
+		if err != nil {
+			app.log.Warn("skipping symbol", "source", source, "symbol", symb, "err", err)
+			continue
+		}
+		if err := app.DB.InsertSourcePrices(source, ph); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Compare collects every source for symbols, flags deviations of each source
+// against the first one between from and to, and returns the suspect symbols
+func (app *Application) Compare(sources, symbols []string, from, to string, tol db.Tolerance) ([]db.SuspectSymbol, error) {
+	if len(sources) < 2 {
+		return nil, fmt.Errorf("compare needs at least two sources, got %v", sources)
+	}
+	for _, source := range sources {
+		if err := app.Collect(source, symbols); err != nil {
+			return nil, fmt.Errorf("collecting %s: %w", source, err)
+		}
+	}
+	for _, other := range sources[1:] {
+		n, err := app.DB.FlagDiscrepancies(sources[0], other, symbols, from, to, tol)
+		if err != nil {
+			return nil, err
+		}
+		app.log.Info("sources compared", "source", sources[0], "other", other, "discrepancies", n)
+	}
+	return app.DB.SuspectSymbols(sources[0], sources[1:], symbols, from, to)
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"defcor/schedule"
+)
+
+// Schedules are the cron expressions, in exchange time, of the daemon's
+// jobs; an empty expression disables the job
+type Schedules struct {
+	Stocks     string
+	Prices     string
+	Events     string
+	Financials string
+	// EventRange is the dividend and split range of the weekly refresh
+	EventRange string
+	// Quarters is the number of statements the financials job fetches
+	Quarters int
+}
+
+// DefaultSchedules refresh reference data before the open, prices after
+// the close, dividends and splits on saturdays and statements quarterly
+var DefaultSchedules = Schedules{
+	Stocks:     "0 7 * * 1-5",
+	Prices:     "30 17 * * 1-5",
+	Events:     "0 9 * * 6",
+	Financials: "0 10 15 2,5,8,11 *",
+	EventRange: "1m",
+	Quarters:   1,
+}
+
+// Scheduler builds the daemon's jobs. Reference data and prices only run
+// on trading days; the weekly and quarterly jobs run regardless.
+func (app *Application) Scheduler(s Schedules) (*schedule.Scheduler, error) {
+	sched := &schedule.Scheduler{Calendar: app.cal, Locker: app.DB, Log: app.log}
+	add := func(name, expr string, tradingDays bool, run func() error) error {
+		if expr == "" {
+			return nil
+		}
+		cron, err := schedule.ParseCron(expr)
+		if err != nil {
+			return err
+		}
+		sched.Jobs = append(sched.Jobs, schedule.Job{
+			Name:        name,
+			Cron:        cron,
+			TradingDays: tradingDays,
+			Run: func(ctx context.Context) error {
+				app.SetContext(ctx)
+				return run()
+			},
+		})
+		return nil
+	}
+	if err := add("stocks", s.Stocks, true, app.RefreshStocks); err != nil {
+		return nil, err
+	}
+	if err := add("prices", s.Prices, true, func() error {
+		symbols, err := app.DB.Symbols()
+		if err != nil {
+			return err
+		}
+		return app.Update(symbols)
+	}); err != nil {
+		return nil, err
+	}
+	if err := add("events", s.Events, false, func() error {
+		symbols, err := app.DB.Symbols()
+		if err != nil {
+			return err
+		}
+		return app.RefreshEvents(symbols, s.EventRange)
+	}); err != nil {
+		return nil, err
+	}
+	if err := add("financials", s.Financials, false, func() error {
+		symbols, err := app.DB.Symbols()
+		if err != nil {
+			return err
+		}
+		return app.Financials(symbols, s.Quarters)
+	}); err != nil {
+		return nil, err
+	}
+	return sched, nil
+}
+
+// RefreshEvents refetches the dividends and splits of symbols over rng
+func (app *Application) RefreshEvents(symbols []string, rng string) error {
+	for _, symb := range symbols {
+		if err := app.ctx.Err(); err != nil {
+			return err
+		}
+		app.log.Info("refreshing events", "symbol", symb, "range", rng)
+		dh, err := app.api.DividendRange(app.ctx, symb, rng)
+		if err != nil {
+			return err
+		}
+		if err := app.ingestFetchedDividends(dh); err != nil {
+			return err
+		}
+		sh, err := app.api.SplitRange(app.ctx, symb, rng)
+		if err != nil {
+			return err
+		}
+		if err := app.ingestFetchedSplits(sh); err != nil {
+			return err
+		}
+		if err := app.extendTotalReturn(symb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
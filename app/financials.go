@@ -0,0 +1,41 @@
+package app
+
+// CompleteFinancials fetches the last quarterly statements and inserts them into the database
+func (app *Application) CompleteFinancials(symbol string, quarters int) error {
+	income, err := app.api.IncomeStatements(app.ctx, symbol, quarters)
+	if err != nil {
+		return err
+	}
+	income.Symbol = symbol
+	if err := app.DB.InsertIncomeHistory(income); err != nil {
+		return err
+	}
+	balance, err := app.api.BalanceSheets(app.ctx, symbol, quarters)
+	if err != nil {
+		return err
+	}
+	balance.Symbol = symbol
+	if err := app.DB.InsertBalanceHistory(balance); err != nil {
+		return err
+	}
+	cashflow, err := app.api.CashFlows(app.ctx, symbol, quarters)
+	if err != nil {
+		return err
+	}
+	cashflow.Symbol = symbol
+	return app.DB.InsertCashFlowHistory(cashflow)
+}
+
+// Financials refreshes the quarterly statements of symbols
+func (app *Application) Financials(symbols []string, quarters int) error {
+	for _, symb := range symbols {
+		if err := app.ctx.Err(); err != nil {
+			return err
+		}
+		app.log.Info("refreshing financials", "symbol", symb, "quarters", quarters)
+		if err := app.CompleteFinancials(symb, quarters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"defcor/calendar"
+	"defcor/iex"
+	"defcor/metrics"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SLA bounds how stale stored data may get
+type SLA struct {
+	// PriceLag is how many sessions the latest bar may trail the last closed session
+	PriceLag int
+	// DividendAge is how long ago dividends may have last been fetched
+	DividendAge time.Duration
+	// ReportAge is how old the latest income statement may be; symbols
+	// without any statements are not checked
+	ReportAge time.Duration
+	// MaxStale is how many stale symbols are tolerated
+	MaxStale int
+}
+
+// DefaultSLA allows for the post close update, the weekly event refresh and
+// a quarter plus the filing deadline
+var DefaultSLA = SLA{
+	PriceLag:    1,
+	DividendAge: 8 * 24 * time.Hour,
+	ReportAge:   135 * 24 * time.Hour,
+	MaxStale:    0,
+}
+
+// StaleSymbol is a symbol breaking the SLA and why
+type StaleSymbol struct {
+	Symbol  string   `json:"symbol"`
+	Reasons []string `json:"reasons"`
+}
+
+// FreshnessReport is the result of a freshness check
+type FreshnessReport struct {
+	Session  string        `json:"session"`
+	Checked  int           `json:"checked"`
+	Stale    []StaleSymbol `json:"stale"`
+	Breached bool          `json:"breached"`
+}
+
+// Freshness checks every active symbol against sla and the trading calendar
+func (app *Application) Freshness(sla SLA) (*FreshnessReport, error) {
+	all, err := app.DB.FreshnessAll()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := app.cal.LastSession(now)
+	report := &FreshnessReport{Session: session.Format(calendar.DateFormat), Checked: len(all), Stale: []StaleSymbol{}}
+	for _, f := range all {
+		var reasons []string
+		switch {
+		case f.LastPrice == nil:
+			reasons = append(reasons, "no prices")
+		case app.cal.CountTradingDays(*f.LastPrice, session) > sla.PriceLag:
+			reasons = append(reasons, fmt.Sprintf("prices %d sessions behind, last %s",
+				app.cal.CountTradingDays(*f.LastPrice, session), f.LastPrice.Format(calendar.DateFormat)))
+		}
+		switch {
+		case f.DividendsFetched == nil:
+			reasons = append(reasons, "dividends never fetched")
+		case now.Sub(*f.DividendsFetched) > sla.DividendAge:
+			reasons = append(reasons, fmt.Sprintf("dividends last fetched %s", f.DividendsFetched.Format(time.RFC3339)))
+		}
+		if f.LastReport != nil && now.Sub(*f.LastReport) > sla.ReportAge {
+			reasons = append(reasons, fmt.Sprintf("latest report %s", f.LastReport.Format(calendar.DateFormat)))
+		}
+		if len(reasons) > 0 {
+			report.Stale = append(report.Stale, StaleSymbol{f.Symbol, reasons})
+		}
+	}
+	report.Breached = len(report.Stale) > sla.MaxStale
+	metrics.StaleSymbols.Set(float64(len(report.Stale)))
+	return report, nil
+}
+
+// FreshnessHandler serves the freshness report as json, with status 503
+// while the SLA is breached
+func (app *Application) FreshnessHandler(sla SLA) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report, err := app.Freshness(sla)
+		if err != nil {
+			app.log.Error("freshness check failed", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if report.Breached {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// ingestFetchedDividends ingests a freshly fetched batch and records the fetch
+func (app *Application) ingestFetchedDividends(dh *iex.DividendHistory) error {
+	if err := app.ingestDividends(dh); err != nil {
+		return err
+	}
+	return app.DB.RecordFetch(dh.Symbol, iex.KindDividends)
+}
+
+// ingestFetchedSplits ingests a freshly fetched batch and records the fetch
+func (app *Application) ingestFetchedSplits(sh *iex.SplitHistory) error {
+	if err := app.ingestSplits(sh); err != nil {
+		return err
+	}
+	return app.DB.RecordFetch(sh.Symbol, iex.KindSplits)
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"defcor/backfill"
+	"defcor/iex"
+	"path"
+	"time"
+)
+
+// PlannedCall is a single iex request a command would make
+type PlannedCall struct {
+	Symbol   string
+	Endpoint string
+	Credits  int
+}
+
+// CostPlan lists the requests of a command without making them
+type CostPlan struct {
+	Command string
+	Calls   []PlannedCall
+	// Interval is the limiter spacing between requests
+	Interval time.Duration
+}
+
+func (p *CostPlan) add(symbol, endpoint string, credits int) {
+	p.Calls = append(p.Calls, PlannedCall{symbol, endpoint, credits})
+}
+
+// Credits totals the iex credits of the plan
+func (p CostPlan) Credits() int {
+	var n int
+	for _, c := range p.Calls {
+		n += c.Credits
+	}
+	return n
+}
+
+// Wall estimates the wall-clock time the limiter alone imposes on the plan;
+// the first request goes out immediately
+func (p CostPlan) Wall() time.Duration {
+	if len(p.Calls) == 0 {
+		return 0
+	}
+	return time.Duration(len(p.Calls)-1) * p.Interval
+}
+
+// PlanSeed prices the requests Seed makes for symbols, including the
+// reference data refresh that precedes it
+func (app *Application) PlanSeed(symbols []string) CostPlan {
+	plan := CostPlan{Command: "seed", Interval: app.interval}
+	plan.add("", "ref-data/symbols", iex.SymbolsCredits)
+	for _, symb := range symbols {
+		plan.add(symb, path.Join("stock", symb, "chart", app.lookback), iex.ChartCredits(app.lookback))
+		plan.add(symb, path.Join("stock", symb, "dividends", app.lookback), iex.DividendsCredits(app.lookback))
+		plan.add(symb, path.Join("stock", symb, "splits", app.lookback), iex.SplitsCredits(app.lookback))
+	}
+	return plan
+}
+
+// PlanUpdate prices the requests Update makes for symbols
+func (app *Application) PlanUpdate(symbols []string) (CostPlan, error) {
+	ranges, err := app.updateRanges(symbols, app.cal.LastSession(time.Now()))
+	if err != nil {
+		return CostPlan{Command: "update", Interval: app.interval}, err
+	}
+	return app.planCharts(ranges), nil
+}
+
+// planCharts prices the chart call of every update range
+func (app *Application) planCharts(ranges []updateRange) CostPlan {
+	plan := CostPlan{Command: "update", Interval: app.interval}
+	for _, u := range ranges {
+		plan.add(u.symbol, path.Join("stock", u.symbol, "chart", u.rng), iex.ChartCredits(u.rng))
+	}
+	return plan
+}
+
+// PlanBackfillCost prices the calls of backfill plans
+func (app *Application) PlanBackfillCost(plans []backfill.Plan) CostPlan {
+	plan := CostPlan{Command: "backfill", Interval: app.interval}
+	for _, p := range plans {
+		for _, c := range p.Calls {
+			plan.add(c.Symbol, path.Join("stock", c.Symbol, c.Endpoint()), c.Credits)
+		}
+	}
+	return plan
+}
+
+// PlanFinancials prices the requests Financials makes for symbols
+func (app *Application) PlanFinancials(symbols []string, quarters int) CostPlan {
+	plan := CostPlan{Command: "financials", Interval: app.interval}
+	for _, symb := range symbols {
+		for _, stmt := range []string{"income", "balance-sheet", "cash-flow"} {
+			plan.add(symb, path.Join("stock", symb, stmt), iex.StatementsCredits(quarters))
+		}
+	}
+	return plan
+}
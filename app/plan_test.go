@@ -0,0 +1,89 @@
+package app
+
+import (
+	"defcor/calendar"
+	"testing"
+	"time"
+)
+
+func TestPlanSeed(t *testing.T) {
+	app := &Application{lookback: "1y", interval: 100 * time.Millisecond}
+	plan := app.PlanSeed([]string{"AAPL", "IBM"})
+	// symbols, then per symbol 250 bars, three quarterly dividends and one split
+	want := []PlannedCall{
+		{"", "ref-data/symbols", 100},
+		{"AAPL", "stock/AAPL/chart/1y", 2500},
+		{"AAPL", "stock/AAPL/dividends/1y", 30},
+		{"AAPL", "stock/AAPL/splits/1y", 10},
+		{"IBM", "stock/IBM/chart/1y", 2500},
+		{"IBM", "stock/IBM/dividends/1y", 30},
+		{"IBM", "stock/IBM/splits/1y", 10},
+	}
+	if len(plan.Calls) != len(want) {
+		t.Fatalf("got %+v, want %+v", plan.Calls, want)
+	}
+	for i, c := range plan.Calls {
+		if c != want[i] {
+			t.Errorf("call %d: got %+v, want %+v", i, c, want[i])
+		}
+	}
+	if plan.Credits() != 5180 {
+		t.Errorf("Credits() = %d, want 5180", plan.Credits())
+	}
+	if plan.Wall() != 600*time.Millisecond {
+		t.Errorf("Wall() = %v, want 600ms", plan.Wall())
+	}
+}
+
+func TestPlanUpdate(t *testing.T) {
+	app := &Application{lookback: "5y", interval: 100 * time.Millisecond, cal: calendar.NYSE}
+	day := func(s string) time.Time {
+		d, err := calendar.ParseDate(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	latest := map[string]time.Time{
+		"AAPL": day("2021-01-08"),
+		"IBM":  day("2021-01-05"),
+		// december's 22 sessions and january's first five
+		"MSFT": day("2020-11-30"),
+	}
+	ranges := app.rangesSince(latest, []string{"AAPL", "IBM", "MSFT", "NEWCO"}, day("2021-01-08"))
+	plan := app.planCharts(ranges)
+	want := []PlannedCall{
+		{"IBM", "stock/IBM/chart/5d", 50},
+		{"MSFT", "stock/MSFT/chart/3m", 600},
+		{"NEWCO", "stock/NEWCO/chart/5y", 12550},
+	}
+	if len(plan.Calls) != len(want) {
+		t.Fatalf("got %+v, want %+v", plan.Calls, want)
+	}
+	for i, c := range plan.Calls {
+		if c != want[i] {
+			t.Errorf("call %d: got %+v, want %+v", i, c, want[i])
+		}
+	}
+	if plan.Command != "update" || plan.Credits() != 13200 || plan.Wall() != 200*time.Millisecond {
+		t.Errorf("%s plan: %d credits over %v, want update, 13200 over 200ms", plan.Command, plan.Credits(), plan.Wall())
+	}
+}
+
+func TestWall(t *testing.T) {
+	tests := []struct {
+		calls int
+		want  time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{2, time.Second},
+		{61, time.Minute},
+	}
+	for _, tt := range tests {
+		plan := CostPlan{Calls: make([]PlannedCall, tt.calls), Interval: time.Second}
+		if got := plan.Wall(); got != tt.want {
+			t.Errorf("%d calls: Wall() = %v, want %v", tt.calls, got, tt.want)
+		}
+	}
+}
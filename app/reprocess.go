@@ -0,0 +1,98 @@
+package app
+
+import (
+	"defcor/iex"
+	"fmt"
+	"time"
+)
+
+// payloadArchive files raw responses under the application's run id
+type payloadArchive struct {
+	app *Application
+}
+
+// Archive satisfies iex.Archiver
+func (pa payloadArchive) Archive(endpoint string, fetched time.Time, payload []byte) error {
+	return pa.app.DB.ArchivePayload(pa.app.RunID, endpoint, fetched, payload)
+}
+
+// Reprocess decodes archived payloads with the current iex types and re-runs the inserts.
+// With no run ids the latest payload of every endpoint is used.
+func (app *Application) Reprocess(runIDs ...string) error {
+	if len(runIDs) == 0 {
+		runIDs = []string{""}
+	}
+	for _, runID := range runIDs {
+		ids, err := app.DB.PayloadIDs(runID)
+		if err != nil {
+			return err
+		}
+		app.log.Info("reprocessing", "payloads", len(ids), "source_run", runID)
+		for _, id := range ids {
+			if err := app.reprocessPayload(id); err != nil {
+				return fmt.Errorf("payload %d: %w", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (app *Application) reprocessPayload(id int64) error {
+	rp, err := app.DB.Payload(id)
+	if err != nil {
+		return err
+	}
+	kind, symbol, err := iex.ParseEndpoint(rp.Endpoint)
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case iex.KindPrices:
+		ph, err := iex.DecodePrices(symbol, rp.Payload)
+		if err != nil {
+			return err
+		}
+		if err := app.ingestPrices(ph); err != nil {
+			return err
+		}
+		return app.extendTotalReturn(symbol)
+	case iex.KindDividends:
+		dh, err := iex.DecodeDividends(symbol, rp.Payload)
+		if err != nil {
+			return err
+		}
+		if err := app.ingestDividends(dh); err != nil {
+			return err
+		}
+		return app.extendTotalReturn(symbol)
+	case iex.KindSplits:
+		sh, err := iex.DecodeSplits(symbol, rp.Payload)
+		if err != nil {
+			return err
+		}
+		if err := app.ingestSplits(sh); err != nil {
+			return err
+		}
+		return app.extendTotalReturn(symbol)
+	case iex.KindIncome:
+		ih, err := iex.DecodeIncome(symbol, rp.Payload)
+		if err != nil {
+			return err
+		}
+		return app.DB.InsertIncomeHistory(ih)
+	case iex.KindBalance:
+		bh, err := iex.DecodeBalance(symbol, rp.Payload)
+		if err != nil {
+			return err
+		}
+		return app.DB.InsertBalanceHistory(bh)
+	case iex.KindCashFlow:
+		ch, err := iex.DecodeCashFlow(symbol, rp.Payload)
+		if err != nil {
+			return err
+		}
+		return app.DB.InsertCashFlowHistory(ch)
+	}
+	app.log.Warn("payload skipped", "endpoint", rp.Endpoint, "kind", kind, "reason", "no inserts for kind")
+	return nil
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"defcor/iex"
+	"defcor/rotation"
+	"fmt"
+	"time"
+)
+
+// Rotation spreads deep refreshes of the universe across days
+type Rotation struct {
+	Spec rotation.Spec
+	// Budget caps the credits of a run, daily updates included; zero is
+	// unlimited, which only a sharded spec allows
+	Budget int
+	// Range is the chart, dividend and split range of a deep refresh
+	Range string
+}
+
+// PlanRotation prices the daily update of every symbol and selects the
+// symbols to deep refresh with whatever budget the update leaves
+func (app *Application) PlanRotation(symbols []string, r Rotation) (CostPlan, []rotation.Candidate, error) {
+	if r.Spec.Cycle == 0 && r.Budget <= 0 {
+		// without a budget oldest first would deep refresh the whole universe
+		return CostPlan{}, nil, fmt.Errorf("rotation %s needs a credit budget", r.Spec)
+	}
+	update, err := app.PlanUpdate(symbols)
+	if err != nil {
+		return update, nil, err
+	}
+	budget := r.Budget
+	if budget > 0 {
+		budget -= update.Credits()
+		if budget <= 0 {
+			return update, nil, nil
+		}
+	}
+	refreshed, err := app.DB.RefreshTimes()
+	if err != nil {
+		return update, nil, err
+	}
+	credits := iex.ChartCredits(r.Range) + iex.DividendsCredits(r.Range) + iex.SplitsCredits(r.Range)
+	cands := make([]rotation.Candidate, len(symbols))
+	for i, symb := range symbols {
+		cands[i] = rotation.Candidate{Symbol: symb, Refreshed: refreshed[symb], Credits: credits}
+	}
+	return update, rotation.Select(cands, r.Spec, budget), nil
+}
+
+// SeedRotation updates every symbol cheaply, then deep refreshes the
+// symbols the rotation selects for today
+func (app *Application) SeedRotation(symbols []string, r Rotation) error {
+	update, deep, err := app.PlanRotation(symbols, r)
+	if err != nil {
+		return err
+	}
+	app.log.Info("rotation planned", "spec", r.Spec, "update_credits", update.Credits(),
+		"deep", len(deep), "symbols", len(symbols), "range", r.Range)
+	if err := app.Update(symbols); err != nil {
+		return err
+	}
+	for _, c := range deep {
+		if err := app.ctx.Err(); err != nil {
+			return err
+		}
+		app.log.Info("deep refreshing", "symbol", c.Symbol, "range", r.Range)
+		if err := app.DeepRefresh(c.Symbol, r.Range); err != nil {
+			return err
+		}
+	}
+	app.log.Info("rotation complete", "deep", len(deep))
+	return nil
+}
+
+// DeepRefresh refetches prices, dividends and splits of symbol over rng and
+// records the refresh
+func (app *Application) DeepRefresh(symbol, rng string) error {
+	ph, err := app.api.Chart(app.ctx, symbol, rng)
+	if err != nil {
+		return err
+	}
+	if err := app.ingestPrices(ph); err != nil {
+		return err
+	}
+	dh, err := app.api.DividendRange(app.ctx, symbol, rng)
+	if err != nil {
+		return err
+	}
+	if err := app.ingestFetchedDividends(dh); err != nil {
+		return err
+	}
+	sh, err := app.api.SplitRange(app.ctx, symbol, rng)
+	if err != nil {
+		return err
+	}
+	if err := app.ingestFetchedSplits(sh); err != nil {
+		return err
+	}
+	// revised history may move every point of the index
+	if app.tri {
+		if err := app.UpdateTotalReturn(symbol, true); err != nil {
+			return err
+		}
+	}
+	return app.DB.MarkRefreshed(symbol, time.Now())
+}
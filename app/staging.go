@@ -0,0 +1,118 @@
+package app
+
+import (
+	"defcor/iex"
+	"defcor/quality"
+	"encoding/json"
+	"strings"
+)
+
+// rejections maps the row numbers of a batch to the reasons they failed validation;
+// only error severity issues hold a row back
+func rejections(issues []quality.Issue, rowsByDate map[string][]int) map[int]string {
+	rejected := make(map[int]string)
+	for _, is := range issues {
+		if is.Severity != quality.Error {
+			continue
+		}
+		for _, row := range rowsByDate[is.Date] {
+			reason := is.Rule + ": " + is.Detail
+			if prev, ok := rejected[row]; ok {
+				reason = strings.Join([]string{prev, reason}, "; ")
+			}
+			rejected[row] = reason
+		}
+	}
+	return rejected
+}
+
+// stage lands rows in the staging area
+func (app *Application) stage(kind, symbol string, n int, row func(i int) interface{}) (int64, error) {
+	rows := make([][]byte, n)
+	for i := range rows {
+		data, err := json.Marshal(row(i))
+		if err != nil {
+			return 0, err
+		}
+		rows[i] = data
+	}
+	return app.DB.StageBatch(app.RunID, kind, symbol, rows)
+}
+
+// ingestPrices stages, validates and promotes a fetched price batch
+func (app *Application) ingestPrices(ph *iex.PriceHistory) error {
+	if !app.staging {
+		return app.DB.InsertPriceHistory(ph)
+	}
+	batchID, err := app.stage(iex.KindPrices, ph.Symbol, len(ph.Prices), func(i int) interface{} { return ph.Prices[i] })
+	if err != nil {
+		return err
+	}
+	rowsByDate := make(map[string][]int)
+	for i, p := range ph.Prices {
+		rowsByDate[p.Date] = append(rowsByDate[p.Date], i)
+	}
+	rejected := rejections(quality.Check(ph, app.rules), rowsByDate)
+	accepted := &iex.PriceHistory{Symbol: ph.Symbol}
+	for i, p := range ph.Prices {
+		if _, bad := rejected[i]; !bad {
+			accepted.Prices = append(accepted.Prices, p)
+		}
+	}
+	app.logQuarantine(iex.KindPrices, ph.Symbol, batchID, rejected)
+	return app.DB.PromoteBatch(batchID, ph.Symbol, accepted, rejected)
+}
+
+// ingestDividends stages, validates and promotes a fetched dividend batch
+func (app *Application) ingestDividends(dh *iex.DividendHistory) error {
+	if !app.staging || dh.IsEmpty() {
+		return app.DB.InsertDividendHistory(dh)
+	}
+	batchID, err := app.stage(iex.KindDividends, dh.Symbol, len(dh.Dividends), func(i int) interface{} { return dh.Dividends[i] })
+	if err != nil {
+		return err
+	}
+	rowsByDate := make(map[string][]int)
+	for i, d := range dh.Dividends {
+		rowsByDate[d.ExDate] = append(rowsByDate[d.ExDate], i)
+	}
+	rejected := rejections(quality.CheckDividends(dh), rowsByDate)
+	accepted := &iex.DividendHistory{Symbol: dh.Symbol}
+	for i, d := range dh.Dividends {
+		if _, bad := rejected[i]; !bad {
+			accepted.Dividends = append(accepted.Dividends, d)
+		}
+	}
+	app.logQuarantine(iex.KindDividends, dh.Symbol, batchID, rejected)
+	return app.DB.PromoteBatch(batchID, dh.Symbol, accepted, rejected)
+}
+
+// ingestSplits stages, validates and promotes a fetched split batch
+func (app *Application) ingestSplits(sh *iex.SplitHistory) error {
+	if !app.staging || sh.IsEmpty() {
+		return app.DB.InsertSplitHistory(sh)
+	}
+	batchID, err := app.stage(iex.KindSplits, sh.Symbol, len(sh.Splits), func(i int) interface{} { return sh.Splits[i] })
+	if err != nil {
+		return err
+	}
+	rowsByDate := make(map[string][]int)
+	for i, s := range sh.Splits {
+		rowsByDate[s.ExDate] = append(rowsByDate[s.ExDate], i)
+	}
+	rejected := rejections(quality.CheckSplits(sh), rowsByDate)
+	accepted := &iex.SplitHistory{Symbol: sh.Symbol}
+	for i, s := range sh.Splits {
+		if _, bad := rejected[i]; !bad {
+			accepted.Splits = append(accepted.Splits, s)
+		}
+	}
+	app.logQuarantine(iex.KindSplits, sh.Symbol, batchID, rejected)
+	return app.DB.PromoteBatch(batchID, sh.Symbol, accepted, rejected)
+}
+
+func (app *Application) logQuarantine(kind, symbol string, batchID int64, rejected map[int]string) {
+	if len(rejected) > 0 {
+		app.log.Warn("rows quarantined", "symbol", symbol, "kind", kind, "rows", len(rejected), "batch", batchID)
+	}
+}
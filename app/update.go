@@ -0,0 +1,70 @@
+package app
+
+import (
+	"defcor/calendar"
+	"defcor/iex"
+	"time"
+)
+
+// updateRange is the chart range that brings one symbol up to date
+type updateRange struct {
+	symbol string
+	rng    string
+}
+
+// updateRanges picks the chart range each symbol needs to reach session;
+// symbols already up to date are left out and symbols without bars get the
+// default lookback.
+func (app *Application) updateRanges(symbols []string, session time.Time) ([]updateRange, error) {
+	latest, err := app.DB.LatestPriceDates()
+	if err != nil {
+		return nil, err
+	}
+	return app.rangesSince(latest, symbols, session), nil
+}
+
+// rangesSince picks the ranges from the latest stored bar of each symbol
+func (app *Application) rangesSince(latest map[string]time.Time, symbols []string, session time.Time) []updateRange {
+	var ranges []updateRange
+	for _, symb := range symbols {
+		rng := app.lookback
+		if last, ok := latest[symb]; ok {
+			missing := app.cal.CountTradingDays(last, session)
+			if missing == 0 {
+				continue
+			}
+			rng = iex.RangeFor(missing)
+		}
+		ranges = append(ranges, updateRange{symb, rng})
+	}
+	return ranges
+}
+
+// Update fetches only the sessions each symbol is missing since its latest
+// stored bar, using the exchange calendar to skip holidays and weekends.
+// Symbols without any bars get the default lookback.
+func (app *Application) Update(symbols []string) error {
+	session := app.cal.LastSession(time.Now())
+	ranges, err := app.updateRanges(symbols, session)
+	if err != nil {
+		return err
+	}
+	for _, u := range ranges {
+		if err := app.ctx.Err(); err != nil {
+			return err
+		}
+		app.log.Info("updating", "symbol", u.symbol, "range", u.rng)
+		ph, err := app.api.Chart(app.ctx, u.symbol, u.rng)
+		if err != nil {
+			return err
+		}
+		if err := app.ingestPrices(ph); err != nil {
+			return err
+		}
+		if err := app.extendTotalReturn(u.symbol); err != nil {
+			return err
+		}
+	}
+	app.log.Info("up to date", "session", session.Format(calendar.DateFormat), "updated", len(ranges))
+	return nil
+}
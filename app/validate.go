@@ -0,0 +1,51 @@
+package app
+
+import (
+	"database/sql"
+	"defcor/db"
+	"defcor/quality"
+	"math"
+)
+
+// Validate runs rules over the stored bars of symbols between from and to,
+// records the findings in data_quality_issues and counts them by severity
+func (app *Application) Validate(symbols []string, from, to string, rules []quality.Rule) (map[quality.Severity]int, error) {
+	names := quality.RuleNames(rules)
+	counts := make(map[quality.Severity]int)
+	for _, symb := range symbols {
+		ph, err := app.DB.PriceHistory(symb, from, to)
+		if err != nil {
+			return nil, err
+		}
+		issues := quality.Check(ph, rules)
+		if err := app.DB.ReplaceIssues(symb, from, to, names, storedIssues(issues)); err != nil {
+			return nil, err
+		}
+		for _, is := range issues {
+			counts[is.Severity]++
+		}
+		if len(issues) > 0 {
+			app.log.Info("issues found", "symbol", symb, "issues", len(issues))
+		}
+	}
+	return counts, nil
+}
+
+// storedIssues converts findings into data_quality_issues rows
+func storedIssues(issues []quality.Issue) []db.Issue {
+	stored := make([]db.Issue, len(issues))
+	for i, is := range issues {
+		stored[i] = db.Issue{
+			Date:     is.Date,
+			Rule:     is.Rule,
+			Severity: string(is.Severity),
+			Detail:   is.Detail,
+			// numeric columns take neither NaN nor infinities
+			Value: sql.NullFloat64{
+				Float64: is.Value,
+				Valid:   !math.IsNaN(is.Value) && !math.IsInf(is.Value, 0),
+			},
+		}
+	}
+	return stored
+}
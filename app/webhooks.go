@@ -0,0 +1,58 @@
+package app
+
+import (
+	"defcor/webhook"
+	"net/http"
+	"time"
+)
+
+// DeliverWebhooks posts queued webhook deliveries until the app context is
+// done, retrying failures with the backoff of w and logging every attempt.
+// Any number of delivery workers may run; each delivery is leased to one.
+func (app *Application) DeliverWebhooks(w Worker, timeout time.Duration) error {
+	sender := &webhook.Sender{Client: &http.Client{Timeout: timeout}}
+	for app.ctx.Err() == nil {
+		d, err := app.DB.ClaimDelivery(w.Name, w.Lease)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			if w.Once {
+				return nil
+			}
+			app.sleep(w.Idle)
+			continue
+		}
+		l := app.log.With("delivery", d.ID, "webhook", d.WebhookID, "kind", d.Kind, "attempt", d.Attempts)
+		start := time.Now()
+		status, err := sender.Send(app.ctx, d.URL, d.Secret, d.Kind, d.ID, d.Payload)
+		took := time.Since(start)
+		if err != nil && app.ctx.Err() != nil {
+			// interrupted by shutdown rather than refused
+			return app.DB.ReleaseDelivery(d.ID, w.Name)
+		}
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		}
+		if err := app.DB.RecordAttempt(d.ID, d.Attempts, status, took, reason); err != nil {
+			return err
+		}
+		if err != nil {
+			backoff := w.Backoff << uint(d.Attempts-1)
+			if backoff > maxBackoff || backoff <= 0 {
+				backoff = maxBackoff
+			}
+			l.Warn("webhook delivery failed", "status", status, "retry_in", backoff, "err", err)
+			if err := app.DB.FailDelivery(d.ID, w.Name, backoff, reason); err != nil {
+				return err
+			}
+			continue
+		}
+		l.Info("webhook delivered", "status", status, "duration", took)
+		if err := app.DB.CompleteDelivery(d.ID, w.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
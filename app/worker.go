@@ -0,0 +1,159 @@
+package app
+
+import (
+	"defcor/db"
+	"defcor/iex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// KindFinancials is the job kind fetching all three statements; its range
+// is the number of quarters
+const KindFinancials = "financials"
+
+// JobKinds lists the kinds of work the queue accepts
+var JobKinds = []string{iex.KindPrices, iex.KindDividends, iex.KindSplits, KindFinancials}
+
+// Worker configures a queue consumer
+type Worker struct {
+	Name string
+	// Lease is how long a claimed job is held before others may retry it
+	Lease time.Duration
+	// Backoff is the delay before the first retry, doubled for each further one
+	Backoff time.Duration
+	// Idle is the pause between polls of an empty queue
+	Idle time.Duration
+	// Once stops the worker when the queue has nothing runnable
+	Once bool
+}
+
+// maxBackoff caps the retry delay of a job
+const maxBackoff = time.Hour
+
+// Enqueue queues a job per symbol and kind, skipping tasks already pending
+func (app *Application) Enqueue(symbols, kinds []string, rng string, maxAttempts int) (int, error) {
+	for _, kind := range kinds {
+		if !validKind(kind) {
+			return 0, fmt.Errorf("unknown job kind %q", kind)
+		}
+	}
+	var n int
+	for _, symb := range symbols {
+		for _, kind := range kinds {
+			added, err := app.DB.EnqueueJob(symb, kind, rng, maxAttempts)
+			if err != nil {
+				return n, err
+			}
+			if added {
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+func validKind(kind string) bool {
+	for _, k := range JobKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Work claims and runs queued jobs until the app context is done. Workers
+// should share the rate limit (see ShareRateLimit) so any number of them
+// together stay within it. Inserts are upserts, so a job rerun after its
+// lease expired does no harm.
+func (app *Application) Work(w Worker) error {
+	for app.ctx.Err() == nil {
+		job, err := app.DB.ClaimJob(w.Name, w.Lease)
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			if w.Once {
+				return nil
+			}
+			app.sleep(w.Idle)
+			continue
+		}
+		l := app.log.With("job", job.ID, "kind", job.Kind, "symbol", job.Symbol, "range", job.Range)
+		l.Info("job claimed", "attempt", job.Attempts, "max_attempts", job.MaxAttempts)
+		if err := app.runJob(job); err != nil {
+			// interrupted by shutdown rather than failed
+			if app.ctx.Err() != nil {
+				return app.DB.ReleaseJob(job.ID, w.Name)
+			}
+			backoff := w.Backoff << uint(job.Attempts-1)
+			if backoff > maxBackoff || backoff <= 0 {
+				backoff = maxBackoff
+			}
+			l.Error("job failed", "retry_in", backoff, "err", err)
+			if err := app.DB.FailJob(job.ID, w.Name, backoff, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := app.DB.CompleteJob(job.ID, w.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sleep waits for d unless the app context ends first
+func (app *Application) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return app.ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-app.ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+func (app *Application) runJob(job *db.Job) error {
+	if err := app.fetchJob(job); err != nil {
+		return err
+	}
+	if job.Kind == KindFinancials {
+		return nil
+	}
+	return app.extendTotalReturn(job.Symbol)
+}
+
+func (app *Application) fetchJob(job *db.Job) error {
+	switch job.Kind {
+	case iex.KindPrices:
+		ph, err := app.api.Chart(app.ctx, job.Symbol, job.Range)
+		if err != nil {
+			return err
+		}
+		return app.ingestPrices(ph)
+	case iex.KindDividends:
+		dh, err := app.api.DividendRange(app.ctx, job.Symbol, job.Range)
+		if err != nil {
+			return err
+		}
+		return app.ingestFetchedDividends(dh)
+	case iex.KindSplits:
+		sh, err := app.api.SplitRange(app.ctx, job.Symbol, job.Range)
+		if err != nil {
+			return err
+		}
+		return app.ingestFetchedSplits(sh)
+	case KindFinancials:
+		quarters, err := strconv.Atoi(job.Range)
+		if err != nil {
+			return fmt.Errorf("financials range %q is not a number of quarters", job.Range)
+		}
+		return app.CompleteFinancials(job.Symbol, quarters)
+	}
+	return fmt.Errorf("unknown job kind %q", job.Kind)
+}
@@ -0,0 +1,119 @@
+package backfill
+
+import (
+	"defcor/calendar"
+	"defcor/iex"
+	"time"
+)
+
+// Gap is a run of consecutive sessions without a stored bar
+type Gap struct {
+	From time.Time
+	To   time.Time
+	Days int
+}
+
+// Call is a single iex request planned to fill gaps
+type Call struct {
+	Symbol string
+	// Date is set for chart/date calls, Range for chart/{range} calls
+	Date    string
+	Range   string
+	Bars    int
+	Credits int
+}
+
+// Endpoint renders the chart path of the call
+func (c Call) Endpoint() string {
+	if c.Date != "" {
+		return "chart/date/" + c.Date
+	}
+	return "chart/" + c.Range
+}
+
+// Plan lists the gaps of one symbol and the calls chosen to fill them
+type Plan struct {
+	Symbol string
+	Gaps   []Gap
+	Calls  []Call
+}
+
+// Credits totals the planned iex credits
+func (p Plan) Credits() int {
+	var n int
+	for _, c := range p.Calls {
+		n += c.Credits
+	}
+	return n
+}
+
+// Gaps finds the sessions between the first stored bar and through that have no bar.
+// have must be sorted.
+func Gaps(cal *calendar.Calendar, have []time.Time, through time.Time) []Gap {
+	if len(have) == 0 {
+		return nil
+	}
+	stored := make(map[time.Time]bool, len(have))
+	for _, d := range have {
+		stored[calendar.Date(d)] = true
+	}
+	var gaps []Gap
+	var open *Gap
+	for _, d := range cal.TradingDaysBetween(have[0], through) {
+		if stored[d] {
+			open = nil
+			continue
+		}
+		if open == nil {
+			gaps = append(gaps, Gap{From: d})
+			open = &gaps[len(gaps)-1]
+		}
+		open.To = d
+		open.Days++
+	}
+	return gaps
+}
+
+// Calls picks the cheapest mix of chart/date calls for older gaps and one
+// chart/{range} call reaching back over the newer ones. Range calls always
+// end at the latest session, so one covers every gap after its start.
+// callCost is the credit equivalent charged per request to favour fewer calls.
+func Calls(cal *calendar.Calendar, symbol string, gaps []Gap, latest time.Time, callCost int) []Call {
+	if len(gaps) == 0 {
+		return nil
+	}
+	// daily[i] is the cost of filling gaps[:i] day by day
+	daily := make([]int, len(gaps)+1)
+	for i, g := range gaps {
+		daily[i+1] = daily[i] + g.Days*(iex.ChartCreditsPerBar+callCost)
+	}
+	best, split, rng := daily[len(gaps)], len(gaps), ""
+	for i, g := range gaps {
+		r := iex.RangeFor(cal.CountTradingDays(g.From, latest) + 1)
+		cost := daily[i] + iex.RangeDays(r)*iex.ChartCreditsPerBar + callCost
+		if cost < best {
+			best, split, rng = cost, i, r
+		}
+	}
+	var calls []Call
+	for _, g := range gaps[:split] {
+		for _, d := range cal.TradingDaysBetween(g.From, g.To) {
+			calls = append(calls, Call{
+				Symbol:  symbol,
+				Date:    d.Format("20060102"),
+				Bars:    1,
+				Credits: iex.ChartCreditsPerBar,
+			})
+		}
+	}
+	if rng != "" {
+		bars := iex.RangeDays(rng)
+		calls = append(calls, Call{
+			Symbol:  symbol,
+			Range:   rng,
+			Bars:    bars,
+			Credits: bars * iex.ChartCreditsPerBar,
+		})
+	}
+	return calls
+}
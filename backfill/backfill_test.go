@@ -0,0 +1,93 @@
+package backfill
+
+import (
+	"defcor/calendar"
+	"testing"
+	"time"
+)
+
+func dates(ss ...string) []time.Time {
+	ds := make([]time.Time, len(ss))
+	for i, s := range ss {
+		d, err := calendar.ParseDate(s)
+		if err != nil {
+			panic(err)
+		}
+		ds[i] = d
+	}
+	return ds
+}
+
+func TestGaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    []time.Time
+		through string
+		want    [][2]string
+		days    []int
+	}{
+		{
+			name:    "no bars",
+			through: "2021-01-12",
+		},
+		{
+			name:    "complete",
+			have:    dates("2021-01-04", "2021-01-05", "2021-01-06"),
+			through: "2021-01-06",
+		},
+		{
+			name:    "gaps span weekends and holidays",
+			have:    dates("2021-01-04", "2021-01-05", "2021-01-08", "2021-01-12"),
+			through: "2021-01-20",
+			want:    [][2]string{{"2021-01-06", "2021-01-07"}, {"2021-01-11", "2021-01-11"}, {"2021-01-13", "2021-01-20"}},
+			days:    []int{2, 1, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gaps := Gaps(calendar.NYSE, tt.have, dates(tt.through)[0])
+			if len(gaps) != len(tt.want) {
+				t.Fatalf("got %v, want %v", gaps, tt.want)
+			}
+			for i, g := range gaps {
+				from, to := g.From.Format(calendar.DateFormat), g.To.Format(calendar.DateFormat)
+				if from != tt.want[i][0] || to != tt.want[i][1] || g.Days != tt.days[i] {
+					t.Errorf("gap %d: %s..%s (%d days), want %s..%s (%d days)",
+						i, from, to, g.Days, tt.want[i][0], tt.want[i][1], tt.days[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalls(t *testing.T) {
+	latest := dates("2021-01-12")[0]
+	old := Gap{From: dates("2020-06-01")[0], To: dates("2020-06-01")[0], Days: 1}
+	recent := Gap{From: dates("2021-01-06")[0], To: latest, Days: 5}
+	tests := []struct {
+		name string
+		gaps []Gap
+		want []string
+	}{
+		{"nothing missing", nil, nil},
+		{"old gap is fetched by date", []Gap{old}, []string{"chart/date/20200601"}},
+		{"recent gap is fetched by range", []Gap{recent}, []string{"chart/5d"}},
+		{"mixed", []Gap{old, recent}, []string{"chart/date/20200601", "chart/5d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := Calls(calendar.NYSE, "AAPL", tt.gaps, latest, 20)
+			if len(calls) != len(tt.want) {
+				t.Fatalf("got %v, want %v", calls, tt.want)
+			}
+			for i, c := range calls {
+				if c.Endpoint() != tt.want[i] {
+					t.Errorf("call %d: %s, want %s", i, c.Endpoint(), tt.want[i])
+				}
+				if c.Symbol != "AAPL" || c.Credits != c.Bars*10 {
+					t.Errorf("call %d: %+v", i, c)
+				}
+			}
+		})
+	}
+}
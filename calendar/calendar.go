@@ -0,0 +1,184 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// Calendar knows the sessions of an exchange. Holidays and early closes come
+// from rules; Closed, Open and EarlyClose override individual dates.
+type Calendar struct {
+	Name     string
+	Location *time.Location
+	// Close and EarlyCloseTime are the regular and shortened session ends
+	Close          time.Duration
+	EarlyCloseTime time.Duration
+	// Closed lists unscheduled closures, e.g. national days of mourning
+	Closed map[string]string
+	// Open lists rule based holidays or early closes that were regular sessions
+	Open map[string]bool
+	// EarlyClose lists shortened sessions the rules miss
+	EarlyClose map[string]bool
+}
+
+// DateFormat is the layout of dates used throughout the calendar
+const DateFormat = "2006-01-02"
+
+// NYSE is the New York Stock Exchange calendar
+var NYSE = newUS("NYSE")
+
+// Nasdaq follows the same holiday schedule as the NYSE
+var Nasdaq = newUS("Nasdaq")
+
+func newUS(name string) *Calendar {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.FixedZone("EST", -5*60*60)
+	}
+	return &Calendar{
+		Name:           name,
+		Location:       loc,
+		Close:          16 * time.Hour,
+		EarlyCloseTime: 13 * time.Hour,
+		Closed: map[string]string{
+			"1994-04-27": "Nixon Day of Mourning",
+			"2001-09-11": "September 11",
+			"2001-09-12": "September 11",
+			"2001-09-13": "September 11",
+			"2001-09-14": "September 11",
+			"2004-06-11": "Reagan Day of Mourning",
+			"2007-01-02": "Ford Day of Mourning",
+			"2012-10-29": "Hurricane Sandy",
+			"2012-10-30": "Hurricane Sandy",
+			"2018-12-05": "Bush Day of Mourning",
+			"2025-01-09": "Carter Day of Mourning",
+		},
+		Open:       map[string]bool{},
+		EarlyClose: map[string]bool{},
+	}
+}
+
+// Date truncates t to midnight UTC of its calendar day
+func Date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ParseDate reads a yyyy-mm-dd date
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse(DateFormat, s)
+}
+
+// Holiday names the holiday falling on t, if any
+func (c *Calendar) Holiday(t time.Time) (string, bool) {
+	d := Date(t)
+	key := d.Format(DateFormat)
+	if name, ok := c.Closed[key]; ok {
+		return name, true
+	}
+	if c.Open[key] {
+		return "", false
+	}
+	return ruleHoliday(d)
+}
+
+// IsTradingDay reports whether the exchange holds a session on t
+func (c *Calendar) IsTradingDay(t time.Time) bool {
+	d := Date(t)
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := c.Holiday(d)
+	return !holiday
+}
+
+// IsEarlyClose reports whether the session on t ends early
+func (c *Calendar) IsEarlyClose(t time.Time) bool {
+	d := Date(t)
+	if !c.IsTradingDay(d) {
+		return false
+	}
+	key := d.Format(DateFormat)
+	if c.EarlyClose[key] {
+		return true
+	}
+	if c.Open[key] {
+		return false
+	}
+	return ruleEarlyClose(d)
+}
+
+// CloseTime returns the end of the session on t in exchange time
+func (c *Calendar) CloseTime(t time.Time) (time.Time, bool) {
+	d := Date(t)
+	if !c.IsTradingDay(d) {
+		return time.Time{}, false
+	}
+	end := c.Close
+	if c.IsEarlyClose(d) {
+		end = c.EarlyCloseTime
+	}
+	midnight := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, c.Location)
+	return midnight.Add(end), true
+}
+
+// PrevTradingDay returns the last session strictly before t
+func (c *Calendar) PrevTradingDay(t time.Time) time.Time {
+	d := Date(t).AddDate(0, 0, -1)
+	for !c.IsTradingDay(d) {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+// NextTradingDay returns the first session strictly after t
+func (c *Calendar) NextTradingDay(t time.Time) time.Time {
+	d := Date(t).AddDate(0, 0, 1)
+	for !c.IsTradingDay(d) {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// TradingDaysBetween lists the sessions from through to, both inclusive
+func (c *Calendar) TradingDaysBetween(from, to time.Time) []time.Time {
+	var days []time.Time
+	for d := Date(from); !d.After(Date(to)); d = d.AddDate(0, 0, 1) {
+		if c.IsTradingDay(d) {
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// CountTradingDays counts the sessions strictly after from up to and including to
+func (c *Calendar) CountTradingDays(from, to time.Time) int {
+	if !Date(to).After(Date(from)) {
+		return 0
+	}
+	return len(c.TradingDaysBetween(Date(from).AddDate(0, 0, 1), to))
+}
+
+// LastSession returns the latest session that has closed by now
+func (c *Calendar) LastSession(now time.Time) time.Time {
+	local := now.In(c.Location)
+	today := Date(local)
+	if end, ok := c.CloseTime(today); ok && !local.Before(end) {
+		return today
+	}
+	return c.PrevTradingDay(today)
+}
+
+// Holidays lists the rule based and override holidays of a year that fall on weekdays
+func (c *Calendar) Holidays(year int) []time.Time {
+	var days []time.Time
+	for d := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC); d.Year() == year; d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		if _, ok := c.Holiday(d); ok {
+			days = append(days, d)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
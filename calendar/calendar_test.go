@@ -0,0 +1,116 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, err := ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestHolidays(t *testing.T) {
+	tests := []struct {
+		year int
+		want []string
+	}{
+		{2021, []string{
+			"2021-01-01", "2021-01-18", "2021-02-15", "2021-04-02", "2021-05-31",
+			"2021-07-05", "2021-09-06", "2021-11-25", "2021-12-24",
+		}},
+		{2022, []string{
+			// new year on a saturday is not observed on the prior friday
+			"2022-01-17", "2022-02-21", "2022-04-15", "2022-05-30", "2022-06-20",
+			"2022-07-04", "2022-09-05", "2022-11-24", "2022-12-26",
+		}},
+		{2023, []string{
+			"2023-01-02", "2023-01-16", "2023-02-20", "2023-04-07", "2023-05-29",
+			"2023-06-19", "2023-07-04", "2023-09-04", "2023-11-23", "2023-12-25",
+		}},
+		{2025, []string{
+			"2025-01-01", "2025-01-09", "2025-01-20", "2025-02-17", "2025-04-18",
+			"2025-05-26", "2025-06-19", "2025-07-04", "2025-09-01", "2025-11-27",
+			"2025-12-25",
+		}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, d := range NYSE.Holidays(tt.year) {
+			got = append(got, d.Format(DateFormat))
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%d: got %v, want %v", tt.year, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%d: got %v, want %v", tt.year, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestIsTradingDay(t *testing.T) {
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2021-12-31", true}, // friday before a saturday new year
+		{"2021-06-18", true}, // juneteenth is only observed from 2022
+		{"1997-01-20", true}, // martin luther king day is only observed from 1998
+		{"1998-01-19", false},
+		{"2012-10-29", false}, // hurricane sandy
+		{"2001-09-17", true},
+		{"2020-04-10", false}, // good friday
+		{"2020-04-13", true},
+		{"2021-07-03", false}, // saturday
+	}
+	for _, tt := range tests {
+		if got := NYSE.IsTradingDay(date(tt.date)); got != tt.want {
+			t.Errorf("IsTradingDay(%s) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestIsEarlyClose(t *testing.T) {
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2019-07-03", true},
+		{"2020-07-03", false}, // independence day observed
+		{"2021-11-26", true},
+		{"2021-12-24", false}, // christmas observed
+		{"2020-12-24", true},
+		{"2021-12-23", false},
+	}
+	for _, tt := range tests {
+		if got := NYSE.IsEarlyClose(date(tt.date)); got != tt.want {
+			t.Errorf("IsEarlyClose(%s) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestLastSession(t *testing.T) {
+	ny := NYSE.Location
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{time.Date(2021, 1, 8, 15, 59, 0, 0, ny), "2021-01-07"},
+		{time.Date(2021, 1, 8, 16, 0, 0, 0, ny), "2021-01-08"},
+		{time.Date(2021, 1, 10, 12, 0, 0, 0, ny), "2021-01-08"},
+		{time.Date(2021, 11, 26, 13, 30, 0, 0, ny), "2021-11-26"},
+		{time.Date(2021, 1, 19, 9, 0, 0, 0, ny), "2021-01-15"},
+	}
+	for _, tt := range tests {
+		if got := NYSE.LastSession(tt.now).Format(DateFormat); got != tt.want {
+			t.Errorf("LastSession(%s) = %s, want %s", tt.now, got, tt.want)
+		}
+	}
+}
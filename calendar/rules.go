@@ -0,0 +1,93 @@
+package calendar
+
+import "time"
+
+// ruleHoliday applies the regular NYSE holiday schedule to a midnight UTC date
+func ruleHoliday(d time.Time) (string, bool) {
+	y, m, day := d.Year(), d.Month(), d.Day()
+	switch {
+	case m == time.January && day == 1 && d.Weekday() != time.Sunday,
+		m == time.January && day == 2 && d.Weekday() == time.Monday:
+		// a Saturday new year is not observed on the prior Friday
+		return "New Year's Day", true
+	case y >= 1998 && d.Equal(nthWeekday(y, time.January, time.Monday, 3)):
+		return "Martin Luther King Jr. Day", true
+	case d.Equal(nthWeekday(y, time.February, time.Monday, 3)):
+		return "Washington's Birthday", true
+	case d.Equal(easter(y).AddDate(0, 0, -2)):
+		return "Good Friday", true
+	case d.Equal(lastWeekday(y, time.May, time.Monday)):
+		return "Memorial Day", true
+	case y >= 2022 && d.Equal(observed(y, time.June, 19)):
+		return "Juneteenth", true
+	case d.Equal(observed(y, time.July, 4)):
+		return "Independence Day", true
+	case d.Equal(nthWeekday(y, time.September, time.Monday, 1)):
+		return "Labor Day", true
+	case d.Equal(nthWeekday(y, time.November, time.Thursday, 4)):
+		return "Thanksgiving Day", true
+	case d.Equal(observed(y, time.December, 25)):
+		return "Christmas Day", true
+	}
+	return "", false
+}
+
+// ruleEarlyClose flags the regular 1pm closes around Independence Day,
+// Thanksgiving and Christmas
+func ruleEarlyClose(d time.Time) bool {
+	y, m, day := d.Year(), d.Month(), d.Day()
+	switch {
+	case m == time.July && day == 3:
+		return true
+	case d.Equal(nthWeekday(y, time.November, time.Thursday, 4).AddDate(0, 0, 1)):
+		return true
+	case m == time.December && day == 24:
+		return true
+	}
+	return false
+}
+
+// observed moves a fixed date holiday off the weekend
+func observed(year int, month time.Month, day int) time.Time {
+	d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	switch d.Weekday() {
+	case time.Saturday:
+		return d.AddDate(0, 0, -1)
+	case time.Sunday:
+		return d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+// nthWeekday finds the nth weekday wd of a month
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) time.Time {
+	d := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (int(wd) - int(d.Weekday()) + 7) % 7
+	return d.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday finds the last weekday wd of a month
+func lastWeekday(year int, month time.Month, wd time.Weekday) time.Time {
+	d := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	offset := (int(d.Weekday()) - int(wd) + 7) % 7
+	return d.AddDate(0, 0, -offset)
+}
+
+// easter computes Easter Sunday with the anonymous Gregorian algorithm
+func easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// RawPayload is an archived api response
+type RawPayload struct {
+	ID        int64
+	RunID     string
+	Endpoint  string
+	FetchedAt time.Time
+	Payload   []byte
+}
+
+// ArchivePayload stores a raw api response for later reprocessing
+func (c *Conn) ArchivePayload(runID, endpoint string, fetched time.Time, payload []byte) error {
+	sql := `INSERT INTO raw_payloads (run_id, endpoint, fetched_at, payload)
+		VALUES ($1, $2, $3, $4)`
+	_, err := c.c.Exec(context.Background(), sql, runID, endpoint, fetched, string(payload))
+	return err
+}
+
+// PayloadIDs lists archived payloads for runID in fetch order;
+// an empty runID selects the latest payload of every endpoint
+func (c *Conn) PayloadIDs(runID string) ([]int64, error) {
+	sql := `SELECT id FROM raw_payloads WHERE run_id=$1 ORDER BY fetched_at, id`
+	args := []interface{}{runID}
+	if runID == "" {
+		sql = `SELECT id FROM (
+			SELECT DISTINCT ON (endpoint) id, fetched_at FROM raw_payloads
+			ORDER BY endpoint, fetched_at DESC
+		) latest ORDER BY fetched_at, id`
+		args = nil
+	}
+	rows, err := c.c.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Payload fetches a single archived payload
+func (c *Conn) Payload(id int64) (*RawPayload, error) {
+	sql := `SELECT id, run_id, endpoint, fetched_at, payload::text FROM raw_payloads WHERE id=$1`
+	var rp RawPayload
+	var payload string
+	if err := c.c.QueryRow(context.Background(), sql, id).Scan(
+		&rp.ID, &rp.RunID, &rp.Endpoint, &rp.FetchedAt, &payload,
+	); err != nil {
+		return nil, err
+	}
+	rp.Payload = []byte(payload)
+	return &rp, nil
+}
@@ -4,32 +4,92 @@ import (
 	"context"
 	"database/sql"
 	"defcor/iex"
+	"defcor/logging"
+	"defcor/metrics"
+	"errors"
 	"fmt"
-	"log"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 )
 
 var tfmt = "2006-01-02"
 
-// Conn type stores a postgres database connection
+// ErrNotFound is returned when a lookup matches nothing
+var ErrNotFound = errors.New("not found")
+
+// Conn type stores a pool of postgres connections, safe for concurrent use
 type Conn struct {
-	c *pgx.Conn
+	c   *pgxpool.Pool
+	log *logging.Logger
+
+	mu    sync.Mutex
+	locks map[string]*pgxpool.Conn
 }
 
 // CreateConn creates a postgres connection struct
 func CreateConn(dburl string) (*Conn, error) {
-	c, err := pgx.Connect(context.Background(), dburl)
+	c, err := pgxpool.Connect(context.Background(), dburl)
 	if err != nil {
 		return nil, err
 	}
-	return &Conn{c}, nil
+	return &Conn{c: c, log: logging.Default(), locks: make(map[string]*pgxpool.Conn)}, nil
+}
+
+// SetLogger replaces the logger writes are recorded with
+func (c *Conn) SetLogger(l *logging.Logger) {
+	c.log = l
+}
+
+// writes counts the rows a statement batch touched
+type writes struct {
+	inserted, updated, deleted int
+	// first and last bound the dates of the inserted or changed rows
+	first, last string
+}
+
+// row counts one upserted row; inserted is the (xmax = 0) upsert idiom
+func (w *writes) row(inserted bool) {
+	if inserted {
+		w.inserted++
+	} else {
+		w.updated++
+	}
+}
+
+// dated widens the span of changed rows to date
+func (w *writes) dated(date string) {
+	if w.first == "" || date < w.first {
+		w.first = date
+	}
+	if date > w.last {
+		w.last = date
+	}
+}
+
+// written records a committed write to table
+func (c *Conn) written(table, symbol string, w writes) {
+	metrics.Rows.WithLabelValues(table, "inserted").Add(float64(w.inserted))
+	metrics.Rows.WithLabelValues(table, "updated").Add(float64(w.updated))
+	metrics.Rows.WithLabelValues(table, "deleted").Add(float64(w.deleted))
+	c.log.Info("rows written", "table", table, "symbol", symbol,
+		"inserted", w.inserted, "updated", w.updated, "deleted", w.deleted)
+}
+
+// timeTx starts timing a write transaction on table; call the result when it ends
+func timeTx(table string) func() {
+	start := time.Now()
+	return func() {
+		metrics.TxDuration.WithLabelValues(table).Observe(time.Since(start).Seconds())
+	}
 }
 
-// Close ends a postgres connection
+// Close ends every postgres connection
 func (c *Conn) Close() error {
-	return c.c.Close(context.Background())
+	c.c.Close()
+	return nil
 }
 
 // InsertStock inserts a stock record into a database
@@ -41,13 +101,80 @@ func (c *Conn) InsertStock(s iex.Stock) (int, error) {
 	RETURNING secid`
 	today := time.Now().Format(tfmt)
 
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return -1, err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
 	var secid int
-	if err := c.c.QueryRow(context.Background(), sql,
+	if err := tx.QueryRow(context.Background(), sql,
 		s.Symbol, s.Name, today, s.Type, s.IexID, s.Figi, s.Curr, s.Region, s.Cik,
 	).Scan(&secid); err != nil {
 		return -1, err // -1 is an invalid secid
 	}
-	return secid, nil
+	if err := notify(tx, Event{Kind: EventStockAdded, Secid: secid, Symbol: s.Symbol}); err != nil {
+		return -1, err
+	}
+	return secid, tx.Commit(context.Background())
+}
+
+// DeactivateStock marks the active stock with symbol inactive as of today
+func (c *Conn) DeactivateStock(symbol string) error {
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	var secid int
+	err = tx.QueryRow(context.Background(),
+		`UPDATE stocks SET date_inactive=current_date
+		WHERE symbol=$1 AND date_inactive IS NULL
+		RETURNING secid`, symbol,
+	).Scan(&secid)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := notify(tx, Event{Kind: EventStockDeactivated, Secid: secid, Symbol: symbol}); err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+// UpdateStock replaces the reference data of the active stock prev with curr,
+// keeping its secid and history; a changed symbol is notified as a rename
+func (c *Conn) UpdateStock(prev, curr iex.Stock) error {
+	sql := `UPDATE stocks SET
+		symbol=$2, name=$3, sectype=$4, iexid=$5, figi=$6, currency=$7, region=$8, cik=$9
+		WHERE symbol=$1 AND date_inactive IS NULL
+		RETURNING secid`
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	var secid int
+	err = tx.QueryRow(context.Background(), sql, prev.Symbol,
+		curr.Symbol, curr.Name, curr.Type, curr.IexID, curr.Figi, curr.Curr, curr.Region, curr.Cik,
+	).Scan(&secid)
+	if err == pgx.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if prev.Symbol != curr.Symbol {
+		e := Event{Kind: EventStockRenamed, Secid: secid, Symbol: curr.Symbol, PrevSymbol: prev.Symbol}
+		if err := notify(tx, e); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(context.Background())
 }
 
 // InsertStocks adds a slice of stocks into the stocks database
@@ -57,7 +184,7 @@ func (c *Conn) InsertStocks(stks []iex.Stock) error {
 		if err != nil {
 			return err
 		}
-		log.Printf("secid: %d(%s, %s)", secid, stk.Symbol, stk.Name)
+		c.log.Info("stock inserted", "secid", secid, "symbol", stk.Symbol, "name", stk.Name)
 	}
 	return nil
 }
@@ -110,6 +237,19 @@ func (c *Conn) Stocks() ([]iex.Stock, error) {
 	return stks, nil
 }
 
+// Stock returns the stock with symbol, or ErrNotFound
+func (c *Conn) Stock(symbol string) (iex.Stock, error) {
+	sql := `SELECT symbol, name, sectype, iexid, figi, currency, region, cik FROM stocks WHERE symbol=$1`
+	var s iex.Stock
+	err := c.c.QueryRow(context.Background(), sql, symbol).Scan(
+		&s.Symbol, &s.Name, &s.Type, &s.IexID, &s.Figi, &s.Curr, &s.Region, &s.Cik,
+	)
+	if err == pgx.ErrNoRows {
+		return s, ErrNotFound
+	}
+	return s, err
+}
+
 // FindSecurityID grabs the secid from the stocks table
 func (c *Conn) FindSecurityID(symbol string) (int, error) {
 	var secid int
@@ -124,33 +264,70 @@ func (c *Conn) FindSecurityID(symbol string) (int, error) {
 
 // InsertPriceHistory inserts a stock's historical price data into the price table
 func (c *Conn) InsertPriceHistory(ph *iex.PriceHistory) error {
-	sql := `INSERT INTO prices(
-		date, secid, uopen, uclose, uhigh, ulow, uvolume, aopen, aclose, ahigh, alow, avolume
-		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 	secid, err := c.FindSecurityID(ph.Symbol)
 	if err != nil {
 		return err
 	}
+	defer timeTx("prices")()
 	tx, err := c.c.Begin(context.Background())
 	if err != nil {
 		return err
 	}
 	// no-op on successful tx commit
 	defer tx.Rollback(context.Background())
-	for _, p := range ph.Prices {
-		_, err = tx.Exec(context.Background(), sql,
-			p.Date, secid, p.Uopen, p.Uclose, p.Uhigh, p.Ulow, p.Uvolume, p.Aopen, p.Aclose, p.Ahigh, p.Alow, p.Avolume,
+	w, err := insertPrices(tx, secid, ph)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+	c.written("prices", ph.Symbol, w)
+	return nil
+}
+
+// insertPrices upserts the bars of ph within tx. Bars matching the stored
+// ones are left alone; the total return index is cut from the earliest
+// inserted or changed bar, and the bars event spans the changed ones only.
+func insertPrices(tx pgx.Tx, secid int, ph *iex.PriceHistory) (writes, error) {
+	sql := `INSERT INTO prices(
+		date, secid, uopen, uclose, uhigh, ulow, uvolume, aopen, aclose, ahigh, alow, avolume, adjusted_only
 		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (date, secid) DO UPDATE SET
+		uopen=EXCLUDED.uopen, uclose=EXCLUDED.uclose, uhigh=EXCLUDED.uhigh, ulow=EXCLUDED.ulow,
+		uvolume=EXCLUDED.uvolume, aopen=EXCLUDED.aopen, aclose=EXCLUDED.aclose, ahigh=EXCLUDED.ahigh,
+		alow=EXCLUDED.alow, avolume=EXCLUDED.avolume, adjusted_only=EXCLUDED.adjusted_only
+		WHERE (prices.uopen, prices.uclose, prices.uhigh, prices.ulow, prices.uvolume,
+			prices.aopen, prices.aclose, prices.ahigh, prices.alow, prices.avolume, prices.adjusted_only)
+		IS DISTINCT FROM (EXCLUDED.uopen, EXCLUDED.uclose, EXCLUDED.uhigh, EXCLUDED.ulow, EXCLUDED.uvolume,
+			EXCLUDED.aopen, EXCLUDED.aclose, EXCLUDED.ahigh, EXCLUDED.alow, EXCLUDED.avolume, EXCLUDED.adjusted_only)
+		RETURNING (xmax = 0)`
+	var w writes
+	for _, p := range ph.Prices {
+		var inserted bool
+		err := tx.QueryRow(context.Background(), sql,
+			p.Date, secid, p.Uopen, p.Uclose, p.Uhigh, p.Ulow, p.Uvolume, p.Aopen, p.Aclose, p.Ahigh, p.Alow, p.Avolume, p.AdjustedOnly,
+		).Scan(&inserted)
+		if err == pgx.ErrNoRows {
+			// the stored bar is unchanged
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("insertion error: %s(price:%v)", ph.Symbol, p)
+			return w, fmt.Errorf("insertion error: %s(price:%v)", ph.Symbol, p)
 		}
+		w.row(inserted)
+		w.dated(p.Date)
 	}
-	err = tx.Commit(context.Background())
-	if err != nil {
-		return err
+	if w.inserted+w.updated == 0 {
+		return w, nil
 	}
-	return nil
+	if err := truncateTotalReturn(tx, secid, w.first); err != nil {
+		return w, err
+	}
+	// subscribers hear only of the bars that changed
+	return w, notify(tx, Event{Kind: EventBars, Secid: secid, Symbol: ph.Symbol,
+		From: w.first, To: w.last, Rows: w.inserted + w.updated})
 }
 
 // InsertDividendHistory inserts a stock's historical dividends into the dividends table
@@ -158,33 +335,68 @@ func (c *Conn) InsertDividendHistory(dh *iex.DividendHistory) error {
 	if dh.IsEmpty() {
 		return nil
 	}
-	sql := `INSERT INTO dividends
-		(secid, decdate, exdate, recdate, paydate, amount, flag, currency, frequency)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-
 	secid, err := c.FindSecurityID(dh.Symbol)
 	if err != nil {
 		return err
 	}
+	defer timeTx("dividends")()
 	tx, err := c.c.Begin(context.Background())
 	if err != nil {
 		return err
 	}
 	// no-op on successful tx commit
 	defer tx.Rollback(context.Background())
+	w, err := insertDividends(tx, secid, dh)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+	c.written("dividends", dh.Symbol, w)
+	return nil
+}
+
+// insertDividends upserts the dividends of dh within tx. A security can pay
+// several distributions on one exdate, so amount and flag are part of the key.
+// The total return index is cut from the earliest inserted or changed exdate.
+func insertDividends(tx pgx.Tx, secid int, dh *iex.DividendHistory) (writes, error) {
+	sql := `INSERT INTO dividends
+		(secid, decdate, exdate, recdate, paydate, amount, flag, currency, frequency)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (secid, exdate, (coalesce(amount, -1)), (coalesce(flag, ''))) DO UPDATE SET
+		decdate=EXCLUDED.decdate, recdate=EXCLUDED.recdate, paydate=EXCLUDED.paydate,
+		currency=EXCLUDED.currency, frequency=EXCLUDED.frequency
+		WHERE (dividends.decdate, dividends.recdate, dividends.paydate, dividends.currency, dividends.frequency)
+		IS DISTINCT FROM (EXCLUDED.decdate, EXCLUDED.recdate, EXCLUDED.paydate, EXCLUDED.currency, EXCLUDED.frequency)
+		RETURNING (xmax = 0)`
+	var w writes
 	for _, d := range dh.Dividends {
-		_, err = tx.Exec(context.Background(), sql,
+		var inserted bool
+		err := tx.QueryRow(context.Background(), sql,
 			secid, d.DecDate, d.ExDate, d.RecDate, d.PayDate, d.Amount, d.Flag, d.Curr, d.Freq,
-		)
+		).Scan(&inserted)
+		if err == pgx.ErrNoRows {
+			// the stored dividend is unchanged
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("insertion error: %s(dividend:%v)", dh.Symbol, d)
+			return w, fmt.Errorf("insertion error: %s(dividend:%v)", dh.Symbol, d)
+		}
+		w.row(inserted)
+		w.dated(d.ExDate)
+		if inserted {
+			if err := notify(tx, Event{Kind: EventDividend, Secid: secid, Symbol: dh.Symbol, ExDate: d.ExDate}); err != nil {
+				return w, err
+			}
 		}
 	}
-	err = tx.Commit(context.Background())
-	if err != nil {
-		return err
+	if w.first != "" {
+		if err := truncateTotalReturn(tx, secid, w.first); err != nil {
+			return w, err
+		}
 	}
-	return nil
+	return w, nil
 }
 
 // InsertSplitHistory inserts a stock's historical stock splits into the splits table
@@ -192,32 +404,66 @@ func (c *Conn) InsertSplitHistory(sh *iex.SplitHistory) error {
 	if sh.IsEmpty() {
 		return nil
 	}
-	sql := `INSERT INTO splits(secid, decdate, exdate, tofactor, fromfactor)
-		VALUES ($1, $2, $3, $4, $5)`
-
 	secid, err := c.FindSecurityID(sh.Symbol)
 	if err != nil {
 		return err
 	}
+	defer timeTx("splits")()
 	tx, err := c.c.Begin(context.Background())
 	if err != nil {
 		return err
 	}
 	// no-op on successful tx commit
 	defer tx.Rollback(context.Background())
+	w, err := insertSplits(tx, secid, sh)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+	c.written("splits", sh.Symbol, w)
+	return nil
+}
+
+// insertSplits upserts the splits of sh within tx, cutting the total
+// return index from the earliest inserted or changed split
+func insertSplits(tx pgx.Tx, secid int, sh *iex.SplitHistory) (writes, error) {
+	sql := `INSERT INTO splits(secid, decdate, exdate, tofactor, fromfactor)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (secid, exdate) DO UPDATE SET
+		decdate=EXCLUDED.decdate, tofactor=EXCLUDED.tofactor, fromfactor=EXCLUDED.fromfactor
+		WHERE (splits.decdate, splits.tofactor, splits.fromfactor)
+		IS DISTINCT FROM (EXCLUDED.decdate, EXCLUDED.tofactor, EXCLUDED.fromfactor)
+		RETURNING (xmax = 0)`
+	var w writes
 	for _, s := range sh.Splits {
-		_, err = tx.Exec(context.Background(), sql,
+		var inserted bool
+		err := tx.QueryRow(context.Background(), sql,
 			secid, s.DecDate, s.ExDate, s.ToFactor, s.FromFactor,
-		)
+		).Scan(&inserted)
+		if err == pgx.ErrNoRows {
+			// the stored split is unchanged
+			continue
+		}
 		if err != nil {
-			return fmt.Errorf("insertion error: %s(split:%v)", sh.Symbol, s)
+			return w, fmt.Errorf("insertion error: %s(split:%v)", sh.Symbol, s)
+		}
+		w.row(inserted)
+		// a corrected ratio changes the index as much as a new split
+		w.dated(s.ExDate)
+		if inserted {
+			if err := notify(tx, Event{Kind: EventSplit, Secid: secid, Symbol: sh.Symbol, ExDate: s.ExDate}); err != nil {
+				return w, err
+			}
 		}
 	}
-	err = tx.Commit(context.Background())
-	if err != nil {
-		return err
+	if w.first != "" {
+		if err := truncateTotalReturn(tx, secid, w.first); err != nil {
+			return w, err
+		}
 	}
-	return nil
+	return w, nil
 }
 
 func nullString(s string) sql.NullString {
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"defcor/iex"
+	"testing"
+)
+
+func testBar(date string, close float64) iex.Prices {
+	return iex.Prices{Date: date, Uopen: close, Uhigh: close, Ulow: close, Uclose: close, Uvolume: 100,
+		Aopen: close, Ahigh: close, Alow: close, Aclose: close, Avolume: 100}
+}
+
+// seedStock adds AAPL with three bars and an index over them
+func seedStock(t *testing.T, c *Conn) []iex.Prices {
+	err := c.InsertStocks([]iex.Stock{{Symbol: "AAPL", Name: "Apple Inc", Type: "cs", IexID: "IEX_A",
+		Figi: "BBG000B9XRY4", Curr: "USD", Region: "US", Cik: 320193}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bars := []iex.Prices{testBar("2020-01-02", 10), testBar("2020-01-03", 11), testBar("2020-01-06", 12)}
+	if err := c.InsertPriceHistory(&iex.PriceHistory{Symbol: "AAPL", Prices: bars}); err != nil {
+		t.Fatal(err)
+	}
+	indexed(t, c)
+	return bars
+}
+
+// indexed stores an index point for every seeded bar
+func indexed(t *testing.T, c *Conn) {
+	err := c.InsertTotalReturn("AAPL", []IndexPoint{{"2020-01-02", 100}, {"2020-01-03", 110}, {"2020-01-06", 120}})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func lastIndexed(t *testing.T, c *Conn) string {
+	point, ok, err := c.LastTotalReturn("AAPL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		return ""
+	}
+	return point.Date
+}
+
+func TestUpsertsCutIndexFromEarliestChange(t *testing.T) {
+	c := testConn(t)
+	bars := seedStock(t, c)
+
+	// identical bars and actions leave the index alone
+	if err := c.InsertPriceHistory(&iex.PriceHistory{Symbol: "AAPL", Prices: bars}); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastIndexed(t, c); got != "2020-01-06" {
+		t.Fatalf("after identical bars the index ends %q, want 2020-01-06", got)
+	}
+
+	// a corrected bar cuts the index from its date on
+	bars[1].Uclose = 11.5
+	if err := c.InsertPriceHistory(&iex.PriceHistory{Symbol: "AAPL", Prices: bars}); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastIndexed(t, c); got != "2020-01-02" {
+		t.Fatalf("after a changed bar the index ends %q, want 2020-01-02", got)
+	}
+
+	indexed(t, c)
+	split := &iex.SplitHistory{Symbol: "AAPL", Splits: []iex.Split{{ExDate: "2020-01-06", ToFactor: 4, FromFactor: 1}}}
+	if err := c.InsertSplitHistory(split); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastIndexed(t, c); got != "2020-01-03" {
+		t.Fatalf("after a new split the index ends %q, want 2020-01-03", got)
+	}
+	indexed(t, c)
+	if err := c.InsertSplitHistory(split); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastIndexed(t, c); got != "2020-01-06" {
+		t.Errorf("after an unchanged split the index ends %q, want 2020-01-06", got)
+	}
+}
+
+func TestBarsEventSpansChangedBars(t *testing.T) {
+	c := testConn(t)
+	if _, err := c.CreateWebhook("http://127.0.0.1:1/hook", []string{EventBars}, "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	bars := seedStock(t, c)
+	bars[1].Uclose = 11.5
+	bars = append(bars, testBar("2020-01-07", 13))
+	for i := 0; i < 2; i++ {
+		if err := c.InsertPriceHistory(&iex.PriceHistory{Symbol: "AAPL", Prices: bars}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := c.c.Query(context.Background(),
+		`SELECT payload FROM webhook_deliveries WHERE kind=$1 ORDER BY delivery_id`, EventBars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var events []Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(&e); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	// the seed, then one changed and one new bar; the repeat is silent
+	want := []Event{
+		{Kind: EventBars, Symbol: "AAPL", From: "2020-01-02", To: "2020-01-06", Rows: 3},
+		{Kind: EventBars, Symbol: "AAPL", From: "2020-01-03", To: "2020-01-07", Rows: 2},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %+v, want %+v", events, want)
+	}
+	for i, e := range events {
+		e.Secid = 0
+		if e != want[i] {
+			t.Errorf("event %d: got %+v, want %+v", i, e, want[i])
+		}
+	}
+}
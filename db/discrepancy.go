@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"defcor/iex"
+	"fmt"
+	"time"
+)
+
+// Tolerance holds the relative deviations allowed between two sources
+type Tolerance struct {
+	Close  float64
+	Volume float64
+}
+
+// SuspectSymbol summarises the discrepancies recorded for one symbol
+type SuspectSymbol struct {
+	Symbol       string
+	Count        int
+	First        time.Time
+	Last         time.Time
+	MaxDeviation float64
+}
+
+// InsertSourcePrices stores the bars a named source returned for comparison
+func (c *Conn) InsertSourcePrices(source string, ph *iex.PriceHistory) error {
+	sql := `INSERT INTO source_prices(
+		source, date, secid, uopen, uclose, uhigh, ulow, uvolume, aopen, aclose, ahigh, alow, avolume
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (source, date, secid) DO UPDATE SET
+		uopen=EXCLUDED.uopen, uclose=EXCLUDED.uclose, uhigh=EXCLUDED.uhigh, ulow=EXCLUDED.ulow,
+		uvolume=EXCLUDED.uvolume, aopen=EXCLUDED.aopen, aclose=EXCLUDED.aclose, ahigh=EXCLUDED.ahigh,
+		alow=EXCLUDED.alow, avolume=EXCLUDED.avolume`
+	secid, err := c.FindSecurityID(ph.Symbol)
+	if err != nil {
+		return err
+	}
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	for _, p := range ph.Prices {
+		_, err = tx.Exec(context.Background(), sql,
+			source, p.Date, secid, p.Uopen, p.Uclose, p.Uhigh, p.Ulow, p.Uvolume, p.Aopen, p.Aclose, p.Ahigh, p.Alow, p.Avolume,
+		)
+		if err != nil {
+			return fmt.Errorf("insertion error: %s/%s(price:%v)", source, ph.Symbol, p)
+		}
+	}
+	return tx.Commit(context.Background())
+}
+
+// FlagDiscrepancies aligns the bars of sources a and b for symbols by date and
+// secid and records every adjusted close or volume deviating beyond tol.
+// Discrepancies previously recorded for the pair and symbols between from and
+// to are cleared first, so the ones the sources have since agreed on are
+// dropped while other symbols keep theirs.
+func (c *Conn) FlagDiscrepancies(a, b string, symbols []string, from, to string, tol Tolerance) (int64, error) {
+	sql := `INSERT INTO price_discrepancies
+		(date, secid, source_a, source_b, field, value_a, value_b, deviation)
+		SELECT date, secid, source_a, source_b, field, value_a, value_b, deviation FROM (
+			SELECT pa.date, pa.secid, pa.source AS source_a, pb.source AS source_b,
+				'close' AS field, pa.aclose::numeric AS value_a, pb.aclose::numeric AS value_b,
+				abs(pa.aclose - pb.aclose) / nullif(abs(pa.aclose), 0) AS deviation, $5::numeric AS tol
+			FROM source_prices pa JOIN source_prices pb USING (date, secid) JOIN stocks s USING (secid)
+			WHERE pa.source=$1 AND pb.source=$2 AND pa.date BETWEEN $3::date AND $4::date
+			AND s.symbol = ANY($7)
+			UNION ALL
+			SELECT pa.date, pa.secid, pa.source, pb.source,
+				'volume', pa.avolume, pb.avolume,
+				abs(pa.avolume - pb.avolume)::numeric / nullif(abs(pa.avolume), 0), $6::numeric
+			FROM source_prices pa JOIN source_prices pb USING (date, secid) JOIN stocks s USING (secid)
+			WHERE pa.source=$1 AND pb.source=$2 AND pa.date BETWEEN $3::date AND $4::date
+			AND s.symbol = ANY($7)
+		) aligned
+		WHERE coalesce(deviation, 1) > tol AND value_a IS DISTINCT FROM value_b
+		ON CONFLICT (date, secid, source_a, source_b, field) DO UPDATE SET
+		value_a=EXCLUDED.value_a, value_b=EXCLUDED.value_b, deviation=EXCLUDED.deviation, detected_at=now()`
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	if _, err := tx.Exec(context.Background(),
+		`DELETE FROM price_discrepancies
+		USING stocks s
+		WHERE price_discrepancies.secid=s.secid AND s.symbol = ANY($5)
+		AND source_a=$1 AND source_b=$2 AND date BETWEEN $3::date AND $4::date`, a, b, from, to, symbols,
+	); err != nil {
+		return 0, err
+	}
+	tag, err := tx.Exec(context.Background(), sql, a, b, from, to, tol.Close, tol.Volume, symbols)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), tx.Commit(context.Background())
+}
+
+// SuspectSymbols summarises by symbol the discrepancies recorded between from
+// and to of the others against the reference source for symbols
+func (c *Conn) SuspectSymbols(reference string, others, symbols []string, from, to string) ([]SuspectSymbol, error) {
+	sql := `SELECT s.symbol, count(*), min(d.date), max(d.date), coalesce(max(d.deviation), 0)::float8
+		FROM price_discrepancies d JOIN stocks s USING (secid)
+		WHERE d.source_a=$1 AND d.source_b=ANY($2)
+		AND d.date BETWEEN $3::date AND $4::date AND s.symbol = ANY($5)
+		GROUP BY s.symbol
+		ORDER BY count(*) DESC, s.symbol`
+	rows, err := c.c.Query(context.Background(), sql, reference, others, from, to, symbols)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suspects []SuspectSymbol
+	for rows.Next() {
+		var s SuspectSymbol
+		if err := rows.Scan(&s.Symbol, &s.Count, &s.First, &s.Last, &s.MaxDeviation); err != nil {
+			return nil, err
+		}
+		suspects = append(suspects, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return suspects, nil
+}
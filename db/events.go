@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// EventChannel is the postgres channel ingestion events are notified on
+const EventChannel = "defcor_events"
+
+// Event kinds
+const (
+	EventBars             = "bars"
+	EventDividend         = "dividend"
+	EventSplit            = "split"
+	EventStockAdded       = "stock_added"
+	EventStockDeactivated = "stock_deactivated"
+	EventStockRenamed     = "stock_renamed"
+	EventRunComplete      = "run_complete"
+)
+
+// Event describes a committed change. Bars carry the date range and number
+// of bars inserted or changed, dividends and splits their exdate, renames the old symbol
+// and run completions the run, its command and any error it ended with.
+type Event struct {
+	Kind       string `json:"kind"`
+	Secid      int    `json:"secid"`
+	Symbol     string `json:"symbol"`
+	From       string `json:"from,omitempty"`
+	To         string `json:"to,omitempty"`
+	Rows       int    `json:"rows,omitempty"`
+	ExDate     string `json:"exDate,omitempty"`
+	PrevSymbol string `json:"prevSymbol,omitempty"`
+	RunID      string `json:"runId,omitempty"`
+	Command    string `json:"command,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// notify queues e on tx along with a delivery per webhook subscribed to
+// its kind; postgres only delivers either if tx commits
+func notify(tx pgx.Tx, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(context.Background(), `SELECT pg_notify($1, $2)`, EventChannel, string(payload)); err != nil {
+		return fmt.Errorf("notifying %s %s: %w", e.Kind, e.Symbol, err)
+	}
+	if _, err := tx.Exec(context.Background(),
+		`INSERT INTO webhook_deliveries (webhook_id, kind, payload)
+		SELECT webhook_id, $1, $2::jsonb FROM webhooks
+		WHERE disabled_at IS NULL AND (events = '{}' OR $1 = ANY(events))`,
+		e.Kind, string(payload),
+	); err != nil {
+		return fmt.Errorf("queueing webhooks for %s %s: %w", e.Kind, e.Symbol, err)
+	}
+	return nil
+}
+
+// RunComplete notifies the end of a run of command; runErr is nil on success
+func (c *Conn) RunComplete(runID, command string, runErr error) error {
+	e := Event{Kind: EventRunComplete, RunID: runID, Command: command}
+	if runErr != nil {
+		e.Error = runErr.Error()
+	}
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	if err := notify(tx, e); err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+// Subscribe holds a connection listening for events and calls fn with each
+// until ctx is done or fn fails. Events committed while no one listens are lost.
+func (c *Conn) Subscribe(ctx context.Context, fn func(Event) error) error {
+	conn, err := c.c.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+	if _, err := conn.Exec(ctx, "LISTEN "+EventChannel); err != nil {
+		return err
+	}
+	// the connection goes back to the pool, so stop listening on it
+	defer conn.Exec(context.Background(), "UNLISTEN "+EventChannel)
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(n.Payload), &e); err != nil {
+			c.log.Warn("undecodable event", "payload", n.Payload, "err", err)
+			continue
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
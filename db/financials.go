@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"defcor/iex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// upsertSQL builds an insert of cols into table replacing rows that share
+// (secid, reportDate); it returns whether the row was new
+func upsertSQL(table string, cols []string) string {
+	params := make([]string, len(cols))
+	sets := make([]string, 0, len(cols))
+	for i, col := range cols {
+		params[i] = fmt.Sprintf("$%d", i+1)
+		if col != "secid" && col != "reportDate" {
+			sets = append(sets, fmt.Sprintf("%s=EXCLUDED.%s", col, col))
+		}
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (secid, reportDate) DO UPDATE SET %s RETURNING (xmax = 0)",
+		table, strings.Join(cols, ", "), strings.Join(params, ", "), strings.Join(sets, ", "))
+}
+
+var incomestatementColumns = []string{
+	"secid", "reportDate", "fiscalDate", "currency", "totalRevenue",
+	"costOfRevenue", "grossProfit", "researchAndDevelopment", "sellingGeneralAndAdmin", "operatingExpense",
+	"operatingIncome", "otherIncomeExpenseNet", "ebit", "interestIncome", "pretaxIncome",
+	"incomeTax", "minorityInterest", "netIncome", "netIncomeBasic",
+}
+
+// InsertIncomeHistory inserts a stock's quarterly income statements into the incomestatement table
+func (c *Conn) InsertIncomeHistory(ih *iex.IncomeHistory) error {
+	if len(ih.Income) == 0 {
+		return nil
+	}
+	sql := upsertSQL("incomestatement", incomestatementColumns)
+	secid, err := c.FindSecurityID(ih.Symbol)
+	if err != nil {
+		return err
+	}
+	defer timeTx("incomestatement")()
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	var w writes
+	for _, s := range ih.Income {
+		var inserted bool
+		err = tx.QueryRow(context.Background(), sql,
+			secid, s.ReportDate, s.FiscalDate, s.Currency, s.TotalRevenue,
+			s.CostOfRevenue, s.GrossProfit, s.ResearchAndDevelopment, s.SellingGeneralAndAdmin, s.OperatingExpense,
+			s.OperatingIncome, s.OtherIncomeExpenseNet, s.Ebit, s.InterestIncome, s.PretaxIncome,
+			s.IncomeTax, s.MinorityInterest, s.NetIncome, s.NetIncomeBasic,
+		).Scan(&inserted)
+		if err != nil {
+			return fmt.Errorf("insertion error: %s(incomestatement:%v)", ih.Symbol, s.ReportDate)
+		}
+		w.row(inserted)
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+	c.written("incomestatement", ih.Symbol, w)
+	return nil
+}
+
+var balancesheetColumns = []string{
+	"secid", "reportDate", "fiscalDate", "currency", "currentCash",
+	"shortTermInvestments", "receivables", "inventory", "otherCurrentAssets", "currentAssets",
+	"longTermInvestments", "propertyPlantEquipment", "goodwill", "intangibleAssets", "otherAssets",
+	"totalAssets", "accountsPayable", "currentLongTermDebt", "otherCurrentLiabilities", "totalCurrentLiabilities",
+	"longTermDebt", "otherLiabilities", "minorityInterest", "totalLiabilities", "commonStock",
+	"retainedEarnings", "treasuryStock", "capitalSurplus", "shareholderEquity", "netTangibleAssets",
+}
+
+// InsertBalanceHistory inserts a stock's quarterly balance sheets into the balancesheet table
+func (c *Conn) InsertBalanceHistory(bh *iex.BalanceHistory) error {
+	if len(bh.Balancesheet) == 0 {
+		return nil
+	}
+	sql := upsertSQL("balancesheet", balancesheetColumns)
+	secid, err := c.FindSecurityID(bh.Symbol)
+	if err != nil {
+		return err
+	}
+	defer timeTx("balancesheet")()
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	var w writes
+	for _, s := range bh.Balancesheet {
+		var inserted bool
+		err = tx.QueryRow(context.Background(), sql,
+			secid, s.ReportDate, s.FiscalDate, s.Currency, s.CurrentCash,
+			s.ShortTermInvestments, s.Receivables, s.Inventory, s.OtherCurrentAssets, s.CurrentAssets,
+			s.LongTermInvestments, s.PropertyPlantEquipment, s.Goodwill, s.IntangibleAssets, s.OtherAssets,
+			s.TotalAssets, s.AccountsPayable, s.CurrentLongTermDebt, s.OtherCurrentLiabilities, s.TotalCurrentLiabilities,
+			s.LongTermDebt, s.OtherLiabilities, s.MinorityInterest, s.TotalLiabilities, s.CommonStock,
+			s.RetainedEarnings, s.TreasuryStock, s.CapitalSurplus, s.ShareholderEquity, s.NetTangibleAssets,
+		).Scan(&inserted)
+		if err != nil {
+			return fmt.Errorf("insertion error: %s(balancesheet:%v)", bh.Symbol, s.ReportDate)
+		}
+		w.row(inserted)
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+	c.written("balancesheet", bh.Symbol, w)
+	return nil
+}
+
+var cashflowColumns = []string{
+	"secid", "reportDate", "fiscalDate", "currency", "netIncome",
+	"depreciation", "changesInReceivables", "changesInInventories", "cashChange", "cashFlow",
+	"capitalExpenditures", "investments", "investingActivityOther", "totalInvestingCashFlows", "dividendsPaid",
+	"netBorrowings", "otherFinancingCashFlows", "cashFlowFinancing", "exchangeRateEffect",
+}
+
+// InsertCashFlowHistory inserts a stock's quarterly cash flows into the cashflow table
+func (c *Conn) InsertCashFlowHistory(ch *iex.CashFlowHistory) error {
+	if len(ch.Cashflow) == 0 {
+		return nil
+	}
+	sql := upsertSQL("cashflow", cashflowColumns)
+	secid, err := c.FindSecurityID(ch.Symbol)
+	if err != nil {
+		return err
+	}
+	defer timeTx("cashflow")()
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	var w writes
+	for _, s := range ch.Cashflow {
+		var inserted bool
+		err = tx.QueryRow(context.Background(), sql,
+			secid, s.ReportDate, s.FiscalDate, s.Currency, s.NetIncome,
+			s.Depreciation, s.ChangesInReceivables, s.ChangesInInventories, s.CashChange, s.CashFlow,
+			s.CapitalExpenditures, s.Investments, s.InvestingActivityOther, s.TotalInvestingCashFlows, s.DividendsPaid,
+			s.NetBorrowings, s.OtherFinancingCashFlows, s.CashFlowFinancing, s.ExchangeRateEffect,
+		).Scan(&inserted)
+		if err != nil {
+			return fmt.Errorf("insertion error: %s(cashflow:%v)", ch.Symbol, s.ReportDate)
+		}
+		w.row(inserted)
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+	c.written("cashflow", ch.Symbol, w)
+	return nil
+}
+
+// statements reads the statements of symbol in table, latest first, into v
+// by way of json so the column lists above stay the only mapping
+func (c *Conn) statements(table string, cols []string, symbol string, v interface{}) error {
+	sql := fmt.Sprintf(`SELECT coalesce(json_agg(t ORDER BY t.reportDate DESC), '[]')::text FROM (
+		SELECT %s FROM %s JOIN stocks s USING (secid) WHERE s.symbol=$1
+		) t`, strings.Join(cols[1:], ", "), table)
+	var data string
+	if err := c.c.QueryRow(context.Background(), sql, symbol).Scan(&data); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), v)
+}
+
+// IncomeHistory reads the stored income statements of symbol, latest first
+func (c *Conn) IncomeHistory(symbol string) (*iex.IncomeHistory, error) {
+	ih := iex.IncomeHistory{Symbol: symbol}
+	if err := c.statements("incomestatement", incomestatementColumns, symbol, &ih.Income); err != nil {
+		return nil, err
+	}
+	return &ih, nil
+}
+
+// BalanceHistory reads the stored balance sheets of symbol, latest first
+func (c *Conn) BalanceHistory(symbol string) (*iex.BalanceHistory, error) {
+	bh := iex.BalanceHistory{Symbol: symbol}
+	if err := c.statements("balancesheet", balancesheetColumns, symbol, &bh.Balancesheet); err != nil {
+		return nil, err
+	}
+	return &bh, nil
+}
+
+// CashFlowHistory reads the stored cash flow statements of symbol, latest first
+func (c *Conn) CashFlowHistory(symbol string) (*iex.CashFlowHistory, error) {
+	ch := iex.CashFlowHistory{Symbol: symbol}
+	if err := c.statements("cashflow", cashflowColumns, symbol, &ch.Cashflow); err != nil {
+		return nil, err
+	}
+	return &ch, nil
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Freshness is how recent the stored data of a symbol is; nil means none
+type Freshness struct {
+	Symbol string
+	// LastPrice is the date of the latest bar
+	LastPrice *time.Time
+	// DividendsFetched is when dividends were last fetched, whether or not any came back
+	DividendsFetched *time.Time
+	// LastReport is the latest income statement report date
+	LastReport *time.Time
+}
+
+// RecordFetch notes that kind was fetched for symbol just now
+func (c *Conn) RecordFetch(symbol, kind string) error {
+	sql := `INSERT INTO fetches (secid, kind, fetched_at)
+		SELECT secid, $2, now() FROM stocks WHERE symbol=$1
+		ON CONFLICT (secid, kind) DO UPDATE SET fetched_at=EXCLUDED.fetched_at`
+	_, err := c.c.Exec(context.Background(), sql, symbol, kind)
+	return err
+}
+
+// FreshnessAll reports the freshness of every active symbol
+func (c *Conn) FreshnessAll() ([]Freshness, error) {
+	sql := `SELECT s.symbol,
+		(SELECT max(p.date) FROM prices p WHERE p.secid=s.secid),
+		(SELECT f.fetched_at FROM fetches f WHERE f.secid=s.secid AND f.kind='dividends'),
+		(SELECT max(i.reportDate) FROM incomestatement i WHERE i.secid=s.secid)
+		FROM stocks s
+		WHERE s.date_inactive IS NULL
+		ORDER BY s.symbol`
+	rows, err := c.c.Query(context.Background(), sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fs []Freshness
+	for rows.Next() {
+		var f Freshness
+		if err := rows.Scan(&f.Symbol, &f.LastPrice, &f.DividendsFetched, &f.LastReport); err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// EmptySessions lists the sessions of symbol the vendor confirmed it has no
+// bar for, ordered by date
+func (c *Conn) EmptySessions(symbol string) ([]time.Time, error) {
+	sql := `SELECT e.date FROM empty_sessions e JOIN stocks s USING (secid)
+		WHERE s.symbol=$1
+		ORDER BY e.date`
+	rows, err := c.c.Query(context.Background(), sql, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var d time.Time
+		if err := rows.Scan(&d); err != nil {
+			return nil, err
+		}
+		dates = append(dates, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// MarkEmptySessions records sessions of symbol a backfill asked for and the
+// vendor returned no bar for, so they are not planned again
+func (c *Conn) MarkEmptySessions(symbol string, dates []time.Time) error {
+	if len(dates) == 0 {
+		return nil
+	}
+	sql := `INSERT INTO empty_sessions (secid, date)
+		VALUES ($1, $2)
+		ON CONFLICT (secid, date) DO UPDATE SET confirmed_at=now()
+		RETURNING (xmax = 0)`
+	secid, err := c.FindSecurityID(symbol)
+	if err != nil {
+		return err
+	}
+	defer timeTx("empty_sessions")()
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	var w writes
+	for _, d := range dates {
+		var inserted bool
+		if err := tx.QueryRow(context.Background(), sql, secid, d).Scan(&inserted); err != nil {
+			return err
+		}
+		w.row(inserted)
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+	c.written("empty_sessions", symbol, w)
+	return nil
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"defcor/iex"
+	"time"
+)
+
+// PriceHistory reads the stored bars of symbol between from and to ordered by date
+func (c *Conn) PriceHistory(symbol, from, to string) (*iex.PriceHistory, error) {
+	return c.priceRows(symbol, priceSelect+" ORDER BY p.date", symbol, from, to)
+}
+
+// PricePage reads at most limit bars of symbol between from and to, skipping
+// the first offset, so callers paging through a long history only load a page
+func (c *Conn) PricePage(symbol, from, to string, limit, offset int) (*iex.PriceHistory, error) {
+	return c.priceRows(symbol, priceSelect+" ORDER BY p.date LIMIT $4 OFFSET $5", symbol, from, to, limit, offset)
+}
+
+const priceSelect = `SELECT p.date, p.uopen, p.uclose, p.uhigh, p.ulow, p.uvolume,
+		p.aopen, p.aclose, p.ahigh, p.alow, p.avolume, p.adjusted_only
+		FROM prices p JOIN stocks s USING (secid)
+		WHERE s.symbol=$1 AND p.date BETWEEN $2::date AND $3::date`
+
+func (c *Conn) priceRows(symbol, sql string, args ...interface{}) (*iex.PriceHistory, error) {
+	rows, err := c.c.Query(context.Background(), sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ph := iex.PriceHistory{Symbol: symbol}
+	for rows.Next() {
+		var p iex.Prices
+		var date time.Time
+		if err := rows.Scan(
+			&date, &p.Uopen, &p.Uclose, &p.Uhigh, &p.Ulow, &p.Uvolume,
+			&p.Aopen, &p.Aclose, &p.Ahigh, &p.Alow, &p.Avolume, &p.AdjustedOnly,
+		); err != nil {
+			return nil, err
+		}
+		p.Date = date.Format(tfmt)
+		ph.Prices = append(ph.Prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &ph, nil
+}
+
+// ClosesBefore reads the last unadjusted close of symbol before each of dates,
+// leaving out dates with no earlier bar
+func (c *Conn) ClosesBefore(symbol string, dates []string) (map[string]float64, error) {
+	sql := `SELECT d::text, (SELECT p.uclose::float8 FROM prices p
+			WHERE p.secid=s.secid AND p.date < d ORDER BY p.date DESC LIMIT 1)
+		FROM stocks s, unnest($2::text[]::date[]) d
+		WHERE s.symbol=$1`
+	// pgx binds []string as text[] only; postgres does the cast to dates
+	rows, err := c.c.Query(context.Background(), sql, symbol, dates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	closes := make(map[string]float64)
+	for rows.Next() {
+		var date string
+		var close *float64
+		if err := rows.Scan(&date, &close); err != nil {
+			return nil, err
+		}
+		if close != nil {
+			closes[date] = *close
+		}
+	}
+	return closes, rows.Err()
+}
+
+// DividendHistory reads the stored dividends of symbol ordered by exdate
+func (c *Conn) DividendHistory(symbol string) (*iex.DividendHistory, error) {
+	sql := `SELECT d.decdate::text, d.exdate::text, d.recdate::text, d.paydate::text,
+		d.amount::float8, d.flag, d.currency, d.frequency
+		FROM dividends d JOIN stocks s USING (secid)
+		WHERE s.symbol=$1
+		ORDER BY d.exdate`
+	rows, err := c.c.Query(context.Background(), sql, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dh := iex.DividendHistory{Symbol: symbol}
+	for rows.Next() {
+		var d iex.Dividend
+		if err := rows.Scan(
+			&d.DecDate, &d.ExDate, &d.RecDate, &d.PayDate, &d.Amount, &d.Flag, &d.Curr, &d.Freq,
+		); err != nil {
+			return nil, err
+		}
+		dh.Dividends = append(dh.Dividends, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &dh, nil
+}
+
+// SplitHistory reads the stored splits of symbol ordered by exdate
+func (c *Conn) SplitHistory(symbol string) (*iex.SplitHistory, error) {
+	sql := `SELECT sp.decdate::text, sp.exdate::text, sp.tofactor::float8, sp.fromfactor::float8
+		FROM splits sp JOIN stocks s USING (secid)
+		WHERE s.symbol=$1
+		ORDER BY sp.exdate`
+	rows, err := c.c.Query(context.Background(), sql, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sh := iex.SplitHistory{Symbol: symbol}
+	for rows.Next() {
+		var sp iex.Split
+		if err := rows.Scan(&sp.DecDate, &sp.ExDate, &sp.ToFactor, &sp.FromFactor); err != nil {
+			return nil, err
+		}
+		sh.Splits = append(sh.Splits, sp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &sh, nil
+}
+
+// LatestPriceDates maps every active symbol to its most recent stored bar;
+// symbols without bars are left out
+func (c *Conn) LatestPriceDates() (map[string]time.Time, error) {
+	sql := `SELECT s.symbol, max(p.date)
+		FROM stocks s JOIN prices p USING (secid)
+		WHERE s.date_inactive IS NULL
+		GROUP BY s.symbol`
+	rows, err := c.c.Query(context.Background(), sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	latest := make(map[string]time.Time)
+	for rows.Next() {
+		var symbol string
+		var date time.Time
+		if err := rows.Scan(&symbol, &date); err != nil {
+			return nil, err
+		}
+		latest[symbol] = date
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// PriceDates lists the dates of the stored bars of symbol in order
+func (c *Conn) PriceDates(symbol string) ([]time.Time, error) {
+	sql := `SELECT p.date FROM prices p JOIN stocks s USING (secid)
+		WHERE s.symbol=$1
+		ORDER BY p.date`
+	rows, err := c.c.Query(context.Background(), sql, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var d time.Time
+		if err := rows.Scan(&d); err != nil {
+			return nil, err
+		}
+		dates = append(dates, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// EachBar calls fn with every stored bar of symbols between from and to,
+// ordered by symbol then date, as rows arrive rather than collecting them;
+// an error from fn stops the scan and is returned
+func (c *Conn) EachBar(ctx context.Context, symbols []string, from, to string, fn func(symbol string, p iex.Prices) error) error {
+	sql := `SELECT s.symbol, p.date, p.uopen, p.uclose, p.uhigh, p.ulow, p.uvolume,
+		p.aopen, p.aclose, p.ahigh, p.alow, p.avolume, p.adjusted_only
+		FROM prices p JOIN stocks s USING (secid)
+		WHERE s.symbol = ANY($1) AND p.date BETWEEN $2::date AND $3::date
+		ORDER BY s.symbol, p.date`
+	rows, err := c.c.Query(ctx, sql, symbols, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var symbol string
+		var p iex.Prices
+		var date time.Time
+		if err := rows.Scan(
+			&symbol, &date, &p.Uopen, &p.Uclose, &p.Uhigh, &p.Ulow, &p.Uvolume,
+			&p.Aopen, &p.Aclose, &p.Ahigh, &p.Alow, &p.Avolume, &p.AdjustedOnly,
+		); err != nil {
+			return err
+		}
+		p.Date = date.Format(tfmt)
+		if err := fn(symbol, p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,25 @@
+package db
+
+import "testing"
+
+func TestClosesBefore(t *testing.T) {
+	c := testConn(t)
+	seedStock(t, c)
+	closes, err := c.ClosesBefore("AAPL", []string{"2020-01-02", "2020-01-03", "2020-01-05", "2020-02-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nothing precedes the first bar; weekends and later dates take the last close
+	want := map[string]float64{"2020-01-03": 10, "2020-01-05": 11, "2020-02-01": 12}
+	if len(closes) != len(want) {
+		t.Fatalf("got %v, want %v", closes, want)
+	}
+	for d, v := range want {
+		if closes[d] != v {
+			t.Errorf("close before %s = %v, want %v", d, closes[d], v)
+		}
+	}
+	if closes, err := c.ClosesBefore("AAPL", nil); err != nil || len(closes) != 0 {
+		t.Errorf("no dates: got %v, %v", closes, err)
+	}
+}
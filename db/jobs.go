@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Job statuses; dead jobs exhausted their attempts and wait for Requeue
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobDead    = "dead"
+)
+
+// Job is an ingestion task of one symbol, data type and range
+type Job struct {
+	ID          int64
+	Symbol      string
+	Kind        string
+	Range       string
+	Status      string
+	Attempts    int
+	MaxAttempts int
+	LastError   string
+}
+
+// EnqueueJob queues a task unless the same task is already pending;
+// it reports whether a job was added
+func (c *Conn) EnqueueJob(symbol, kind, rng string, maxAttempts int) (bool, error) {
+	sql := `INSERT INTO jobs (symbol, kind, rng, max_attempts) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (symbol, kind, rng) WHERE status IN ('queued', 'running') DO NOTHING`
+	tag, err := c.c.Exec(context.Background(), sql, symbol, kind, rng, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ClaimJob leases the next runnable job to worker, skipping rows other
+// workers hold. Jobs whose lease expired are claimable again; those out of
+// attempts are dead-lettered first. A nil job means nothing is runnable.
+func (c *Conn) ClaimJob(worker string, lease time.Duration) (*Job, error) {
+	expire := `UPDATE jobs SET status='dead', lease_until=NULL, last_error='lease expired'
+		WHERE status='running' AND lease_until < now() AND attempts >= max_attempts`
+	if _, err := c.c.Exec(context.Background(), expire); err != nil {
+		return nil, err
+	}
+	sql := `UPDATE jobs SET status='running', attempts=attempts+1, worker=$1,
+		lease_until=now() + $2::bigint * interval '1 microsecond'
+		WHERE job_id = (
+			SELECT job_id FROM jobs
+			WHERE (status='queued' AND run_after <= now())
+			OR (status='running' AND lease_until < now())
+			ORDER BY run_after, job_id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING job_id, symbol, kind, rng, status, attempts, max_attempts, coalesce(last_error, '')`
+	rows, err := c.c.Query(context.Background(), sql, worker, lease.Microseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	var j Job
+	if err := rows.Scan(&j.ID, &j.Symbol, &j.Kind, &j.Range, &j.Status, &j.Attempts, &j.MaxAttempts, &j.LastError); err != nil {
+		return nil, err
+	}
+	return &j, rows.Err()
+}
+
+// CompleteJob marks a job worker holds as done
+func (c *Conn) CompleteJob(id int64, worker string) error {
+	sql := `UPDATE jobs SET status='done', lease_until=NULL, finished_at=now()
+		WHERE job_id=$1 AND worker=$2 AND status='running'`
+	_, err := c.c.Exec(context.Background(), sql, id, worker)
+	return err
+}
+
+// FailJob requeues a job worker holds to run after backoff, or dead-letters
+// it once its attempts are spent
+func (c *Conn) FailJob(id int64, worker string, backoff time.Duration, reason string) error {
+	sql := `UPDATE jobs SET
+		status=CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'queued' END,
+		run_after=now() + $3::bigint * interval '1 microsecond',
+		lease_until=NULL, last_error=$4
+		WHERE job_id=$1 AND worker=$2 AND status='running'`
+	_, err := c.c.Exec(context.Background(), sql, id, worker, backoff.Microseconds(), reason)
+	return err
+}
+
+// RequeueJob gives a dead job a fresh set of attempts
+func (c *Conn) RequeueJob(id int64) error {
+	sql := `UPDATE jobs SET status='queued', attempts=0, run_after=now()
+		WHERE job_id=$1 AND status='dead'`
+	_, err := c.c.Exec(context.Background(), sql, id)
+	return err
+}
+
+// JobCounts counts jobs by status
+func (c *Conn) JobCounts() (map[string]int, error) {
+	rows, err := c.c.Query(context.Background(), `SELECT status, count(*) FROM jobs GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, err
+		}
+		counts[status] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// DeadJobs lists the dead-lettered jobs
+func (c *Conn) DeadJobs() ([]Job, error) {
+	sql := `SELECT job_id, symbol, kind, rng, status, attempts, max_attempts, coalesce(last_error, '')
+		FROM jobs WHERE status='dead' ORDER BY job_id`
+	rows, err := c.c.Query(context.Background(), sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var j Job
+		if err := rows.Scan(&j.ID, &j.Symbol, &j.Kind, &j.Range, &j.Status, &j.Attempts, &j.MaxAttempts, &j.LastError); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ReleaseJob hands back a job worker claimed but never started, without
+// spending an attempt
+func (c *Conn) ReleaseJob(id int64, worker string) error {
+	sql := `UPDATE jobs SET status='queued', attempts=attempts-1, lease_until=NULL
+		WHERE job_id=$1 AND worker=$2 AND status='running'`
+	_, err := c.c.Exec(context.Background(), sql, id, worker)
+	return err
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// TryLock takes the session advisory lock named name without waiting. The
+// lock lives on a connection held out of the pool until Unlock.
+func (c *Conn) TryLock(name string) (bool, error) {
+	conn, err := c.c.Acquire(context.Background())
+	if err != nil {
+		return false, err
+	}
+	var ok bool
+	if err := conn.QueryRow(context.Background(), `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&ok); err != nil {
+		conn.Release()
+		return false, err
+	}
+	if !ok {
+		conn.Release()
+		return false, nil
+	}
+	c.mu.Lock()
+	c.locks[name] = conn
+	c.mu.Unlock()
+	return true, nil
+}
+
+// Unlock releases the session advisory lock named name
+func (c *Conn) Unlock(name string) error {
+	c.mu.Lock()
+	conn, ok := c.locks[name]
+	delete(c.locks, name)
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("lock %q is not held", name)
+	}
+	defer conn.Release()
+	_, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name)
+	return err
+}
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS raw_payloads (
+	id bigserial PRIMARY KEY,
+	run_id varchar(40) NOT NULL,
+	endpoint varchar(200) NOT NULL,
+	fetched_at timestamptz NOT NULL,
+	payload jsonb NOT NULL
+);
+
+CREATE INDEX ON raw_payloads (run_id);
+CREATE INDEX ON raw_payloads (endpoint, fetched_at DESC);
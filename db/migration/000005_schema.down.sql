@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS price_discrepancies;
+DROP TABLE IF EXISTS source_prices;
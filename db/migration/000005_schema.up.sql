@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS source_prices (
+	source varchar(20) NOT NULL,
+	date date NOT NULL,
+	secid integer REFERENCES stocks (secid),
+	uopen numeric(8, 2),
+	uclose numeric(8, 2),
+	uhigh numeric(8, 2),
+	ulow numeric(8, 2),
+	uvolume integer,
+	aopen numeric(8, 2),
+	aclose numeric(8, 2),
+	ahigh numeric(8, 2),
+	alow numeric(8, 2),
+	avolume integer,
+	PRIMARY KEY (source, date, secid)
+);
+
+CREATE INDEX ON source_prices (secid, date DESC);
+
+CREATE TABLE IF NOT EXISTS price_discrepancies (
+	date date NOT NULL,
+	secid integer REFERENCES stocks (secid),
+	source_a varchar(20) NOT NULL,
+	source_b varchar(20) NOT NULL,
+	field varchar(10) NOT NULL,
+	value_a numeric,
+	value_b numeric,
+	deviation numeric,
+	detected_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (date, secid, source_a, source_b, field)
+);
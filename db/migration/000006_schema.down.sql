@@ -0,0 +1 @@
+DROP TABLE IF EXISTS total_return_index;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS total_return_index (
+	date date,
+	secid integer REFERENCES stocks (secid),
+	value numeric(18, 6),
+	PRIMARY KEY (date, secid)
+);
+
+CREATE INDEX ON total_return_index (secid, date DESC);
+
+SELECT
+	create_hypertable ('total_return_index',
+		'date',
+		create_default_indexes => FALSE,
+		chunk_time_interval => interval '1 day');
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS data_quality_issues (
+	id bigserial PRIMARY KEY,
+	secid integer REFERENCES stocks (secid),
+	date date NOT NULL,
+	rule varchar(20) NOT NULL,
+	severity varchar(10) NOT NULL,
+	detail varchar(200),
+	value numeric,
+	detected_at timestamptz NOT NULL DEFAULT now(),
+	UNIQUE (secid, date, rule)
+);
+
+CREATE INDEX ON data_quality_issues (severity, secid);
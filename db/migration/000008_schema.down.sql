@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS quarantine;
+DROP TABLE IF EXISTS staging_rows;
+DROP TABLE IF EXISTS staging_batches;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS staging_batches (
+	batch_id bigserial PRIMARY KEY,
+	run_id varchar(40),
+	kind varchar(20) NOT NULL,
+	symbol varchar(6) NOT NULL,
+	status varchar(12) NOT NULL DEFAULT 'staged',
+	created_at timestamptz NOT NULL DEFAULT now(),
+	promoted_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS staging_rows (
+	batch_id bigint REFERENCES staging_batches (batch_id) ON DELETE CASCADE,
+	rowno integer,
+	payload jsonb NOT NULL,
+	PRIMARY KEY (batch_id, rowno)
+);
+
+CREATE TABLE IF NOT EXISTS quarantine (
+	qid bigserial PRIMARY KEY,
+	batch_id bigint REFERENCES staging_batches (batch_id),
+	kind varchar(20) NOT NULL,
+	symbol varchar(6) NOT NULL,
+	payload jsonb NOT NULL,
+	reasons text,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	released_at timestamptz
+);
+
+CREATE INDEX ON quarantine (released_at, symbol);
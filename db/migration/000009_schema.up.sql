@@ -0,0 +1 @@
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS last_refreshed timestamptz;
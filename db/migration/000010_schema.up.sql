@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS jobs (
+	job_id bigserial PRIMARY KEY,
+	symbol varchar(6) NOT NULL,
+	kind varchar(20) NOT NULL,
+	rng varchar(10) NOT NULL,
+	status varchar(12) NOT NULL DEFAULT 'queued',
+	attempts integer NOT NULL DEFAULT 0,
+	max_attempts integer NOT NULL DEFAULT 5,
+	run_after timestamptz NOT NULL DEFAULT now(),
+	lease_until timestamptz,
+	worker varchar(64),
+	last_error text,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	finished_at timestamptz
+);
+
+CREATE INDEX ON jobs (status, run_after);
+
+-- a task is queued at most once while pending
+CREATE UNIQUE INDEX ON jobs (symbol, kind, rng) WHERE status IN ('queued', 'running');
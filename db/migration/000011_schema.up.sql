@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS token_buckets (
+	name varchar(40) PRIMARY KEY,
+	tokens float8 NOT NULL,
+	updated_at timestamptz NOT NULL
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS fetches (
+	secid integer REFERENCES stocks (secid),
+	kind varchar(20),
+	fetched_at timestamptz NOT NULL,
+	PRIMARY KEY (secid, kind)
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS api_tokens (
+	token_hash char(64) PRIMARY KEY,
+	name varchar(64) NOT NULL UNIQUE,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	last_used_at timestamptz,
+	revoked_at timestamptz
+);
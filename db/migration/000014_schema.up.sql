@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+	webhook_id serial PRIMARY KEY,
+	url text NOT NULL,
+	-- event kinds delivered; empty delivers every kind
+	events text[] NOT NULL DEFAULT '{}',
+	secret text NOT NULL,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	disabled_at timestamptz
+);
+
+-- queued in the transaction that produced the event
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	delivery_id bigserial PRIMARY KEY,
+	webhook_id integer NOT NULL REFERENCES webhooks (webhook_id),
+	kind varchar(20) NOT NULL,
+	payload jsonb NOT NULL,
+	status varchar(12) NOT NULL DEFAULT 'queued',
+	attempts integer NOT NULL DEFAULT 0,
+	max_attempts integer NOT NULL DEFAULT 8,
+	run_after timestamptz NOT NULL DEFAULT now(),
+	lease_until timestamptz,
+	worker varchar(64),
+	last_error text,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	delivered_at timestamptz
+);
+
+CREATE INDEX ON webhook_deliveries (status, run_after);
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+	delivery_id bigint REFERENCES webhook_deliveries (delivery_id),
+	attempt integer,
+	attempted_at timestamptz NOT NULL DEFAULT now(),
+	status_code integer,
+	error text,
+	duration_ms integer NOT NULL,
+	PRIMARY KEY (delivery_id, attempt)
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS dividends_key;
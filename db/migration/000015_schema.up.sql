@@ -0,0 +1,7 @@
+DELETE FROM dividends a USING dividends b
+WHERE a.secid = b.secid AND a.exdate = b.exdate
+	AND a.amount IS NOT DISTINCT FROM b.amount
+	AND a.flag IS NOT DISTINCT FROM b.flag
+	AND a.divid < b.divid;
+
+CREATE UNIQUE INDEX dividends_key ON dividends (secid, exdate, (coalesce(amount, -1)), (coalesce(flag, '')));
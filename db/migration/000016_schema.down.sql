@@ -0,0 +1 @@
+DROP TABLE IF EXISTS empty_sessions;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS empty_sessions (
+	secid integer REFERENCES stocks (secid),
+	date date,
+	confirmed_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY (secid, date)
+);
@@ -0,0 +1 @@
+ALTER TABLE prices DROP COLUMN IF EXISTS adjusted_only;
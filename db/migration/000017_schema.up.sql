@@ -0,0 +1 @@
+ALTER TABLE prices ADD COLUMN IF NOT EXISTS adjusted_only boolean NOT NULL DEFAULT false;
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Issue is a data quality finding as stored in data_quality_issues
+type Issue struct {
+	Date     string
+	Rule     string
+	Severity string
+	Detail   string
+	Value    sql.NullFloat64
+}
+
+// ReplaceIssues swaps the findings of rules for symbol between from and to with issues
+func (c *Conn) ReplaceIssues(symbol, from, to string, rules []string, issues []Issue) error {
+	sql := `INSERT INTO data_quality_issues (secid, date, rule, severity, detail, value)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (secid, date, rule) DO UPDATE SET
+		severity=EXCLUDED.severity, detail=EXCLUDED.detail, value=EXCLUDED.value, detected_at=now()`
+	secid, err := c.FindSecurityID(symbol)
+	if err != nil {
+		return err
+	}
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	if _, err := tx.Exec(context.Background(),
+		`DELETE FROM data_quality_issues
+		WHERE secid=$1 AND rule=ANY($2) AND date BETWEEN $3::date AND $4::date`,
+		secid, rules, from, to,
+	); err != nil {
+		return err
+	}
+	for _, is := range issues {
+		if _, err := tx.Exec(context.Background(), sql,
+			secid, is.Date, is.Rule, is.Severity, is.Detail, is.Value,
+		); err != nil {
+			return fmt.Errorf("insertion error: %s(issue:%v)", symbol, is)
+		}
+	}
+	return tx.Commit(context.Background())
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Bucket is a token bucket kept in token_buckets, so every process naming
+// the same bucket shares one rate. It satisfies iex.Limiter.
+type Bucket struct {
+	c     *Conn
+	name  string
+	every time.Duration
+	burst int
+}
+
+// Bucket returns the shared bucket name refilling a token every interval,
+// holding at most burst
+func (c *Conn) Bucket(name string, every time.Duration, burst int) *Bucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Bucket{c: c, name: name, every: every, burst: burst}
+}
+
+// Wait takes a token, sleeping until it is due. The take always succeeds and
+// may drive the bucket negative; the debt is what the caller sleeps off, so
+// concurrent callers queue up behind each other. Timing uses the database
+// clock so hosts agree.
+func (b *Bucket) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if b.every <= 0 {
+		return nil
+	}
+	// clock_timestamp() rather than now(): now() is when the statement began,
+	// before it queued for the row lock, so updated_at could move backwards
+	// and the same seconds be refilled twice
+	sql := `INSERT INTO token_buckets (name, tokens, updated_at) VALUES ($1, $2::float8 - 1, clock_timestamp())
+		ON CONFLICT (name) DO UPDATE SET
+		tokens = least($2::float8, token_buckets.tokens
+			+ extract(epoch FROM clock_timestamp() - token_buckets.updated_at)::float8 / $3::float8) - 1,
+		updated_at = clock_timestamp()
+		RETURNING tokens`
+	var tokens float64
+	if err := b.c.c.QueryRow(context.Background(), sql,
+		b.name, float64(b.burst), b.every.Seconds(),
+	).Scan(&tokens); err != nil {
+		return err
+	}
+	if tokens >= 0 {
+		return nil
+	}
+	t := time.NewTimer(time.Duration(-tokens * float64(b.every)))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// RefreshTimes maps every active symbol to its last deep refresh;
+// symbols never deep refreshed map to the zero time
+func (c *Conn) RefreshTimes() (map[string]time.Time, error) {
+	sql := `SELECT symbol, last_refreshed FROM stocks WHERE date_inactive IS NULL`
+	rows, err := c.c.Query(context.Background(), sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refreshed := make(map[string]time.Time)
+	for rows.Next() {
+		var symbol string
+		var at *time.Time
+		if err := rows.Scan(&symbol, &at); err != nil {
+			return nil, err
+		}
+		refreshed[symbol] = time.Time{}
+		if at != nil {
+			refreshed[symbol] = *at
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return refreshed, nil
+}
+
+// MarkRefreshed records a completed deep refresh of symbol
+func (c *Conn) MarkRefreshed(symbol string, at time.Time) error {
+	sql := `UPDATE stocks SET last_refreshed=$2 WHERE symbol=$1`
+	_, err := c.c.Exec(context.Background(), sql, symbol, at)
+	return err
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// IndexPoint is a stored value of a total return index
+type IndexPoint struct {
+	Date  string
+	Value float64
+}
+
+// LastTotalReturn finds the latest stored total return index point of symbol
+func (c *Conn) LastTotalReturn(symbol string) (IndexPoint, bool, error) {
+	sql := `SELECT t.date, t.value::float8
+		FROM total_return_index t JOIN stocks s USING (secid)
+		WHERE s.symbol=$1
+		ORDER BY t.date DESC LIMIT 1`
+	var point IndexPoint
+	var date time.Time
+	err := c.c.QueryRow(context.Background(), sql, symbol).Scan(&date, &point.Value)
+	if err == pgx.ErrNoRows {
+		return point, false, nil
+	}
+	if err != nil {
+		return point, false, err
+	}
+	point.Date = date.Format(tfmt)
+	return point, true, nil
+}
+
+// InsertTotalReturn upserts total return index points for symbol
+func (c *Conn) InsertTotalReturn(symbol string, points []IndexPoint) error {
+	if len(points) == 0 {
+		return nil
+	}
+	sql := `INSERT INTO total_return_index (date, secid, value)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (date, secid) DO UPDATE SET value=EXCLUDED.value`
+	secid, err := c.FindSecurityID(symbol)
+	if err != nil {
+		return err
+	}
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	for _, p := range points {
+		if _, err := tx.Exec(context.Background(), sql, p.Date, secid, p.Value); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(context.Background())
+}
+
+// DeleteTotalReturn drops the stored index of symbol so it can be rebuilt
+func (c *Conn) DeleteTotalReturn(symbol string) error {
+	sql := `DELETE FROM total_return_index
+		WHERE secid=(SELECT secid FROM stocks WHERE symbol=$1)`
+	_, err := c.c.Exec(context.Background(), sql, symbol)
+	return err
+}
+
+// truncateTotalReturn drops the index points of secid from date on within tx;
+// they were computed without a bar or corporate action stored since, and the
+// next incremental update recomputes them from the last point left
+func truncateTotalReturn(tx pgx.Tx, secid int, date string) error {
+	_, err := tx.Exec(context.Background(),
+		`DELETE FROM total_return_index WHERE secid=$1 AND date >= $2::date`, secid, date,
+	)
+	return err
+}
@@ -0,0 +1,217 @@
+package db
+
+import (
+	"context"
+	"defcor/iex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// QuarantinedRow is a staged row held back from production tables
+type QuarantinedRow struct {
+	QID        int64
+	BatchID    int64
+	Kind       string
+	Symbol     string
+	Payload    []byte
+	Reasons    string
+	CreatedAt  time.Time
+	ReleasedAt *time.Time
+}
+
+// StageBatch lands the json encoded rows of one fetch in the staging area
+func (c *Conn) StageBatch(runID, kind, symbol string, rows [][]byte) (int64, error) {
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	var batchID int64
+	if err := tx.QueryRow(context.Background(),
+		`INSERT INTO staging_batches (run_id, kind, symbol) VALUES ($1, $2, $3) RETURNING batch_id`,
+		runID, kind, symbol,
+	).Scan(&batchID); err != nil {
+		return 0, err
+	}
+	for i, row := range rows {
+		if _, err := tx.Exec(context.Background(),
+			`INSERT INTO staging_rows (batch_id, rowno, payload) VALUES ($1, $2, $3)`,
+			batchID, i, string(row),
+		); err != nil {
+			return 0, err
+		}
+	}
+	return batchID, tx.Commit(context.Background())
+}
+
+// PromoteBatch moves the accepted rows of a staged batch into the production
+// table and the rejected rows, keyed by row number, into quarantine.
+// accepted is a *iex.PriceHistory, *iex.DividendHistory or *iex.SplitHistory.
+func (c *Conn) PromoteBatch(batchID int64, symbol string, accepted interface{}, rejected map[int]string) error {
+	secid, err := c.FindSecurityID(symbol)
+	if err != nil {
+		return err
+	}
+	defer timeTx("staging")()
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	table, w, err := insertAccepted(tx, secid, accepted)
+	if err != nil {
+		return err
+	}
+	for rowno, reasons := range rejected {
+		if _, err := tx.Exec(context.Background(),
+			`INSERT INTO quarantine (batch_id, kind, symbol, payload, reasons)
+			SELECT b.batch_id, b.kind, b.symbol, r.payload, $3
+			FROM staging_rows r JOIN staging_batches b USING (batch_id)
+			WHERE r.batch_id=$1 AND r.rowno=$2`,
+			batchID, rowno, reasons,
+		); err != nil {
+			return err
+		}
+	}
+	status := "promoted"
+	if len(rejected) > 0 {
+		status = "partial"
+	}
+	if _, err := tx.Exec(context.Background(),
+		`UPDATE staging_batches SET status=$2, promoted_at=now() WHERE batch_id=$1`, batchID, status,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(context.Background(),
+		`DELETE FROM staging_rows WHERE batch_id=$1`, batchID,
+	); err != nil {
+		return err
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+	c.written(table, symbol, w)
+	c.written("quarantine", symbol, writes{inserted: len(rejected)})
+	c.log.Info("batch promoted", "batch", batchID, "symbol", symbol, "status", status)
+	return nil
+}
+
+// insertAccepted writes accepted to its table and names the table
+func insertAccepted(tx pgx.Tx, secid int, accepted interface{}) (string, writes, error) {
+	switch h := accepted.(type) {
+	case *iex.PriceHistory:
+		w, err := insertPrices(tx, secid, h)
+		return "prices", w, err
+	case *iex.DividendHistory:
+		if h.IsEmpty() {
+			return "dividends", writes{}, nil
+		}
+		w, err := insertDividends(tx, secid, h)
+		return "dividends", w, err
+	case *iex.SplitHistory:
+		w, err := insertSplits(tx, secid, h)
+		return "splits", w, err
+	}
+	return "", writes{}, fmt.Errorf("cannot promote %T", accepted)
+}
+
+// Quarantined lists quarantined rows, optionally including released ones
+func (c *Conn) Quarantined(includeReleased bool) ([]QuarantinedRow, error) {
+	sql := `SELECT qid, batch_id, kind, symbol, payload::text, coalesce(reasons, ''), created_at, released_at
+		FROM quarantine
+		WHERE $1 OR released_at IS NULL
+		ORDER BY qid`
+	rows, err := c.c.Query(context.Background(), sql, includeReleased)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var qs []QuarantinedRow
+	for rows.Next() {
+		var q QuarantinedRow
+		var payload string
+		if err := rows.Scan(
+			&q.QID, &q.BatchID, &q.Kind, &q.Symbol, &payload, &q.Reasons, &q.CreatedAt, &q.ReleasedAt,
+		); err != nil {
+			return nil, err
+		}
+		q.Payload = []byte(payload)
+		qs = append(qs, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+// ReleaseQuarantine promotes a reviewed quarantined row into its production table
+func (c *Conn) ReleaseQuarantine(qid int64) error {
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	var kind, symbol, payload string
+	var secid int
+	if err := tx.QueryRow(context.Background(),
+		`SELECT q.kind, q.symbol, q.payload::text, s.secid
+		FROM quarantine q JOIN stocks s USING (symbol)
+		WHERE q.qid=$1 AND q.released_at IS NULL
+		FOR UPDATE OF q`, qid,
+	).Scan(&kind, &symbol, &payload, &secid); err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("quarantined row %d not found or already released", qid)
+		}
+		return err
+	}
+	accepted, err := decodeRow(kind, symbol, []byte(payload))
+	if err != nil {
+		return err
+	}
+	table, w, err := insertAccepted(tx, secid, accepted)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(context.Background(),
+		`UPDATE quarantine SET released_at=now() WHERE qid=$1`, qid,
+	); err != nil {
+		return err
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+	c.written(table, symbol, w)
+	return nil
+}
+
+// decodeRow turns one staged payload back into a single row history
+func decodeRow(kind, symbol string, payload []byte) (interface{}, error) {
+	switch kind {
+	case iex.KindPrices:
+		var p iex.Prices
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, err
+		}
+		return &iex.PriceHistory{Symbol: symbol, Prices: []iex.Prices{p}}, nil
+	case iex.KindDividends:
+		var d iex.Dividend
+		if err := json.Unmarshal(payload, &d); err != nil {
+			return nil, err
+		}
+		return &iex.DividendHistory{Symbol: symbol, Dividends: []iex.Dividend{d}}, nil
+	case iex.KindSplits:
+		var s iex.Split
+		if err := json.Unmarshal(payload, &s); err != nil {
+			return nil, err
+		}
+		return &iex.SplitHistory{Symbol: symbol, Splits: []iex.Split{s}}, nil
+	}
+	return nil, fmt.Errorf("unknown staged kind %q", kind)
+}
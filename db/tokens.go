@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// APIToken is a named credential of the rest api; only its hash is stored
+type APIToken struct {
+	Name       string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// CreateToken stores the sha256 hex hash of a new token under name
+func (c *Conn) CreateToken(name, hash string) error {
+	sql := `INSERT INTO api_tokens (token_hash, name) VALUES ($1, $2)`
+	_, err := c.c.Exec(context.Background(), sql, hash, name)
+	return err
+}
+
+// RevokeToken disables the token called name
+func (c *Conn) RevokeToken(name string) error {
+	sql := `UPDATE api_tokens SET revoked_at=now() WHERE name=$1 AND revoked_at IS NULL`
+	tag, err := c.c.Exec(context.Background(), sql, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CheckToken returns the name of the live token with hash and marks it used
+func (c *Conn) CheckToken(hash string) (string, error) {
+	sql := `UPDATE api_tokens SET last_used_at=now()
+		WHERE token_hash=$1 AND revoked_at IS NULL
+		RETURNING name`
+	rows, err := c.c.Query(context.Background(), sql, hash)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", ErrNotFound
+	}
+	var name string
+	if err := rows.Scan(&name); err != nil {
+		return "", err
+	}
+	return name, rows.Err()
+}
+
+// Tokens lists every token, revoked ones included
+func (c *Conn) Tokens() ([]APIToken, error) {
+	sql := `SELECT name, created_at, last_used_at, revoked_at FROM api_tokens ORDER BY name`
+	rows, err := c.c.Query(context.Background(), sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []APIToken
+	for rows.Next() {
+		var t APIToken
+		if err := rows.Scan(&t.Name, &t.CreatedAt, &t.LastUsedAt, &t.RevokedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Webhook is a subscription posting events of Events kinds, or every kind
+// when empty, to URL signed with Secret
+type Webhook struct {
+	ID         int
+	URL        string
+	Events     []string
+	Secret     string
+	CreatedAt  time.Time
+	DisabledAt *time.Time
+}
+
+// Delivery statuses; delivered, dead and cancelled deliveries are final
+const (
+	DeliveryQueued    = "queued"
+	DeliverySending   = "sending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+	DeliveryCancelled = "cancelled"
+)
+
+// Delivery is one event bound for one webhook
+type Delivery struct {
+	ID          int64
+	WebhookID   int
+	URL         string
+	Secret      string
+	Kind        string
+	Payload     []byte
+	Status      string
+	Attempts    int
+	MaxAttempts int
+	LastError   string
+}
+
+// DeliveryAttempt is an entry of the delivery log
+type DeliveryAttempt struct {
+	DeliveryID  int64
+	WebhookID   int
+	Kind        string
+	Attempt     int
+	AttemptedAt time.Time
+	StatusCode  *int
+	Error       string
+	Duration    time.Duration
+}
+
+// CreateWebhook subscribes url to events, every kind if none, and returns its id
+func (c *Conn) CreateWebhook(url string, events []string, secret string) (int, error) {
+	if events == nil {
+		events = []string{}
+	}
+	sql := `INSERT INTO webhooks (url, events, secret) VALUES ($1, $2, $3) RETURNING webhook_id`
+	var id int
+	err := c.c.QueryRow(context.Background(), sql, url, events, secret).Scan(&id)
+	return id, err
+}
+
+// DisableWebhook stops a subscription and cancels its pending deliveries
+func (c *Conn) DisableWebhook(id int) error {
+	tx, err := c.c.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	// no-op on successful tx commit
+	defer tx.Rollback(context.Background())
+	tag, err := tx.Exec(context.Background(),
+		`UPDATE webhooks SET disabled_at=now() WHERE webhook_id=$1 AND disabled_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.Exec(context.Background(),
+		`UPDATE webhook_deliveries SET status='cancelled', lease_until=NULL
+		WHERE webhook_id=$1 AND status IN ('queued', 'sending')`, id,
+	); err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+// Webhooks lists every subscription, disabled ones included
+func (c *Conn) Webhooks() ([]Webhook, error) {
+	sql := `SELECT webhook_id, url, events, secret, created_at, disabled_at FROM webhooks ORDER BY webhook_id`
+	rows, err := c.c.Query(context.Background(), sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hooks []Webhook
+	for rows.Next() {
+		var h Webhook
+		if err := rows.Scan(&h.ID, &h.URL, &h.Events, &h.Secret, &h.CreatedAt, &h.DisabledAt); err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+// ClaimDelivery leases the next due delivery to worker the way ClaimJob
+// leases jobs. A nil delivery means nothing is due.
+func (c *Conn) ClaimDelivery(worker string, lease time.Duration) (*Delivery, error) {
+	expire := `UPDATE webhook_deliveries SET status='dead', lease_until=NULL, last_error='lease expired'
+		WHERE status='sending' AND lease_until < now() AND attempts >= max_attempts`
+	if _, err := c.c.Exec(context.Background(), expire); err != nil {
+		return nil, err
+	}
+	sql := `UPDATE webhook_deliveries d SET status='sending', attempts=d.attempts+1, worker=$1,
+		lease_until=now() + $2::bigint * interval '1 microsecond'
+		FROM webhooks w
+		WHERE w.webhook_id = d.webhook_id AND d.delivery_id = (
+			SELECT delivery_id FROM webhook_deliveries
+			WHERE (status='queued' AND run_after <= now())
+			OR (status='sending' AND lease_until < now())
+			ORDER BY run_after, delivery_id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING d.delivery_id, d.webhook_id, w.url, w.secret, d.kind, d.payload::text,
+		d.status, d.attempts, d.max_attempts, coalesce(d.last_error, '')`
+	rows, err := c.c.Query(context.Background(), sql, worker, lease.Microseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	var d Delivery
+	var payload string
+	if err := rows.Scan(&d.ID, &d.WebhookID, &d.URL, &d.Secret, &d.Kind, &payload,
+		&d.Status, &d.Attempts, &d.MaxAttempts, &d.LastError); err != nil {
+		return nil, err
+	}
+	d.Payload = []byte(payload)
+	return &d, rows.Err()
+}
+
+// RecordAttempt adds a try of a delivery to the delivery log; status is 0
+// when no response arrived
+func (c *Conn) RecordAttempt(id int64, attempt, status int, took time.Duration, reason string) error {
+	sql := `INSERT INTO webhook_attempts (delivery_id, attempt, status_code, error, duration_ms)
+		VALUES ($1, $2, nullif($3, 0), nullif($4, ''), $5)`
+	_, err := c.c.Exec(context.Background(), sql, id, attempt, status, reason, took.Milliseconds())
+	return err
+}
+
+// CompleteDelivery marks a delivery worker holds as delivered
+func (c *Conn) CompleteDelivery(id int64, worker string) error {
+	sql := `UPDATE webhook_deliveries SET status='delivered', lease_until=NULL, delivered_at=now()
+		WHERE delivery_id=$1 AND worker=$2 AND status='sending'`
+	_, err := c.c.Exec(context.Background(), sql, id, worker)
+	return err
+}
+
+// FailDelivery retries a delivery worker holds after backoff, or
+// dead-letters it once its attempts are spent
+func (c *Conn) FailDelivery(id int64, worker string, backoff time.Duration, reason string) error {
+	sql := `UPDATE webhook_deliveries SET
+		status=CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'queued' END,
+		run_after=now() + $3::bigint * interval '1 microsecond',
+		lease_until=NULL, last_error=$4
+		WHERE delivery_id=$1 AND worker=$2 AND status='sending'`
+	_, err := c.c.Exec(context.Background(), sql, id, worker, backoff.Microseconds(), reason)
+	return err
+}
+
+// ReleaseDelivery hands back a delivery worker claimed but never sent
+func (c *Conn) ReleaseDelivery(id int64, worker string) error {
+	sql := `UPDATE webhook_deliveries SET status='queued', attempts=attempts-1, lease_until=NULL
+		WHERE delivery_id=$1 AND worker=$2 AND status='sending'`
+	_, err := c.c.Exec(context.Background(), sql, id, worker)
+	return err
+}
+
+// DeliveryAttempts is how many tries a delivery gets, the max_attempts default
+const DeliveryAttempts = 8
+
+// Redeliver gives a dead delivery a fresh set of attempts; attempts keeps
+// counting so the delivery log numbers its tries without repeats
+func (c *Conn) Redeliver(id int64) error {
+	sql := `UPDATE webhook_deliveries SET status='queued', max_attempts=attempts + $2, run_after=now()
+		WHERE delivery_id=$1 AND status='dead'`
+	tag, err := c.c.Exec(context.Background(), sql, id, DeliveryAttempts)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeliveryLog lists the latest limit delivery attempts, newest first
+func (c *Conn) DeliveryLog(limit int) ([]DeliveryAttempt, error) {
+	sql := `SELECT a.delivery_id, d.webhook_id, d.kind, a.attempt, a.attempted_at, a.status_code,
+		coalesce(a.error, ''), a.duration_ms
+		FROM webhook_attempts a JOIN webhook_deliveries d USING (delivery_id)
+		ORDER BY a.attempted_at DESC, a.delivery_id DESC
+		LIMIT $1`
+	rows, err := c.c.Query(context.Background(), sql, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var log []DeliveryAttempt
+	for rows.Next() {
+		var a DeliveryAttempt
+		var ms int64
+		if err := rows.Scan(&a.DeliveryID, &a.WebhookID, &a.Kind, &a.Attempt, &a.AttemptedAt,
+			&a.StatusCode, &a.Error, &ms); err != nil {
+			return nil, err
+		}
+		a.Duration = time.Duration(ms) * time.Millisecond
+		log = append(log, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"defcor/logging"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// testConn connects to DEFCOR_TEST_DATABASE_URL, skipping the test when it
+// is unset. Each test gets a schema of its own with the migrations applied,
+// dropped when it ends, so it neither sees nor changes the database's data.
+func testConn(t *testing.T) *Conn {
+	url := os.Getenv("DEFCOR_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("DEFCOR_TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	admin, err := pgxpool.Connect(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(admin.Close)
+	schema := fmt.Sprintf("defcor_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(ctx, `DROP SCHEMA `+schema+` CASCADE`); err != nil {
+			t.Errorf("dropping %s: %v", schema, err)
+		}
+	})
+
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// extensions such as timescaledb stay reachable in public
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema + ", public"
+	pool, err := pgxpool.ConnectConfig(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Conn{c: pool, log: logging.Default(), locks: make(map[string]*pgxpool.Conn)}
+	t.Cleanup(func() { c.Close() })
+	ups, err := filepath.Glob(filepath.Join("migration", "*.up.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(ups)
+	for _, up := range ups {
+		migration, err := ioutil.ReadFile(up)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pool.Exec(ctx, string(migration)); err != nil {
+			t.Fatalf("applying %s: %v", up, err)
+		}
+	}
+	return c
+}
+
+func deliveryStatus(t *testing.T, c *Conn, id int64) (string, int) {
+	var status string
+	var attempts int
+	err := c.c.QueryRow(context.Background(),
+		`SELECT status, attempts FROM webhook_deliveries WHERE delivery_id=$1`, id,
+	).Scan(&status, &attempts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return status, attempts
+}
+
+func TestFailDeliveryRetriesThenDeadLetters(t *testing.T) {
+	c := testConn(t)
+	hook, err := c.CreateWebhook("http://127.0.0.1:1/hook", []string{EventRunComplete}, "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RunComplete("test-run", "update", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// fail claims and record each try as the deliver loop does
+	fail := func(want int) int64 {
+		d, err := c.ClaimDelivery("tester", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d == nil || d.WebhookID != hook {
+			t.Fatalf("claimed %+v, want a delivery to webhook %d", d, hook)
+		}
+		if d.Attempts != want {
+			t.Fatalf("attempt %d, want %d", d.Attempts, want)
+		}
+		if err := c.RecordAttempt(d.ID, d.Attempts, 500, time.Millisecond, "500 Internal Server Error"); err != nil {
+			t.Fatalf("recording attempt %d: %v", d.Attempts, err)
+		}
+		if err := c.FailDelivery(d.ID, "tester", 0, "500 Internal Server Error"); err != nil {
+			t.Fatal(err)
+		}
+		return d.ID
+	}
+	var id int64
+	for attempt := 1; attempt < DeliveryAttempts; attempt++ {
+		id = fail(attempt)
+		if status, _ := deliveryStatus(t, c, id); status != DeliveryQueued {
+			t.Fatalf("after attempt %d status %s, want %s", attempt, status, DeliveryQueued)
+		}
+	}
+	fail(DeliveryAttempts)
+	if status, attempts := deliveryStatus(t, c, id); status != DeliveryDead || attempts != DeliveryAttempts {
+		t.Fatalf("status %s after %d attempts, want %s after %d", status, attempts, DeliveryDead, DeliveryAttempts)
+	}
+	if d, err := c.ClaimDelivery("tester", time.Minute); err != nil || d != nil {
+		t.Fatalf("claimed %+v, %v from a dead letter", d, err)
+	}
+
+	// a redelivery keeps numbering its attempts after the spent ones
+	if err := c.Redeliver(id); err != nil {
+		t.Fatal(err)
+	}
+	fail(DeliveryAttempts + 1)
+	if status, _ := deliveryStatus(t, c, id); status != DeliveryQueued {
+		t.Errorf("redelivered status %s, want %s", status, DeliveryQueued)
+	}
+}
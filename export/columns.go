@@ -0,0 +1,127 @@
+package export
+
+import (
+	"defcor/iex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// column is a field of a row type as exported: its json name, where it sits
+// in the row and the parquet type it is written as
+type column struct {
+	name     string
+	index    []int
+	parquet  string
+	optional bool
+}
+
+var (
+	nullStringType = reflect.TypeOf(iex.NullString{})
+	nullNumberType = reflect.TypeOf(iex.NullNumber{})
+	nullInt64Type  = reflect.TypeOf(iex.NullInt64{})
+)
+
+// columnsOf lists the exported fields of struct type t in order, flattening
+// embedded structs the way encoding/json does
+func columnsOf(t reflect.Type) []column {
+	var cols []column
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for _, c := range columnsOf(f.Type) {
+				c.index = append([]int{i}, c.index...)
+				cols = append(cols, c)
+			}
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		c := column{name: name, index: []int{i}}
+		switch typ := f.Type; {
+		case typ == nullStringType:
+			c.parquet, c.optional = "UTF8", true
+		case typ == nullNumberType:
+			c.parquet, c.optional = "DOUBLE", true
+		case typ == nullInt64Type:
+			c.parquet, c.optional = "INT64", true
+		default:
+			if typ.Kind() == reflect.Ptr {
+				typ, c.optional = typ.Elem(), true
+			}
+			switch typ.Kind() {
+			case reflect.String:
+				c.parquet = "UTF8"
+			case reflect.Int, reflect.Int32, reflect.Int64:
+				c.parquet = "INT64"
+			case reflect.Float64:
+				c.parquet = "DOUBLE"
+			default:
+				panic(fmt.Sprintf("export: cannot write %s.%s of type %s", t.Name(), f.Name, f.Type))
+			}
+		}
+		cols = append(cols, c)
+	}
+	return cols
+}
+
+// values reads the columns of row as string, int64 or float64, nil when null
+func values(cols []column, row reflect.Value) []interface{} {
+	vals := make([]interface{}, len(cols))
+	for i, c := range cols {
+		v := row.FieldByIndex(c.index)
+		switch x := v.Interface().(type) {
+		case iex.NullString:
+			if x.Valid {
+				vals[i] = x.String
+			}
+			continue
+		case iex.NullNumber:
+			if x.Valid {
+				vals[i] = x.Float64
+			}
+			continue
+		case iex.NullInt64:
+			if x.Valid {
+				vals[i] = x.Int64
+			}
+			continue
+		}
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.String:
+			vals[i] = v.String()
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			vals[i] = v.Int()
+		case reflect.Float64:
+			vals[i] = v.Float()
+		}
+	}
+	return vals
+}
+
+// parquetSchema is the json schema of cols read by the parquet json writer
+func parquetSchema(cols []column) string {
+	fields := make([]string, len(cols))
+	for i, c := range cols {
+		rep := "REQUIRED"
+		if c.optional {
+			rep = "OPTIONAL"
+		}
+		fields[i] = fmt.Sprintf(`{"Tag":"name=%s, type=%s, repetitiontype=%s"}`, c.name, c.parquet, rep)
+	}
+	return `{"Tag":"name=defcor, repetitiontype=REQUIRED","Fields":[` + strings.Join(fields, ",") + `]}`
+}
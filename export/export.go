@@ -0,0 +1,456 @@
+// Package export writes stored data to CSV, JSON Lines or Parquet files,
+// streaming rows from the database so extracts of any size run in bounded memory.
+package export
+
+import (
+	"context"
+	"defcor/adjust"
+	"defcor/calendar"
+	"defcor/db"
+	"defcor/iex"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Format is an output file format
+type Format int
+
+const (
+	// CSV writes a header row then one line per row; nulls are empty
+	CSV Format = iota
+	// JSONL writes one json object per line
+	JSONL
+	// Parquet writes snappy compressed parquet with nullable columns optional
+	Parquet
+)
+
+// ParseFormat converts csv, jsonl or parquet into a Format
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "csv":
+		return CSV, nil
+	case "jsonl":
+		return JSONL, nil
+	case "parquet":
+		return Parquet, nil
+	}
+	return CSV, fmt.Errorf("unknown export format %q, want csv, jsonl or parquet", s)
+}
+
+func (f Format) ext() string {
+	switch f {
+	case JSONL:
+		return "jsonl"
+	case Parquet:
+		return "parquet"
+	}
+	return "csv"
+}
+
+// Partition splits a dataset into files
+type Partition int
+
+const (
+	// BySymbol writes a file per symbol, e.g. prices/AAPL.csv
+	BySymbol Partition = iota
+	// ByYear writes a file per calendar year of the row date, e.g. prices/2020.csv
+	ByYear
+)
+
+// ParsePartition converts symbol or year into a Partition
+func ParsePartition(s string) (Partition, error) {
+	switch s {
+	case "symbol":
+		return BySymbol, nil
+	case "year":
+		return ByYear, nil
+	}
+	return BySymbol, fmt.Errorf("unknown partition %q, want symbol or year", s)
+}
+
+// Datasets that can be exported; stocks is the security master and is
+// written to a single file
+const (
+	Stocks     = "stocks"
+	Prices     = iex.KindPrices
+	Dividends  = iex.KindDividends
+	Splits     = iex.KindSplits
+	Income     = iex.KindIncome
+	Balance    = iex.KindBalance
+	CashFlow   = iex.KindCashFlow
+	Financials = "financials"
+)
+
+// AllDatasets is every dataset, in export order
+var AllDatasets = []string{Stocks, Prices, Dividends, Splits, Income, Balance, CashFlow}
+
+// Options selects what is exported and how
+type Options struct {
+	Dir       string
+	Format    Format
+	Partition Partition
+	// Datasets to write; financials stands for all three statements
+	Datasets []string
+	// Symbols to export, every active symbol when empty
+	Symbols []string
+	// From and To bound row dates inclusively: bar dates, exdates and
+	// statement report dates; empty is unbounded
+	From, To string
+	// Adjusted is raw, iex (the stored adjustment), split or total
+	Adjusted string
+}
+
+// Result counts what an export wrote
+type Result struct {
+	Files []string
+	Rows  map[string]int
+}
+
+// store is what an export reads from the database
+type store interface {
+	Symbols() ([]string, error)
+	Stocks() ([]iex.Stock, error)
+	EachBar(ctx context.Context, symbols []string, from, to string, fn func(symbol string, p iex.Prices) error) error
+	PriceHistory(symbol, from, to string) (*iex.PriceHistory, error)
+	DividendHistory(symbol string) (*iex.DividendHistory, error)
+	SplitHistory(symbol string) (*iex.SplitHistory, error)
+	IncomeHistory(symbol string) (*iex.IncomeHistory, error)
+	BalanceHistory(symbol string) (*iex.BalanceHistory, error)
+	CashFlowHistory(symbol string) (*iex.CashFlowHistory, error)
+}
+
+var _ store = (*db.Conn)(nil)
+
+// Run exports opts.Datasets, stopping between files and symbols when ctx is done
+func Run(ctx context.Context, conn *db.Conn, opts Options) (*Result, error) {
+	return run(ctx, conn, opts)
+}
+
+func run(ctx context.Context, conn store, opts Options) (*Result, error) {
+	var datasets []string
+	for _, d := range opts.Datasets {
+		if d == Financials {
+			datasets = append(datasets, Income, Balance, CashFlow)
+			continue
+		}
+		if !known(d) {
+			return nil, fmt.Errorf("unknown dataset %q, want one of %s or %s", d, strings.Join(AllDatasets, ", "), Financials)
+		}
+		datasets = append(datasets, d)
+	}
+	for _, d := range []string{opts.From, opts.To} {
+		if d == "" {
+			continue
+		}
+		if _, err := calendar.ParseDate(d); err != nil {
+			return nil, fmt.Errorf("bad date %q: %w", d, err)
+		}
+	}
+	switch opts.Adjusted {
+	case "", "raw", "iex", "split", "total":
+	default:
+		return nil, fmt.Errorf("unknown adjustment %q, want raw, iex, split or total", opts.Adjusted)
+	}
+	symbols := opts.Symbols
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = conn.Symbols(); err != nil {
+			return nil, fmt.Errorf("getting symbols: %w", err)
+		}
+	}
+	symbols = append([]string(nil), symbols...)
+	for i, s := range symbols {
+		symbols[i] = strings.ToUpper(s)
+	}
+	sort.Strings(symbols)
+
+	e := &exporter{ctx: ctx, db: conn, opts: opts, symbols: symbols, result: &Result{Rows: make(map[string]int)}}
+	for _, d := range datasets {
+		if err := ctx.Err(); err != nil {
+			return e.result, err
+		}
+		if err := e.dataset(d); err != nil {
+			return e.result, fmt.Errorf("exporting %s: %w", d, err)
+		}
+	}
+	return e.result, nil
+}
+
+func known(dataset string) bool {
+	for _, d := range AllDatasets {
+		if d == dataset {
+			return true
+		}
+	}
+	return false
+}
+
+type exporter struct {
+	ctx     context.Context
+	db      store
+	opts    Options
+	symbols []string
+	result  *Result
+}
+
+// files routes the rows of a dataset to its partition files. Rows arrive
+// ordered by symbol, so by symbol only one file is open at a time; by year
+// a file stays open per year seen until the dataset ends.
+type files struct {
+	e       *exporter
+	dataset string
+	cols    []column
+	open    map[string]sink
+}
+
+func (e *exporter) files(dataset string, row interface{}) *files {
+	return &files{e: e, dataset: dataset, cols: columnsOf(reflect.TypeOf(row)), open: make(map[string]sink)}
+}
+
+// write adds row, a value of the dataset row type, to the file of symbol or
+// of the year of date
+func (fs *files) write(symbol, date string, row interface{}) error {
+	key := symbol
+	if fs.e.opts.Partition == ByYear {
+		key = date[:4]
+	}
+	s, ok := fs.open[key]
+	if !ok {
+		if fs.e.opts.Partition == BySymbol {
+			if err := fs.close(); err != nil {
+				return err
+			}
+		}
+		path := filepath.Join(fs.e.opts.Dir, fs.dataset, key+"."+fs.e.opts.Format.ext())
+		var err error
+		if s, err = newSink(path, fs.e.opts.Format, fs.cols); err != nil {
+			return err
+		}
+		fs.open[key] = s
+		fs.e.result.Files = append(fs.e.result.Files, path)
+	}
+	fs.e.result.Rows[fs.dataset]++
+	return s.write(values(fs.cols, reflect.ValueOf(row)))
+}
+
+// close finishes every open file
+func (fs *files) close() error {
+	var first error
+	for key, s := range fs.open {
+		if err := s.close(); err != nil && first == nil {
+			first = err
+		}
+		delete(fs.open, key)
+	}
+	return first
+}
+
+// abort discards the files still open after a failure
+func (fs *files) abort() {
+	for key, s := range fs.open {
+		s.abort()
+		delete(fs.open, key)
+	}
+}
+
+func (e *exporter) inRange(date string) bool {
+	return (e.opts.From == "" || date >= e.opts.From) && (e.opts.To == "" || date <= e.opts.To)
+}
+
+func (e *exporter) dataset(d string) error {
+	if d == Stocks {
+		return e.stocks()
+	}
+	var fs *files
+	var err error
+	switch d {
+	case Prices:
+		fs = e.files(d, priceRow{})
+		err = e.prices(fs)
+	case Dividends:
+		fs = e.files(d, dividendRow{})
+		err = e.eachSymbol(fs, e.dividends)
+	case Splits:
+		fs = e.files(d, splitRow{})
+		err = e.eachSymbol(fs, e.splits)
+	case Income:
+		fs = e.files(d, incomeRow{})
+		err = e.eachSymbol(fs, e.income)
+	case Balance:
+		fs = e.files(d, balanceRow{})
+		err = e.eachSymbol(fs, e.balance)
+	case CashFlow:
+		fs = e.files(d, cashFlowRow{})
+		err = e.eachSymbol(fs, e.cashFlow)
+	}
+	if err != nil {
+		fs.abort()
+		return err
+	}
+	return fs.close()
+}
+
+// eachSymbol writes the rows of one symbol at a time
+func (e *exporter) eachSymbol(fs *files, write func(fs *files, symbol string) error) error {
+	for _, symbol := range e.symbols {
+		if err := e.ctx.Err(); err != nil {
+			return err
+		}
+		if err := write(fs, symbol); err != nil {
+			return fmt.Errorf("%s: %w", symbol, err)
+		}
+	}
+	return nil
+}
+
+// stocks writes the security master of the exported symbols to one file
+func (e *exporter) stocks() error {
+	stks, err := e.db.Stocks()
+	if err != nil {
+		return err
+	}
+	want := make(map[string]bool)
+	for _, s := range e.symbols {
+		want[s] = true
+	}
+	sort.Slice(stks, func(i, j int) bool { return stks[i].Symbol < stks[j].Symbol })
+	cols := columnsOf(reflect.TypeOf(stockRow{}))
+	path := filepath.Join(e.opts.Dir, Stocks+"."+e.opts.Format.ext())
+	s, err := newSink(path, e.opts.Format, cols)
+	if err != nil {
+		return err
+	}
+	for _, stk := range stks {
+		if !want[stk.Symbol] {
+			continue
+		}
+		row := stockRow{stk.Symbol, stk.Name, stk.Type, stk.IexID, stk.Region, stk.Curr, stk.Figi, int64(stk.Cik)}
+		if err := s.write(values(cols, reflect.ValueOf(row))); err != nil {
+			s.abort()
+			return err
+		}
+		e.result.Rows[Stocks]++
+	}
+	e.result.Files = append(e.result.Files, path)
+	return s.close()
+}
+
+// prices streams stored bars straight from the database; recomputed
+// adjustments hold one symbol's history at a time
+func (e *exporter) prices(fs *files) error {
+	switch e.opts.Adjusted {
+	case "split", "total":
+		mode, _ := adjust.ParseMode(e.opts.Adjusted)
+		return e.eachSymbol(fs, func(fs *files, symbol string) error {
+			ph, err := e.db.PriceHistory(symbol, "-infinity", "infinity")
+			if err != nil {
+				return err
+			}
+			sh, err := e.db.SplitHistory(symbol)
+			if err != nil {
+				return err
+			}
+			dh, err := e.db.DividendHistory(symbol)
+			if err != nil {
+				return err
+			}
+			for _, p := range adjust.Series(ph, sh, dh, "", mode).Prices {
+				if e.inRange(p.Date) {
+					if err := fs.write(symbol, p.Date, newPriceRow(symbol, p, false)); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+	}
+	from, to := e.opts.From, e.opts.To
+	if from == "" {
+		from = "-infinity"
+	}
+	if to == "" {
+		to = "infinity"
+	}
+	raw := e.opts.Adjusted == "raw"
+	return e.db.EachBar(e.ctx, e.symbols, from, to, func(symbol string, p iex.Prices) error {
+		return fs.write(symbol, p.Date, newPriceRow(symbol, p, raw))
+	})
+}
+
+func (e *exporter) dividends(fs *files, symbol string) error {
+	dh, err := e.db.DividendHistory(symbol)
+	if err != nil {
+		return err
+	}
+	for _, d := range dh.Dividends {
+		if e.inRange(d.ExDate) {
+			if err := fs.write(symbol, d.ExDate, dividendRow{symbol, d}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) splits(fs *files, symbol string) error {
+	sh, err := e.db.SplitHistory(symbol)
+	if err != nil {
+		return err
+	}
+	for _, s := range sh.Splits {
+		if e.inRange(s.ExDate) {
+			if err := fs.write(symbol, s.ExDate, splitRow{symbol, s}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) income(fs *files, symbol string) error {
+	ih, err := e.db.IncomeHistory(symbol)
+	if err != nil {
+		return err
+	}
+	for _, s := range ih.Income {
+		if e.inRange(s.ReportDate) {
+			if err := fs.write(symbol, s.ReportDate, incomeRow{symbol, s}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) balance(fs *files, symbol string) error {
+	bh, err := e.db.BalanceHistory(symbol)
+	if err != nil {
+		return err
+	}
+	for _, s := range bh.Balancesheet {
+		if e.inRange(s.ReportDate) {
+			if err := fs.write(symbol, s.ReportDate, balanceRow{symbol, s}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *exporter) cashFlow(fs *files, symbol string) error {
+	ch, err := e.db.CashFlowHistory(symbol)
+	if err != nil {
+		return err
+	}
+	for _, s := range ch.Cashflow {
+		if e.inRange(s.ReportDate) {
+			if err := fs.write(symbol, s.ReportDate, cashFlowRow{symbol, s}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
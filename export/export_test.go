@@ -0,0 +1,311 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"defcor/iex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// memory serves an export from fixed histories
+type memory struct {
+	prices    map[string][]iex.Prices
+	dividends map[string][]iex.Dividend
+	// failAfter stops EachBar with an error after that many bars when set
+	failAfter int
+}
+
+func (m *memory) Symbols() ([]string, error) {
+	var symbols []string
+	for s := range m.prices {
+		symbols = append(symbols, s)
+	}
+	return symbols, nil
+}
+
+func (m *memory) Stocks() ([]iex.Stock, error) {
+	return []iex.Stock{{Symbol: "MSFT", Name: "Microsoft"}, {Symbol: "AAPL", Name: "Apple Inc", Cik: 320193}}, nil
+}
+
+func (m *memory) EachBar(ctx context.Context, symbols []string, from, to string, fn func(symbol string, p iex.Prices) error) error {
+	var n int
+	for _, symbol := range symbols {
+		for _, p := range m.prices[symbol] {
+			if (from != "-infinity" && p.Date < from) || (to != "infinity" && p.Date > to) {
+				continue
+			}
+			if n++; m.failAfter > 0 && n > m.failAfter {
+				return errors.New("connection reset")
+			}
+			if err := fn(symbol, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memory) PriceHistory(symbol, from, to string) (*iex.PriceHistory, error) {
+	return &iex.PriceHistory{Symbol: symbol, Prices: m.prices[symbol]}, nil
+}
+
+func (m *memory) DividendHistory(symbol string) (*iex.DividendHistory, error) {
+	return &iex.DividendHistory{Symbol: symbol, Dividends: m.dividends[symbol]}, nil
+}
+
+func (m *memory) SplitHistory(symbol string) (*iex.SplitHistory, error) {
+	return &iex.SplitHistory{Symbol: symbol}, nil
+}
+
+func (m *memory) IncomeHistory(symbol string) (*iex.IncomeHistory, error) {
+	return &iex.IncomeHistory{Symbol: symbol}, nil
+}
+
+func (m *memory) BalanceHistory(symbol string) (*iex.BalanceHistory, error) {
+	return &iex.BalanceHistory{Symbol: symbol}, nil
+}
+
+func (m *memory) CashFlowHistory(symbol string) (*iex.CashFlowHistory, error) {
+	return &iex.CashFlowHistory{Symbol: symbol}, nil
+}
+
+func bar(date string, close float64) iex.Prices {
+	return iex.Prices{Date: date, Uopen: close * 2, Uhigh: close * 2, Ulow: close * 2, Uclose: close * 2, Uvolume: 50,
+		Aopen: close, Ahigh: close, Alow: close, Aclose: close, Avolume: 100}
+}
+
+func fixture() *memory {
+	return &memory{
+		prices: map[string][]iex.Prices{
+			"AAPL": {bar("2019-12-31", 73.41), bar("2020-01-02", 75.09)},
+			"MSFT": {bar("2019-12-31", 157.7), bar("2020-01-02", 160.62)},
+		},
+		dividends: map[string][]iex.Dividend{"AAPL": {
+			{ExDate: "2019-11-07", Amount: iex.NullNumber{NullFloat64: sql.NullFloat64{Float64: 0.77, Valid: true}},
+				Flag: iex.NullString{NullString: sql.NullString{String: "Cash", Valid: true}}},
+			{ExDate: "2020-02-07"},
+		}},
+	}
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// tree lists the files under dir relative to it
+func tree(t *testing.T, dir string) []string {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func read(t *testing.T, path string) string {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestColumns(t *testing.T) {
+	tests := []struct {
+		row  interface{}
+		want string
+	}{
+		{stockRow{}, "symbol:UTF8 name:UTF8 type:UTF8 iexId:UTF8 region:UTF8 currency:UTF8 figi:UTF8 cik:INT64"},
+		{priceRow{}, "symbol:UTF8 date:UTF8 open:DOUBLE high:DOUBLE low:DOUBLE close:DOUBLE volume:INT64"},
+		// embedded rows flatten in field order and nullable fields are optional
+		{dividendRow{}, "symbol:UTF8 declaredDate:UTF8? exDate:UTF8 recordDate:UTF8? paymentDate:UTF8? " +
+			"amount:DOUBLE? flag:UTF8? currency:UTF8? frequency:UTF8?"},
+		{splitRow{}, "symbol:UTF8 declaredDate:UTF8? exDate:UTF8 toFactor:DOUBLE fromFactor:DOUBLE"},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range columnsOf(reflect.TypeOf(tt.row)) {
+			s := c.name + ":" + c.parquet
+			if c.optional {
+				s += "?"
+			}
+			got = append(got, s)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("%T: got %s, want %s", tt.row, strings.Join(got, " "), tt.want)
+		}
+	}
+}
+
+func TestCSVAndJSONL(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   map[string]string
+	}{
+		{
+			format: CSV,
+			want: map[string]string{
+				"stocks.csv": "symbol,name,type,iexId,region,currency,figi,cik\nAAPL,Apple Inc,,,,,,320193\n",
+				"prices/AAPL.csv": "symbol,date,open,high,low,close,volume\n" +
+					"AAPL,2019-12-31,146.82,146.82,146.82,146.82,50\nAAPL,2020-01-02,150.18,150.18,150.18,150.18,50\n",
+				"dividends/AAPL.csv": "symbol,declaredDate,exDate,recordDate,paymentDate,amount,flag,currency,frequency\n" +
+					"AAPL,,2019-11-07,,,0.77,Cash,,\nAAPL,,2020-02-07,,,,,,\n",
+			},
+		},
+		{
+			format: JSONL,
+			want: map[string]string{
+				"stocks.jsonl": `{"symbol":"AAPL","name":"Apple Inc","type":"","iexId":"","region":"","currency":"","figi":"","cik":320193}` + "\n",
+				"prices/AAPL.jsonl": `{"symbol":"AAPL","date":"2019-12-31","open":146.82,"high":146.82,"low":146.82,"close":146.82,"volume":50}` + "\n" +
+					`{"symbol":"AAPL","date":"2020-01-02","open":150.18,"high":150.18,"low":150.18,"close":150.18,"volume":50}` + "\n",
+				"dividends/AAPL.jsonl": `{"symbol":"AAPL","declaredDate":null,"exDate":"2019-11-07","recordDate":null,"paymentDate":null,` +
+					`"amount":0.77,"flag":"Cash","currency":null,"frequency":null}` + "\n" +
+					`{"symbol":"AAPL","declaredDate":null,"exDate":"2020-02-07","recordDate":null,"paymentDate":null,` +
+					`"amount":null,"flag":null,"currency":null,"frequency":null}` + "\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		dir := tempDir(t)
+		res, err := run(context.Background(), fixture(), Options{Dir: dir, Format: tt.format,
+			Datasets: []string{Stocks, Prices, Dividends}, Symbols: []string{"aapl"}, Adjusted: "raw"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Files) != len(tt.want) || res.Rows[Prices] != 2 || res.Rows[Dividends] != 2 || res.Rows[Stocks] != 1 {
+			t.Errorf("%s: result %+v", tt.format.ext(), res)
+		}
+		for name, want := range tt.want {
+			if got := read(t, filepath.Join(dir, name)); got != want {
+				t.Errorf("%s: got\n%s\nwant\n%s", name, got, want)
+			}
+		}
+	}
+}
+
+func TestYearPartition(t *testing.T) {
+	dir := tempDir(t)
+	_, err := run(context.Background(), fixture(), Options{Dir: dir, Format: CSV, Partition: ByYear,
+		Datasets: []string{Prices}, From: "2019-01-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := tree(t, dir); !reflect.DeepEqual(files, []string{"prices/2019.csv", "prices/2020.csv"}) {
+		t.Fatalf("got %v", files)
+	}
+	// every symbol's bars of a year share its file, in symbol order
+	want := "symbol,date,open,high,low,close,volume\n" +
+		"AAPL,2020-01-02,75.09,75.09,75.09,75.09,100\nMSFT,2020-01-02,160.62,160.62,160.62,160.62,100\n"
+	if got := read(t, filepath.Join(dir, "prices", "2020.csv")); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSinkRenamesOnClose(t *testing.T) {
+	dir := tempDir(t)
+	path := filepath.Join(dir, "prices", "AAPL.csv")
+	cols := columnsOf(reflect.TypeOf(priceRow{}))
+	for _, finish := range []string{"close", "abort"} {
+		s, err := newSink(path, CSV, cols)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.write(values(cols, reflect.ValueOf(newPriceRow("AAPL", bar("2020-01-02", 1), false)))); err != nil {
+			t.Fatal(err)
+		}
+		if files := tree(t, dir); !reflect.DeepEqual(files, []string{"prices/AAPL.csv.tmp"}) {
+			t.Fatalf("%s: while writing got %v, want only the temporary file", finish, files)
+		}
+		if finish == "abort" {
+			s.abort()
+			if files := tree(t, dir); files != nil {
+				t.Errorf("after abort got %v, want nothing", files)
+			}
+			continue
+		}
+		if err := s.close(); err != nil {
+			t.Fatal(err)
+		}
+		if files := tree(t, dir); !reflect.DeepEqual(files, []string{"prices/AAPL.csv"}) {
+			t.Fatalf("after close got %v", files)
+		}
+		os.Remove(path)
+	}
+}
+
+func TestFailedExportLeavesNoPartialFiles(t *testing.T) {
+	dir := tempDir(t)
+	// a previous export of the file survives a failed one
+	old := filepath.Join(dir, "prices", "MSFT.csv")
+	if err := os.MkdirAll(filepath.Dir(old), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(old, []byte("previous\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := fixture()
+	m.failAfter = 3
+	if _, err := run(context.Background(), m, Options{Dir: dir, Format: CSV, Datasets: []string{Prices}}); err == nil {
+		t.Fatal("export succeeded past a failing read")
+	}
+	// AAPL was complete before MSFT failed, so it was renamed into place
+	if files := tree(t, dir); !reflect.DeepEqual(files, []string{"prices/AAPL.csv", "prices/MSFT.csv"}) {
+		t.Errorf("got %v", files)
+	}
+	if got := read(t, old); got != "previous\n" {
+		t.Errorf("previous export overwritten with %q", got)
+	}
+}
+
+func TestParquet(t *testing.T) {
+	cols := columnsOf(reflect.TypeOf(splitRow{}))
+	want := `{"Tag":"name=defcor, repetitiontype=REQUIRED","Fields":[` +
+		`{"Tag":"name=symbol, type=UTF8, repetitiontype=REQUIRED"},` +
+		`{"Tag":"name=declaredDate, type=UTF8, repetitiontype=OPTIONAL"},` +
+		`{"Tag":"name=exDate, type=UTF8, repetitiontype=REQUIRED"},` +
+		`{"Tag":"name=toFactor, type=DOUBLE, repetitiontype=REQUIRED"},` +
+		`{"Tag":"name=fromFactor, type=DOUBLE, repetitiontype=REQUIRED"}]}`
+	if got := parquetSchema(cols); got != want {
+		t.Errorf("schema\n%s\nwant\n%s", got, want)
+	}
+
+	dir := tempDir(t)
+	if _, err := run(context.Background(), fixture(), Options{Dir: dir, Format: Parquet, Datasets: []string{Prices}}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(filepath.Join(dir, "prices", "MSFT.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	pr, err := reader.NewParquetReader(&parquetFile{f}, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.ReadStop()
+	if n := pr.GetNumRows(); n != 2 {
+		t.Errorf("%d rows, want 2", n)
+	}
+}
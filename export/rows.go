@@ -0,0 +1,59 @@
+package export
+
+import "defcor/iex"
+
+// row types of the exported datasets; columns take their json names
+
+type stockRow struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	IexID    string `json:"iexId"`
+	Region   string `json:"region"`
+	Currency string `json:"currency"`
+	Figi     string `json:"figi"`
+	Cik      int64  `json:"cik"`
+}
+
+type priceRow struct {
+	Symbol string  `json:"symbol"`
+	Date   string  `json:"date"`
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume int64   `json:"volume"`
+}
+
+// newPriceRow takes the unadjusted columns of p when raw, else the adjusted ones
+func newPriceRow(symbol string, p iex.Prices, raw bool) priceRow {
+	if raw {
+		return priceRow{symbol, p.Date, p.Uopen, p.Uhigh, p.Ulow, p.Uclose, int64(p.Uvolume)}
+	}
+	return priceRow{symbol, p.Date, p.Aopen, p.Ahigh, p.Alow, p.Aclose, int64(p.Avolume)}
+}
+
+type dividendRow struct {
+	Symbol string `json:"symbol"`
+	iex.Dividend
+}
+
+type splitRow struct {
+	Symbol string `json:"symbol"`
+	iex.Split
+}
+
+type incomeRow struct {
+	Symbol string `json:"symbol"`
+	iex.IncomeStatement
+}
+
+type balanceRow struct {
+	Symbol string `json:"symbol"`
+	iex.BalanceSheet
+}
+
+type cashFlowRow struct {
+	Symbol string `json:"symbol"`
+	iex.CashFlow
+}
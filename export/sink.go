@@ -0,0 +1,196 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// sink writes the rows of one file. The file is built under a temporary name
+// and renamed into place by close, so an interrupted export leaves no
+// truncated file behind; abort discards it.
+type sink interface {
+	write(vals []interface{}) error
+	close() error
+	abort()
+}
+
+// rowGroupSize bounds the rows a parquet sink buffers before flushing
+const rowGroupSize = 16 << 20
+
+func newSink(path string, format Format, cols []column) (sink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, err
+	}
+	base := fileSink{path: path, f: f}
+	switch format {
+	case Parquet:
+		pw, err := writer.NewJSONWriter(parquetSchema(cols), &parquetFile{f}, 1)
+		if err != nil {
+			base.abort()
+			return nil, err
+		}
+		pw.RowGroupSize = rowGroupSize
+		return &parquetSink{fileSink: base, cols: cols, pw: pw}, nil
+	case JSONL:
+		base.buf = bufio.NewWriter(f)
+		return &jsonlSink{fileSink: base, cols: cols}, nil
+	}
+	base.buf = bufio.NewWriter(f)
+	s := &csvSink{fileSink: base, w: csv.NewWriter(base.buf)}
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	if err := s.w.Write(header); err != nil {
+		base.abort()
+		return nil, err
+	}
+	return s, nil
+}
+
+type fileSink struct {
+	path string
+	f    *os.File
+	buf  *bufio.Writer
+}
+
+func (s *fileSink) close() error {
+	if s.buf != nil {
+		if err := s.buf.Flush(); err != nil {
+			s.abort()
+			return err
+		}
+	}
+	if err := s.f.Close(); err != nil {
+		os.Remove(s.f.Name())
+		return err
+	}
+	return os.Rename(s.f.Name(), s.path)
+}
+
+func (s *fileSink) abort() {
+	s.f.Close()
+	os.Remove(s.f.Name())
+}
+
+type csvSink struct {
+	fileSink
+	w      *csv.Writer
+	record []string
+}
+
+func (s *csvSink) write(vals []interface{}) error {
+	if s.record == nil {
+		s.record = make([]string, len(vals))
+	}
+	for i, v := range vals {
+		switch x := v.(type) {
+		case nil:
+			s.record[i] = ""
+		case string:
+			s.record[i] = x
+		case int64:
+			s.record[i] = strconv.FormatInt(x, 10)
+		case float64:
+			s.record[i] = strconv.FormatFloat(x, 'f', -1, 64)
+		}
+	}
+	return s.w.Write(s.record)
+}
+
+func (s *csvSink) close() error {
+	s.w.Flush()
+	if err := s.w.Error(); err != nil {
+		s.abort()
+		return err
+	}
+	return s.fileSink.close()
+}
+
+// jsonObject renders vals as a json object keyed by the column names
+func jsonObject(cols []column, vals []interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, v := range vals {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(cols[i].name)
+		b.Write(name)
+		b.WriteByte(':')
+		value, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+type jsonlSink struct {
+	fileSink
+	cols []column
+}
+
+func (s *jsonlSink) write(vals []interface{}) error {
+	line, err := jsonObject(s.cols, vals)
+	if err != nil {
+		return err
+	}
+	s.buf.Write(line)
+	return s.buf.WriteByte('\n')
+}
+
+type parquetSink struct {
+	fileSink
+	cols []column
+	pw   *writer.JSONWriter
+}
+
+func (s *parquetSink) write(vals []interface{}) error {
+	row, err := jsonObject(s.cols, vals)
+	if err != nil {
+		return err
+	}
+	return s.pw.Write(string(row))
+}
+
+func (s *parquetSink) close() error {
+	if err := s.pw.WriteStop(); err != nil {
+		s.abort()
+		return err
+	}
+	return s.fileSink.close()
+}
+
+// parquetFile adapts an os.File to the file the parquet writer expects
+type parquetFile struct {
+	*os.File
+}
+
+// Open reopens the file itself when name is empty, as parquet readers expect
+func (p *parquetFile) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		name = p.Name()
+	}
+	f, err := os.Open(name)
+	return &parquetFile{f}, err
+}
+
+func (p *parquetFile) Create(name string) (source.ParquetFile, error) {
+	f, err := os.Create(name)
+	return &parquetFile{f}, err
+}
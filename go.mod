@@ -4,5 +4,9 @@ go 1.14
 
 require (
 	github.com/jackc/pgx/v4 v4.8.1
+	github.com/prometheus/client_golang v1.7.1
+	github.com/xitongsys/parquet-go v1.5.1
 	golang.org/x/time v0.0.0-20200630173020-3af7569d3a1e
+	google.golang.org/grpc v1.43.0
+	google.golang.org/protobuf v1.27.1
 )
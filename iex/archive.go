@@ -0,0 +1,109 @@
+package iex
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Archiver persists raw api responses keyed by endpoint (token stripped)
+type Archiver interface {
+	Archive(endpoint string, fetched time.Time, payload []byte) error
+}
+
+// Payload kinds recognised by ParseEndpoint
+const (
+	KindStocks    = "stocks"
+	KindPrices    = "prices"
+	KindDividends = "dividends"
+	KindSplits    = "splits"
+	KindIncome    = "income"
+	KindBalance   = "balance-sheet"
+	KindCashFlow  = "cash-flow"
+)
+
+// ParseEndpoint recovers the payload kind and symbol from an archived endpoint
+func ParseEndpoint(endpoint string) (kind, symbol string, err error) {
+	urlpath := strings.SplitN(endpoint, "?", 2)[0]
+	parts := strings.Split(strings.Trim(urlpath, "/"), "/")
+	if len(parts) == 2 && parts[0] == "ref-data" && parts[1] == "symbols" {
+		return KindStocks, "", nil
+	}
+	if len(parts) < 3 || parts[0] != "stock" {
+		return "", "", fmt.Errorf("unrecognised endpoint %q", endpoint)
+	}
+	symbol = parts[1]
+	switch parts[2] {
+	case "chart":
+		return KindPrices, symbol, nil
+	case "dividends":
+		return KindDividends, symbol, nil
+	case "splits":
+		return KindSplits, symbol, nil
+	case "income":
+		return KindIncome, symbol, nil
+	case "balance-sheet":
+		return KindBalance, symbol, nil
+	case "cash-flow":
+		return KindCashFlow, symbol, nil
+	}
+	return "", "", fmt.Errorf("unrecognised endpoint %q", endpoint)
+}
+
+// DecodePrices re-decodes an archived chart payload
+func DecodePrices(symbol string, payload []byte) (*PriceHistory, error) {
+	ph := PriceHistory{Symbol: symbol}
+	if err := json.Unmarshal(payload, &ph.Prices); err != nil {
+		return nil, err
+	}
+	return &ph, nil
+}
+
+// DecodeDividends re-decodes an archived dividends payload
+func DecodeDividends(symbol string, payload []byte) (*DividendHistory, error) {
+	dh := DividendHistory{Symbol: symbol}
+	if err := json.Unmarshal(payload, &dh.Dividends); err != nil {
+		return nil, err
+	}
+	return &dh, nil
+}
+
+// DecodeSplits re-decodes an archived splits payload
+func DecodeSplits(symbol string, payload []byte) (*SplitHistory, error) {
+	sh := SplitHistory{Symbol: symbol}
+	if err := json.Unmarshal(payload, &sh.Splits); err != nil {
+		return nil, err
+	}
+	return &sh, nil
+}
+
+// DecodeIncome re-decodes an archived income statement payload
+func DecodeIncome(symbol string, payload []byte) (*IncomeHistory, error) {
+	var ih IncomeHistory
+	if err := json.Unmarshal(payload, &ih); err != nil {
+		return nil, err
+	}
+	ih.Symbol = symbol
+	return &ih, nil
+}
+
+// DecodeBalance re-decodes an archived balance sheet payload
+func DecodeBalance(symbol string, payload []byte) (*BalanceHistory, error) {
+	var bh BalanceHistory
+	if err := json.Unmarshal(payload, &bh); err != nil {
+		return nil, err
+	}
+	bh.Symbol = symbol
+	return &bh, nil
+}
+
+// DecodeCashFlow re-decodes an archived cash flow payload
+func DecodeCashFlow(symbol string, payload []byte) (*CashFlowHistory, error) {
+	var ch CashFlowHistory
+	if err := json.Unmarshal(payload, &ch); err != nil {
+		return nil, err
+	}
+	ch.Symbol = symbol
+	return &ch, nil
+}
@@ -0,0 +1,196 @@
+package iex
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"defcor/calendar"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheMode controls how a CachingTransport treats its store
+type CacheMode int
+
+const (
+	// CacheRecord serves fresh entries from disk and records every miss
+	CacheRecord CacheMode = iota
+	// CacheReplay only serves from disk, ignoring ttls; a miss is an error
+	CacheReplay
+	// CacheRefresh always hits the network and overwrites the stored entry
+	CacheRefresh
+)
+
+// Forever marks a cache entry that never expires
+const Forever time.Duration = -1
+
+// ErrCacheMiss is returned in replay mode when a response was never recorded
+var ErrCacheMiss = errors.New("iex: response not in cache")
+
+// ParseCacheMode converts record, replay or refresh into a CacheMode
+func ParseCacheMode(s string) (CacheMode, error) {
+	switch strings.ToLower(s) {
+	case "", "record":
+		return CacheRecord, nil
+	case "replay":
+		return CacheReplay, nil
+	case "refresh":
+		return CacheRefresh, nil
+	}
+	return CacheRecord, fmt.Errorf("unknown cache mode %q", s)
+}
+
+// TTLRule assigns a time to live to every endpoint containing Match
+type TTLRule struct {
+	Match string
+	TTL   time.Duration
+}
+
+// DefaultTTLs keeps reference data for a day and single past sessions
+// forever. Relative chart ranges (5d, 1m, ...) end at the latest session, so
+// they only live until the next one could have been published.
+var DefaultTTLs = []TTLRule{
+	{Match: "/ref-data/", TTL: 24 * time.Hour},
+	{Match: "/chart/date/", TTL: Forever},
+	{Match: "/chart/", TTL: time.Hour},
+}
+
+// CachingTransport stores raw iex responses on disk keyed by endpoint and params
+type CachingTransport struct {
+	Dir        string
+	Mode       CacheMode
+	TTLs       []TTLRule
+	DefaultTTL time.Duration
+	Next       http.RoundTripper
+}
+
+// NewCachingTransport creates a disk cache in dir using DefaultTTLs
+func NewCachingTransport(dir string, mode CacheMode) *CachingTransport {
+	return &CachingTransport{
+		Dir:        dir,
+		Mode:       mode,
+		TTLs:       DefaultTTLs,
+		DefaultTTL: 24 * time.Hour,
+		Next:       http.DefaultTransport,
+	}
+}
+
+// Lookup returns the stored response to req when the mode lets it be served
+// from disk, or nil when req has to go to the network. In replay mode a miss
+// fails with ErrCacheMiss.
+func (ct *CachingTransport) Lookup(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || ct.Mode == CacheRefresh {
+		return nil, nil
+	}
+	resp, err := ct.load(ct.filename(req), req)
+	if err == nil {
+		// served from disk, nothing was charged
+		resp.Header.Del(creditsHeader)
+		return resp, nil
+	}
+	if ct.Mode == CacheReplay {
+		return nil, fmt.Errorf("%s: %w", cacheKey(req), ErrCacheMiss)
+	}
+	return nil, nil
+}
+
+// RoundTrip satisfies http.RoundTripper
+func (ct *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return ct.Next.RoundTrip(req)
+	}
+	if resp, err := ct.Lookup(req); resp != nil || err != nil {
+		return resp, err
+	}
+	file := ct.filename(req)
+	resp, err := ct.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	return ct.store(file, resp)
+}
+
+// ttl finds the time to live for the endpoint of req. Forever only applies
+// to endpoints ending in a date before today; anything else may still change.
+func (ct *CachingTransport) ttl(req *http.Request) time.Duration {
+	for _, r := range ct.TTLs {
+		if !strings.Contains(req.URL.Path, r.Match) {
+			continue
+		}
+		if r.TTL == Forever && !pastDate(req.URL.Path, time.Now()) {
+			continue
+		}
+		return r.TTL
+	}
+	return ct.DefaultTTL
+}
+
+// pastDate reports whether the last element of urlpath is a yyyymmdd date
+// before the exchange's today at now
+func pastDate(urlpath string, now time.Time) bool {
+	date := path.Base(urlpath)
+	if _, err := time.Parse("20060102", date); err != nil {
+		return false
+	}
+	return date < now.In(calendar.NYSE.Location).Format("20060102")
+}
+
+func (ct *CachingTransport) load(file string, req *http.Request) (*http.Response, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	ttl := ct.ttl(req)
+	if ct.Mode == CacheRecord && ttl != Forever && time.Since(info.ModTime()) > ttl {
+		return nil, fmt.Errorf("%s: expired", file)
+	}
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), req)
+}
+
+func (ct *CachingTransport) store(file string, resp *http.Response) (*http.Response, error) {
+	raw, err := httputil.DumpResponse(resp, true)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, err
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, raw, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return nil, err
+	}
+	return http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), resp.Request)
+}
+
+func (ct *CachingTransport) filename(req *http.Request) string {
+	sum := sha256.Sum256([]byte(cacheKey(req)))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(ct.Dir, key[:2], key+".http")
+}
+
+// cacheKey identifies a request by endpoint and params with the token stripped
+func cacheKey(req *http.Request) string {
+	qparams := req.URL.Query()
+	qparams.Del("token")
+	return req.URL.Path + "?" + qparams.Encode()
+}
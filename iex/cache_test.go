@@ -0,0 +1,187 @@
+package iex
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// vendor stands in for iex, answering every request with body and counting calls
+type vendor struct {
+	body  string
+	calls int
+}
+
+func (v *vendor) RoundTrip(req *http.Request) (*http.Response, error) {
+	v.calls++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{creditsHeader: []string{"10"}},
+		Body:       ioutil.NopCloser(strings.NewReader(v.body)),
+		Request:    req,
+	}, nil
+}
+
+func fetch(t *testing.T, ct *CachingTransport, url string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := ct.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body), nil
+}
+
+func TestCachingTransportModes(t *testing.T) {
+	const chart = "https://cloud.iexapis.com/stable/stock/AAPL/chart/5d?token=secret"
+	dir, err := ioutil.TempDir("", "iexcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	v := &vendor{body: "[1]"}
+	ct := &CachingTransport{Dir: dir, Mode: CacheRecord, TTLs: DefaultTTLs, DefaultTTL: time.Hour, Next: v}
+
+	// record fetches a miss once and serves it from disk after
+	for i := 0; i < 2; i++ {
+		if body, err := fetch(t, ct, chart); err != nil || body != "[1]" {
+			t.Fatalf("record: got %q, %v", body, err)
+		}
+	}
+	if v.calls != 1 {
+		t.Fatalf("record: %d vendor calls, want 1", v.calls)
+	}
+	// the token is not part of the key
+	if _, err := fetch(t, ct, strings.Replace(chart, "secret", "other", 1)); err != nil || v.calls != 1 {
+		t.Fatalf("record with another token: %d vendor calls, %v", v.calls, err)
+	}
+
+	// refresh always fetches and overwrites
+	v.body = "[2]"
+	ct.Mode = CacheRefresh
+	if body, err := fetch(t, ct, chart); err != nil || body != "[2]" || v.calls != 2 {
+		t.Fatalf("refresh: got %q, %v after %d vendor calls", body, err, v.calls)
+	}
+
+	// replay serves what is stored, even expired, and never fetches
+	req, _ := http.NewRequest(http.MethodGet, chart, nil)
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(ct.filename(req), old, old); err != nil {
+		t.Fatal(err)
+	}
+	ct.Mode = CacheReplay
+	if body, err := fetch(t, ct, chart); err != nil || body != "[2]" {
+		t.Fatalf("replay: got %q, %v", body, err)
+	}
+	if _, err := fetch(t, ct, "https://cloud.iexapis.com/stable/stock/IBM/chart/5d"); !errors.Is(err, ErrCacheMiss) {
+		t.Fatalf("replay miss: got %v, want %v", err, ErrCacheMiss)
+	}
+	if v.calls != 2 {
+		t.Fatalf("replay: %d vendor calls, want 2", v.calls)
+	}
+
+	// record refetches an entry past its ttl
+	v.body = "[3]"
+	ct.Mode = CacheRecord
+	if body, err := fetch(t, ct, chart); err != nil || body != "[3]" || v.calls != 3 {
+		t.Fatalf("expired: got %q, %v after %d vendor calls", body, err, v.calls)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	ct := NewCachingTransport("", CacheRecord)
+	tests := []struct {
+		path string
+		want time.Duration
+	}{
+		{"/stable/ref-data/symbols", 24 * time.Hour},
+		{"/stable/stock/AAPL/chart/date/20200102", Forever},
+		{"/stable/stock/AAPL/chart/date/29990102", time.Hour},
+		{"/stable/stock/AAPL/chart/5d", time.Hour},
+		{"/stable/stock/AAPL/dividends/5y", 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, "https://cloud.iexapis.com"+tt.path, nil)
+		if got := ct.ttl(req); got != tt.want {
+			t.Errorf("ttl(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPastDateUsesExchangeTime(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		date string
+		want bool
+	}{
+		// late evening in new york is already tomorrow in utc
+		{time.Date(2021, 1, 9, 4, 30, 0, 0, time.UTC), "20210108", false},
+		{time.Date(2021, 1, 9, 5, 30, 0, 0, time.UTC), "20210108", true},
+		{time.Date(2021, 1, 9, 5, 30, 0, 0, time.UTC), "20210109", false},
+		{time.Date(2021, 1, 9, 5, 30, 0, 0, time.UTC), "5d", false},
+	}
+	for _, tt := range tests {
+		if got := pastDate("/stable/stock/AAPL/chart/date/"+tt.date, tt.now); got != tt.want {
+			t.Errorf("pastDate(%s) at %s = %v, want %v", tt.date, tt.now, got, tt.want)
+		}
+	}
+}
+
+type countingLimiter struct{ waits int }
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	return nil
+}
+
+type countingArchiver struct{ archived int }
+
+func (a *countingArchiver) Archive(endpoint string, fetched time.Time, payload []byte) error {
+	a.archived++
+	return nil
+}
+
+func TestCacheHitsSkipLimiterAndArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iexcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	v := &vendor{body: `[{"exDate":"2020-01-02"}]`}
+	ct := NewCachingTransport(dir, CacheRecord)
+	ct.Next = v
+	a := NewAPIConnection("cloud.iexapis.com", "secret", "5d", time.Millisecond)
+	a.SetTransport(ct)
+	limiter, archiver := &countingLimiter{}, &countingArchiver{}
+	a.SetLimiter(limiter)
+	a.SetArchiver(archiver)
+	for i := 0; i < 3; i++ {
+		var got []Dividend
+		if err := a.get(context.Background(), "dividends", "stock/AAPL/dividends/5y", nil, &got); err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].ExDate != "2020-01-02" {
+			t.Fatalf("got %+v", got)
+		}
+	}
+	if v.calls != 1 || limiter.waits != 1 || archiver.archived != 1 {
+		t.Errorf("%d vendor calls, %d limiter waits, %d archived; want 1 of each",
+			v.calls, limiter.waits, archiver.archived)
+	}
+}
@@ -2,20 +2,33 @@ package iex
 
 import (
 	"context"
+	"defcor/logging"
+	"defcor/metrics"
 	"encoding/json"
-	"log"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path"
 	"regexp"
+	"strconv"
 	"time"
 
 	"golang.org/x/time/rate"
 )
 
+// Limiter paces requests; *rate.Limiter throttles one process, a shared
+// limiter every process using the same bucket
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
 // APIConnection generalizes a http client
 type APIConnection struct {
-	rateLimiter *rate.Limiter
+	rateLimiter Limiter
+	client      *http.Client
+	archiver    Archiver
+	log         *logging.Logger
 	baseURL     url.URL
 	apiKey      string
 	lookback    string
@@ -25,6 +38,8 @@ type APIConnection struct {
 func NewAPIConnection(host, key, lookback string, duration time.Duration) *APIConnection {
 	return &APIConnection{
 		rateLimiter: rate.NewLimiter(Per(1, duration), 1),
+		client:      &http.Client{},
+		log:         logging.Default(),
 		baseURL: url.URL{
 			Scheme: "https",
 			Host:   host,
@@ -35,33 +50,145 @@ func NewAPIConnection(host, key, lookback string, duration time.Duration) *APICo
 	}
 }
 
+// SetTransport swaps the transport used for every request, e.g. a CachingTransport
+func (a *APIConnection) SetTransport(rt http.RoundTripper) {
+	a.client.Transport = rt
+}
+
+// SetLimiter replaces the in-process limiter, e.g. with a shared one
+func (a *APIConnection) SetLimiter(l Limiter) {
+	a.rateLimiter = l
+}
+
+// SetLogger replaces the logger requests are recorded with
+func (a *APIConnection) SetLogger(l *logging.Logger) {
+	a.log = l
+}
+
+// SetArchiver records every successful raw response with archiver
+func (a *APIConnection) SetArchiver(archiver Archiver) {
+	a.archiver = archiver
+}
+
 // Per tracks events per unit of time
 func Per(eventCount int, duration time.Duration) rate.Limit {
 	return rate.Every(duration / time.Duration(eventCount))
 }
 
-// AllStocks returns all active stocks and their accompanied data
-func (a *APIConnection) AllStocks(ctx context.Context) ([]Stock, error) {
-	if err := a.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
+// chartRanges pairs each iex chart range with the trading days it safely covers
+var chartRanges = []struct {
+	rng  string
+	days int
+}{
+	{"5d", 5},
+	{"1m", 19},
+	{"3m", 60},
+	{"6m", 123},
+	{"1y", 250},
+	{"2y", 500},
+	{"5y", 1255},
+}
+
+// RangeDays returns the trading days a chart range is assumed to return
+func RangeDays(rng string) int {
+	for _, r := range chartRanges {
+		if r.rng == rng {
+			return r.days
+		}
+	}
+	// max goes back roughly 15 years
+	return 15 * 252
+}
+
+// RangeFor picks the smallest chart range covering the last n trading days
+func RangeFor(n int) string {
+	for _, r := range chartRanges {
+		if n <= r.days {
+			return r.rng
+		}
+	}
+	return "max"
+}
+
+// creditsHeader reports the credits a response was charged
+const creditsHeader = "iexcloud-messages-used"
+
+// get performs a rate limited GET against urlpath and decodes the json body
+// into v. Responses a CachingTransport serves from disk skip the limiter and
+// the archiver, since they were neither charged nor newly fetched.
+func (a *APIConnection) get(ctx context.Context, name, urlpath string, qparams url.Values, v interface{}) error {
+	if qparams == nil {
+		qparams = make(url.Values)
 	}
-	qparams := make(url.Values)
 	qparams.Set("token", a.apiKey)
-	endpoint := a.baseURL.ResolveReference(&url.URL{Path: "ref-data/symbols"})
-	request, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
+	endpoint := a.baseURL.ResolveReference(&url.URL{Path: urlpath})
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	request.URL.RawQuery = qparams.Encode()
 
-	resp, err := http.DefaultClient.Do(request)
+	_, symbol, _ := ParseEndpoint(urlpath)
+	var cached *http.Response
+	if ct, ok := a.client.Transport.(*CachingTransport); ok {
+		if cached, err = ct.Lookup(request); err != nil {
+			return err
+		}
+	}
+	if cached != nil {
+		defer cached.Body.Close()
+		a.log.Debug("cached", "kind", name, "symbol", symbol, "endpoint", urlpath)
+		body, err := ioutil.ReadAll(cached.Body)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(body, v)
+	}
+	waited := time.Now()
+	if err := a.rateLimiter.Wait(ctx); err != nil {
+		return err
+	}
+	metrics.LimiterWait.Observe(time.Since(waited).Seconds())
+	start := time.Now()
+	resp, err := a.client.Do(request)
+	metrics.RequestDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
 	if err != nil {
-		return nil, err
+		metrics.Requests.WithLabelValues(name, "error").Inc()
+		a.log.Error("request failed", "kind", name, "symbol", symbol, "endpoint", urlpath,
+			"duration", time.Since(start), "err", err)
+		return err
 	}
 	defer resp.Body.Close()
+	metrics.Requests.WithLabelValues(name, strconv.Itoa(resp.StatusCode)).Inc()
+	if used, err := strconv.ParseFloat(resp.Header.Get(creditsHeader), 64); err == nil {
+		metrics.Credits.WithLabelValues(name).Add(used)
+	}
+	a.log.Info("request", "kind", name, "symbol", symbol, "endpoint", urlpath,
+		"status", resp.StatusCode, "duration", time.Since(start))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s", name, urlpath, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if a.archiver != nil {
+		qparams.Del("token")
+		endpoint := urlpath
+		if len(qparams) > 0 {
+			endpoint += "?" + qparams.Encode()
+		}
+		if err := a.archiver.Archive(endpoint, time.Now(), body); err != nil {
+			return fmt.Errorf("archiving %s: %w", endpoint, err)
+		}
+	}
+	return json.Unmarshal(body, v)
+}
 
+// AllStocks returns all active stocks and their accompanied data
+func (a *APIConnection) AllStocks(ctx context.Context) ([]Stock, error) {
 	var stks []JSONStock
-	if err := json.NewDecoder(resp.Body).Decode(&stks); err != nil {
+	if err := a.get(ctx, "symbols", "ref-data/symbols", nil, &stks); err != nil {
 		return nil, err
 	}
 
@@ -77,60 +204,51 @@ func (a *APIConnection) AllStocks(ctx context.Context) ([]Stock, error) {
 	return fstks, nil
 }
 
+// Stocks returns the filtered security master, see AllStocks
+func (a *APIConnection) Stocks(ctx context.Context) ([]Stock, error) {
+	return a.AllStocks(ctx)
+}
+
 // Prices returns the historical prices for a stock
 func (a *APIConnection) Prices(ctx context.Context, symbol string) (*PriceHistory, error) {
-	if err := a.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
-	qparams := make(url.Values)
-	qparams.Set("token", a.apiKey)
-	urlpath := path.Join("stock", symbol, "chart", a.lookback)
-	endpoint := a.baseURL.ResolveReference(&url.URL{Path: urlpath})
-	request, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	request.URL.RawQuery = qparams.Encode()
-	resp, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	log.Printf("price status: %s\n", resp.Status)
+	return a.Chart(ctx, symbol, a.lookback)
+}
 
+// Chart returns the historical prices for a stock over an iex range such as 5d or 1y
+func (a *APIConnection) Chart(ctx context.Context, symbol, rng string) (*PriceHistory, error) {
 	var ph PriceHistory
 	ph.Symbol = symbol
-	if err := json.NewDecoder(resp.Body).Decode(&ph.Prices); err != nil {
+	urlpath := path.Join("stock", symbol, "chart", rng)
+	if err := a.get(ctx, "price", urlpath, nil, &ph.Prices); err != nil {
 		return nil, err
 	}
 	return &ph, nil
 }
 
-// Dividends returns the historical dividend information for a stock
-func (a *APIConnection) Dividends(ctx context.Context, symbol string) (*DividendHistory, error) {
-	if err := a.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
+// ChartDate returns the daily bar of a stock for a single yyyymmdd date
+func (a *APIConnection) ChartDate(ctx context.Context, symbol, date string) (*PriceHistory, error) {
+	var ph PriceHistory
+	ph.Symbol = symbol
 	qparams := make(url.Values)
-	qparams.Set("token", a.apiKey)
-	urlpath := path.Join("stock", symbol, "dividends", a.lookback)
-	endpoint := a.baseURL.ResolveReference(&url.URL{Path: urlpath})
-	request, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
-	if err != nil {
+	qparams.Set("chartByDay", "true")
+	urlpath := path.Join("stock", symbol, "chart", "date", date)
+	if err := a.get(ctx, "price", urlpath, qparams, &ph.Prices); err != nil {
 		return nil, err
 	}
-	request.URL.RawQuery = qparams.Encode()
+	return &ph, nil
+}
 
-	resp, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	log.Printf("dividend status: %s\n", resp.Status)
+// Dividends returns the historical dividend information for a stock
+func (a *APIConnection) Dividends(ctx context.Context, symbol string) (*DividendHistory, error) {
+	return a.DividendRange(ctx, symbol, a.lookback)
+}
 
+// DividendRange returns the dividends of a stock over an iex range, e.g. 5y
+func (a *APIConnection) DividendRange(ctx context.Context, symbol, rng string) (*DividendHistory, error) {
 	var dh DividendHistory
 	dh.Symbol = symbol
-	if err := json.NewDecoder(resp.Body).Decode(&dh.Dividends); err != nil {
+	urlpath := path.Join("stock", symbol, "dividends", rng)
+	if err := a.get(ctx, "dividend", urlpath, nil, &dh.Dividends); err != nil {
 		return nil, err
 	}
 	return &dh, nil
@@ -138,119 +256,53 @@ func (a *APIConnection) Dividends(ctx context.Context, symbol string) (*Dividend
 
 // Splits returns the historical split information for a stock
 func (a *APIConnection) Splits(ctx context.Context, symbol string) (*SplitHistory, error) {
-	if err := a.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
-	qparams := make(url.Values)
-	qparams.Set("token", a.apiKey)
-	urlpath := path.Join("stock", symbol, "splits", a.lookback)
-	endpoint := a.baseURL.ResolveReference(&url.URL{Path: urlpath})
-	request, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	request.URL.RawQuery = qparams.Encode()
-
-	resp, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	return a.SplitRange(ctx, symbol, a.lookback)
+}
 
-	log.Printf("split status: %s\n", resp.Status)
+// SplitRange returns the splits of a stock over an iex range, e.g. 5y
+func (a *APIConnection) SplitRange(ctx context.Context, symbol, rng string) (*SplitHistory, error) {
 	var sh SplitHistory
 	sh.Symbol = symbol
-	if err := json.NewDecoder(resp.Body).Decode(&sh.Splits); err != nil {
+	urlpath := path.Join("stock", symbol, "splits", rng)
+	if err := a.get(ctx, "split", urlpath, nil, &sh.Splits); err != nil {
 		return nil, err
 	}
 	return &sh, nil
 }
 
-// IncomeStatements returns the Income Statement history for a stock
-func (a *APIConnection) IncomeStatements(ctx context.Context, symbol string) (*IncomeHistory, error) {
-	if err := a.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
+// statementParams builds the query for the last quarterly financial statements
+func statementParams(last int) url.Values {
 	qparams := make(url.Values)
-	qparams.Set("token", a.apiKey)
 	qparams.Set("period", "quarter")
-	qparams.Set("last", a.lookback)
-	urlpath := path.Join("stock", symbol, "income")
-	endpoint := a.baseURL.ResolveReference(&url.URL{Path: urlpath})
-	request, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	request.URL.RawQuery = qparams.Encode()
+	qparams.Set("last", strconv.Itoa(last))
+	return qparams
+}
 
-	resp, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	log.Printf("income status: %s\n", resp.Status)
+// IncomeStatements returns the last quarterly Income Statements for a stock
+func (a *APIConnection) IncomeStatements(ctx context.Context, symbol string, last int) (*IncomeHistory, error) {
 	var income IncomeHistory
-	if err := json.NewDecoder(resp.Body).Decode(&income); err != nil {
+	urlpath := path.Join("stock", symbol, "income")
+	if err := a.get(ctx, "income", urlpath, statementParams(last), &income); err != nil {
 		return nil, err
 	}
 	return &income, nil
 }
 
-// BalanceSheets returns the Balance Sheet history for a stock
-func (a *APIConnection) BalanceSheets(ctx context.Context, symbol string) (*BalanceHistory, error) {
-	if err := a.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
-	qparams := make(url.Values)
-	qparams.Set("token", a.apiKey)
-	qparams.Set("period", "quarter")
-	qparams.Set("last", a.lookback)
-	urlpath := path.Join("stock", symbol, "balance-sheet")
-	endpoint := a.baseURL.ResolveReference(&url.URL{Path: urlpath})
-	request, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	request.URL.RawQuery = qparams.Encode()
-
-	resp, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	log.Printf("balance status: %s\n", resp.Status)
+// BalanceSheets returns the last quarterly Balance Sheets for a stock
+func (a *APIConnection) BalanceSheets(ctx context.Context, symbol string, last int) (*BalanceHistory, error) {
 	var balance BalanceHistory
-	if err := json.NewDecoder(resp.Body).Decode(&balance); err != nil {
+	urlpath := path.Join("stock", symbol, "balance-sheet")
+	if err := a.get(ctx, "balance", urlpath, statementParams(last), &balance); err != nil {
 		return nil, err
 	}
 	return &balance, nil
 }
 
-// CashFlows returns the Cash Flow history for a stock
-func (a *APIConnection) CashFlows(ctx context.Context, symbol string) (*CashFlowHistory, error) {
-	if err := a.rateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
-	qparams := make(url.Values)
-	qparams.Set("token", a.apiKey)
-	qparams.Set("period", "quarter")
-	qparams.Set("last", a.lookback)
-	urlpath := path.Join("stock", symbol, "cash-flow")
-	endpoint := a.baseURL.ResolveReference(&url.URL{Path: urlpath})
-	request, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-	request.URL.RawQuery = qparams.Encode()
-
-	resp, err := http.DefaultClient.Do(request)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	log.Printf("cashflow status: %s\n", resp.Status)
+// CashFlows returns the last quarterly Cash Flows for a stock
+func (a *APIConnection) CashFlows(ctx context.Context, symbol string, last int) (*CashFlowHistory, error) {
 	var cashflow CashFlowHistory
-	if err := json.NewDecoder(resp.Body).Decode(&cashflow); err != nil {
+	urlpath := path.Join("stock", symbol, "cash-flow")
+	if err := a.get(ctx, "cashflow", urlpath, statementParams(last), &cashflow); err != nil {
 		return nil, err
 	}
 	return &cashflow, nil
@@ -0,0 +1,38 @@
+package iex
+
+// iex message weights, in credits
+const (
+	SymbolsCredits     = 100
+	ChartCreditsPerBar = 10
+	// dividends and splits are charged per record returned
+	DividendCredits = 10
+	SplitCredits    = 10
+	// each statement is charged per fiscal period returned
+	StatementCredits = 1000
+)
+
+// ChartCredits estimates the cost of a chart/{range} call
+func ChartCredits(rng string) int {
+	return RangeDays(rng) * ChartCreditsPerBar
+}
+
+// DividendsCredits estimates the cost of a dividends/{range} call assuming
+// quarterly payments; at least one record is always assumed
+func DividendsCredits(rng string) int {
+	n := RangeDays(rng) / 63
+	if n < 1 {
+		n = 1
+	}
+	return n * DividendCredits
+}
+
+// SplitsCredits estimates the cost of a splits/{range} call; splits are rare
+// enough that a single record is assumed
+func SplitsCredits(rng string) int {
+	return SplitCredits
+}
+
+// StatementsCredits is the cost of one statement call covering last periods
+func StatementsCredits(last int) int {
+	return last * StatementCredits
+}
@@ -23,6 +23,14 @@ func (ns *NullString) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON writes null or the string, mirroring UnmarshalJSON
+func (ns NullString) MarshalJSON() ([]byte, error) {
+	if !ns.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(ns.String)
+}
+
 // NullNumber wraps a sql.NullFloat64 around a json.NullNumber
 type NullNumber struct{ sql.NullFloat64 }
 
@@ -45,23 +53,39 @@ func (nn *NullNumber) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON writes an empty string or the number, mirroring UnmarshalJSON
+func (nn NullNumber) MarshalJSON() ([]byte, error) {
+	if !nn.Valid {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(nn.Float64)
+}
+
 // NullInt64 mirrors a sql.NullInt64
 type NullInt64 struct{ sql.NullInt64 }
 
 // UnmarshalJSON checks to see if value is an integer or null
-// func (ni *NullInt64) UnmarshalJSON(data []byte) error {
-// 	if bytes.Equal(data, []byte(`null`)) {
-// 		ni.Valid = false
-// 		return nil
-// 	}
-// 	var x int64
-// 	if err := json.Unmarshal(data, &x); err != nil {
-// 		return err
-// 	}
-// 	ni.Valid = true
-// 	ni.Int64 = x
-// 	return nil
-// }
+func (ni *NullInt64) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte(`null`)) {
+		ni.Valid = false
+		return nil
+	}
+	var x int64
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	ni.Valid = true
+	ni.Int64 = x
+	return nil
+}
+
+// MarshalJSON writes null or the integer, mirroring UnmarshalJSON
+func (ni NullInt64) MarshalJSON() ([]byte, error) {
+	if !ni.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(ni.Int64)
+}
 
 // JSONStock represents a single Stock in it's json form
 type JSONStock struct {
@@ -128,6 +152,9 @@ type Prices struct {
 	Alow    float64 `json:"low"`
 	Aclose  float64 `json:"close"`
 	Avolume int     `json:"volume"`
+	// AdjustedOnly marks bars from a vendor that publishes only the
+	// adjusted series; their unadjusted columns are left zero
+	AdjustedOnly bool `json:"adjustedOnly,omitempty"`
 }
 
 // PriceHistory models historical prices for a security
@@ -178,88 +205,97 @@ func (sh SplitHistory) IsEmpty() bool {
 	return len(sh.Splits) == 0
 }
 
+// IncomeStatement models a quarterly income statement
+type IncomeStatement struct {
+	ReportDate             string `json:"reportDate"`
+	FiscalDate             string `json:"fiscalDate"`
+	Currency               string `json:"currency"`
+	TotalRevenue           int64  `json:"totalRevenue"`
+	CostOfRevenue          int64  `json:"costOfRevenue"`
+	GrossProfit            int64  `json:"grossProfit"`
+	ResearchAndDevelopment int64  `json:"researchAndDevelopment"`
+	SellingGeneralAndAdmin int64  `json:"sellingGeneralAndAdmin"`
+	OperatingExpense       int64  `json:"operatingExpense"`
+	OperatingIncome        int64  `json:"operatingIncome"`
+	OtherIncomeExpenseNet  int64  `json:"otherIncomeExpenseNet"`
+	Ebit                   int64  `json:"ebit"`
+	InterestIncome         int64  `json:"interestIncome"`
+	PretaxIncome           int64  `json:"pretaxIncome"`
+	IncomeTax              int64  `json:"incomeTax"`
+	MinorityInterest       int64  `json:"minorityInterest"`
+	NetIncome              int64  `json:"netIncome"`
+	NetIncomeBasic         int64  `json:"netIncomeBasic"`
+}
+
+// BalanceSheet models a quarterly balance sheet
+type BalanceSheet struct {
+	ReportDate              string    `json:"reportDate"`
+	FiscalDate              string    `json:"fiscalDate"`
+	Currency                string    `json:"currency"`
+	CurrentCash             int64     `json:"currentCash"`
+	ShortTermInvestments    int64     `json:"shortTermInvestments"`
+	Receivables             int64     `json:"receivables"`
+	Inventory               int64     `json:"inventory"`
+	OtherCurrentAssets      int64     `json:"otherCurrentAssets"`
+	CurrentAssets           int64     `json:"currentAssets"`
+	LongTermInvestments     int64     `json:"longTermInvestments"`
+	PropertyPlantEquipment  int64     `json:"propertyPlantEquipment"`
+	Goodwill                NullInt64 `json:"goodwill"`
+	IntangibleAssets        NullInt64 `json:"intangibleAssets"`
+	OtherAssets             int64     `json:"otherAssets"`
+	TotalAssets             int64     `json:"totalAssets"`
+	AccountsPayable         int64     `json:"accountsPayable"`
+	CurrentLongTermDebt     int64     `json:"currentLongTermDebt"`
+	OtherCurrentLiabilities int64     `json:"otherCurrentLiabilities"`
+	TotalCurrentLiabilities int64     `json:"totalCurrentLiabilities"`
+	LongTermDebt            int64     `json:"longTermDebt"`
+	OtherLiabilities        int64     `json:"otherLiabilities"`
+	MinorityInterest        int64     `json:"minorityInterest"`
+	TotalLiabilities        int64     `json:"totalLiabilities"`
+	CommonStock             int64     `json:"commonStock"`
+	RetainedEarnings        int64     `json:"retainedEarnings"`
+	TreasuryStock           NullInt64 `json:"treasuryStock"`
+	CapitalSurplus          NullInt64 `json:"capitalSurplus"`
+	ShareholderEquity       int64     `json:"shareholderEquity"`
+	NetTangibleAssets       int64     `json:"netTangibleAssets"`
+}
+
+// CashFlow models a quarterly cash flow statement
+type CashFlow struct {
+	ReportDate              string    `json:"reportDate"`
+	FiscalDate              string    `json:"fiscalDate"`
+	Currency                string    `json:"currency"`
+	NetIncome               int64     `json:"netIncome"`
+	Depreciation            int64     `json:"depreciation"`
+	ChangesInReceivables    int64     `json:"changesInReceivables"`
+	ChangesInInventories    int64     `json:"changesInInventories"`
+	CashChange              int64     `json:"cashChange"`
+	CashFlow                int64     `json:"cashFlow"`
+	CapitalExpenditures     int64     `json:"capitalExpenditures"`
+	Investments             int64     `json:"investments"`
+	InvestingActivityOther  int64     `json:"investingActivityOther"`
+	TotalInvestingCashFlows int64     `json:"totalInvestingCashFlows"`
+	DividendsPaid           int64     `json:"dividendsPaid"`
+	NetBorrowings           int64     `json:"netBorrowings"`
+	OtherFinancingCashFlows int64     `json:"otherFinancingCashFlows"`
+	CashFlowFinancing       int64     `json:"cashFlowFinancing"`
+	ExchangeRateEffect      NullInt64 `json:"exchangeRateEffect"`
+}
+
 // IncomeHistory models historical income statements for a security
 type IncomeHistory struct {
-	Symbol string `json:"symbol"`
-	Income []struct {
-		ReportDate             string `json:"reportDate"`
-		FiscalDate             string `json:"fiscalDate"`
-		Currency               string `json:"currency"`
-		TotalRevenue           int64  `json:"totalRevenue"`
-		CostOfRevenue          int64  `json:"costOfRevenue"`
-		GrossProfit            int64  `json:"grossProfit"`
-		ResearchAndDevelopment int64  `json:"researchAndDevelopment"`
-		SellingGeneralAndAdmin int64  `json:"sellingGeneralAndAdmin"`
-		OperatingExpense       int64  `json:"operatingExpense"`
-		OperatingIncome        int64  `json:"operatingIncome"`
-		OtherIncomeExpenseNet  int64  `json:"otherIncomeExpenseNet"`
-		Ebit                   int64  `json:"ebit"`
-		InterestIncome         int64  `json:"interestIncome"`
-		PretaxIncome           int64  `json:"pretaxIncome"`
-		IncomeTax              int64  `json:"incomeTax"`
-		MinorityInterest       int64  `json:"minorityInterest"`
-		NetIncome              int64  `json:"netIncome"`
-		NetIncomeBasic         int64  `json:"netIncomeBasic"`
-	} `json:"income"`
+	Symbol string            `json:"symbol"`
+	Income []IncomeStatement `json:"income"`
 }
 
 // BalanceHistory models the balance sheet history for a security
 type BalanceHistory struct {
-	Symbol       string `json:"symbol"`
-	Balancesheet []struct {
-		ReportDate              string    `json:"reportDate"`
-		FiscalDate              string    `json:"fiscalDate"`
-		Currency                string    `json:"currency"`
-		CurrentCash             int64     `json:"currentCash"`
-		ShortTermInvestments    int64     `json:"shortTermInvestments"`
-		Receivables             int64     `json:"receivables"`
-		Inventory               int64     `json:"inventory"`
-		OtherCurrentAssets      int64     `json:"otherCurrentAssets"`
-		CurrentAssets           int64     `json:"currentAssets"`
-		LongTermInvestments     int64     `json:"longTermInvestments"`
-		PropertyPlantEquipment  int64     `json:"propertyPlantEquipment"`
-		Goodwill                NullInt64 `json:"goodwill"`
-		IntangibleAssets        NullInt64 `json:"intangibleAssets"`
-		OtherAssets             int64     `json:"otherAssets"`
-		TotalAssets             int64     `json:"totalAssets"`
-		AccountsPayable         int64     `json:"accountsPayable"`
-		CurrentLongTermDebt     int64     `json:"currentLongTermDebt"`
-		OtherCurrentLiabilities int64     `json:"otherCurrentLiabilities"`
-		TotalCurrentLiabilities int64     `json:"totalCurrentLiabilities"`
-		LongTermDebt            int64     `json:"longTermDebt"`
-		OtherLiabilities        int64     `json:"otherLiabilities"`
-		MinorityInterest        int64     `json:"minorityInterest"`
-		TotalLiabilities        int64     `json:"totalLiabilities"`
-		CommonStock             int64     `json:"commonStock"`
-		RetainedEarnings        int64     `json:"retainedEarnings"`
-		TreasuryStock           NullInt64 `json:"treasuryStock"`
-		CapitalSurplus          NullInt64 `json:"capitalSurplus"`
-		ShareholderEquity       int64     `json:"shareholderEquity"`
-		NetTangibleAssets       int64     `json:"netTangibleAssets"`
-	} `json:"balancesheet"`
+	Symbol       string         `json:"symbol"`
+	Balancesheet []BalanceSheet `json:"balancesheet"`
 }
 
 // CashFlowHistory models an iex Cashflow entry
 type CashFlowHistory struct {
-	Symbol   string `json:"symbol"`
-	Cashflow []struct {
-		ReportDate              string    `json:"reportDate"`
-		FiscalDate              string    `json:"fiscalDate"`
-		Currency                string    `json:"currency"`
-		NetIncome               int64     `json:"netIncome"`
-		Depreciation            int64     `json:"depreciation"`
-		ChangesInReceivables    int64     `json:"changesInReceivables"`
-		ChangesInInventories    int64     `json:"changesInInventories"`
-		CashChange              int64     `json:"cashChange"`
-		CashFlow                int64     `json:"cashFlow"`
-		CapitalExpenditures     int64     `json:"capitalExpenditures"`
-		Investments             int64     `json:"investments"`
-		InvestingActivityOther  int64     `json:"investingActivityOther"`
-		TotalInvestingCashFlows int64     `json:"totalInvestingCashFlows"`
-		DividendsPaid           int64     `json:"dividendsPaid"`
-		NetBorrowings           int64     `json:"netBorrowings"`
-		OtherFinancingCashFlows int64     `json:"otherFinancingCashFlows"`
-		CashFlowFinancing       int64     `json:"cashFlowFinancing"`
-		ExchangeRateEffect      NullInt64 `json:"exchangeRateEffect"`
-	} `json:"cashflow"`
+	Symbol   string     `json:"symbol"`
+	Cashflow []CashFlow `json:"cashflow"`
 }
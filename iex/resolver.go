@@ -1,6 +1,6 @@
 package iex
 
-import "fmt"
+import "defcor/logging"
 
 func makeStockSets(A, B StockGroup) (map[Stock]struct{}, map[Stock]struct{}) {
 	sA := make(map[Stock]struct{})
@@ -91,7 +91,7 @@ func changes(m map[string]int, bp []int) []int {
 func extractGroup(A StockGroup, xi []int) StockGroup {
 	var corrected StockGroup
 	for i := range xi {
-		corrected = append(corrected, A[i])
+		corrected = append(corrected, A[xi[i]])
 	}
 	return corrected
 }
@@ -116,21 +116,17 @@ func Resolve(Existing, Refreshed StockGroup) (map[Stock]Stock, []Stock, []Stock)
 	return updates, deletes, additions
 }
 
-// FormatOutput pretty prints the Resolve func
+// FormatOutput logs the result of the Resolve func to the default logger
 func FormatOutput(A, B StockGroup) {
+	log := logging.Default()
 	updates, deletes, additions := Resolve(A, B)
-	fmt.Println("\nUpdates:")
 	for k, v := range updates {
-		fmt.Println("\nreconcile...")
-		fmt.Println("\tprev:", k)
-		fmt.Println("\tcurr:", v)
+		log.Info("reconcile", "prev", k, "curr", v)
 	}
-	fmt.Println("\nEnds:")
 	for i, s := range deletes {
-		fmt.Printf("%2d: %v\n", i, s)
+		log.Info("end", "n", i, "stock", s)
 	}
-	fmt.Println("\nAdds:")
 	for i, s := range additions {
-		fmt.Printf("%2d: %v\n", i, s)
+		log.Info("add", "n", i, "stock", s)
 	}
 }
@@ -0,0 +1,89 @@
+package iex
+
+import (
+	"sort"
+	"testing"
+)
+
+func symbols(g []Stock) []string {
+	var ss []string
+	for _, s := range g {
+		ss = append(ss, s.Symbol)
+	}
+	sort.Strings(ss)
+	return ss
+}
+
+func TestResolve(t *testing.T) {
+	aaa := Stock{Symbol: "AAA", Name: "Aaa", IexID: "IEX_A"}
+	old := Stock{Symbol: "OLD", Name: "Old", IexID: "IEX_O"}
+	renamed := Stock{Symbol: "NEW", Name: "New", IexID: "IEX_O"}
+	zzz := Stock{Symbol: "ZZZ", Name: "Zzz", IexID: "IEX_Z"}
+	ddd := Stock{Symbol: "DDD", Name: "Ddd", IexID: "IEX_D"}
+	tests := []struct {
+		name      string
+		existing  StockGroup
+		refreshed StockGroup
+		updates   map[Stock]Stock
+		deletes   []string
+		additions []string
+	}{
+		{
+			name:      "unchanged",
+			existing:  StockGroup{aaa, old},
+			refreshed: StockGroup{old, aaa},
+		},
+		{
+			name:      "rename, end and add",
+			existing:  StockGroup{aaa, old, zzz},
+			refreshed: StockGroup{aaa, renamed, ddd},
+			updates:   map[Stock]Stock{old: renamed},
+			deletes:   []string{"ZZZ"},
+			additions: []string{"DDD"},
+		},
+		{
+			name:      "order does not matter",
+			existing:  StockGroup{zzz, aaa, old},
+			refreshed: StockGroup{ddd, renamed, aaa},
+			updates:   map[Stock]Stock{old: renamed},
+			deletes:   []string{"ZZZ"},
+			additions: []string{"DDD"},
+		},
+		{
+			name:      "first load adds everything",
+			refreshed: StockGroup{aaa, ddd},
+			additions: []string{"AAA", "DDD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates, deletes, additions := Resolve(tt.existing, tt.refreshed)
+			if len(updates) != len(tt.updates) {
+				t.Errorf("updates %v, want %v", updates, tt.updates)
+			}
+			for prev, curr := range tt.updates {
+				if updates[prev] != curr {
+					t.Errorf("update of %s = %v, want %v", prev.Symbol, updates[prev], curr)
+				}
+			}
+			if got := symbols(deletes); !equal(got, tt.deletes) {
+				t.Errorf("deletes %v, want %v", got, tt.deletes)
+			}
+			if got := symbols(additions); !equal(got, tt.additions) {
+				t.Errorf("additions %v, want %v", got, tt.additions)
+			}
+		})
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level orders the severity of a record
+type Level int
+
+// Levels from least to most severe
+const (
+	Debug Level = iota
+	Info
+	Warn
+	Error
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < Debug || l > Error {
+		return strconv.Itoa(int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel reads debug, info, warn or error; empty means info
+func ParseLevel(s string) (Level, error) {
+	if s == "" {
+		return Info, nil
+	}
+	for i, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return Level(i), nil
+		}
+	}
+	return Info, fmt.Errorf("unknown log level %q", s)
+}
+
+// Format is the encoding of a record
+type Format string
+
+// Supported formats
+const (
+	Logfmt Format = "logfmt"
+	JSON   Format = "json"
+)
+
+// ParseFormat reads logfmt or json; empty means logfmt
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", Logfmt:
+		return Logfmt, nil
+	case JSON:
+		return JSON, nil
+	}
+	return Logfmt, fmt.Errorf("unknown log format %q", s)
+}
+
+// sink is the destination shared by a logger and everything derived from it
+type sink struct {
+	mu     sync.Mutex
+	out    io.Writer
+	level  Level
+	format Format
+}
+
+// Logger writes leveled records of a message and key value fields.
+// Loggers derived with With share their parent's output.
+// A nil *Logger writes to Default.
+type Logger struct {
+	sink   *sink
+	fields []interface{}
+}
+
+// New creates a logger writing records at level or above to out
+func New(out io.Writer, level Level, format Format) *Logger {
+	return &Logger{sink: &sink{out: out, level: level, format: format}}
+}
+
+var std = New(os.Stdout, Info, Logfmt)
+
+// Default is the logfmt logger at info level on stdout
+func Default() *Logger {
+	return std
+}
+
+// With returns a logger adding the key value pairs kv to every record
+func (l *Logger) With(kv ...interface{}) *Logger {
+	if l == nil {
+		l = std
+	}
+	fields := make([]interface{}, 0, len(l.fields)+len(kv))
+	fields = append(append(fields, l.fields...), kv...)
+	return &Logger{sink: l.sink, fields: fields}
+}
+
+// Debug logs msg with the key value pairs kv at debug level
+func (l *Logger) Debug(msg string, kv ...interface{}) { l.log(Debug, msg, kv) }
+
+// Info logs msg with the key value pairs kv at info level
+func (l *Logger) Info(msg string, kv ...interface{}) { l.log(Info, msg, kv) }
+
+// Warn logs msg with the key value pairs kv at warn level
+func (l *Logger) Warn(msg string, kv ...interface{}) { l.log(Warn, msg, kv) }
+
+// Error logs msg with the key value pairs kv at error level
+func (l *Logger) Error(msg string, kv ...interface{}) { l.log(Error, msg, kv) }
+
+func (l *Logger) log(level Level, msg string, kv []interface{}) {
+	if l == nil {
+		l = std
+	}
+	s := l.sink
+	if level < s.level {
+		return
+	}
+	fields := make([]interface{}, 0, 6+len(l.fields)+len(kv))
+	fields = append(fields, "time", time.Now().UTC(), "level", level, "msg", msg)
+	fields = append(append(fields, l.fields...), kv...)
+	if len(fields)%2 != 0 {
+		fields = append(fields, "!MISSING")
+	}
+	var b strings.Builder
+	if s.format == JSON {
+		writeJSON(&b, fields)
+	} else {
+		writeLogfmt(&b, fields)
+	}
+	b.WriteByte('\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	io.WriteString(s.out, b.String())
+}
+
+// value renders the types with a better log form than their default
+func value(v interface{}) interface{} {
+	switch x := v.(type) {
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	case time.Duration:
+		return x.String()
+	case Level:
+		return x.String()
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	}
+	return v
+}
+
+func writeLogfmt(b *strings.Builder, fields []interface{}) {
+	for i := 0; i < len(fields); i += 2 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(fmt.Sprint(fields[i]))
+		b.WriteByte('=')
+		s := fmt.Sprint(value(fields[i+1]))
+		if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+			s = strconv.Quote(s)
+		}
+		b.WriteString(s)
+	}
+}
+
+func writeJSON(b *strings.Builder, fields []interface{}) {
+	b.WriteByte('{')
+	for i := 0; i < len(fields); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(fmt.Sprint(fields[i]))
+		b.Write(key)
+		b.WriteByte(':')
+		val, err := json.Marshal(value(fields[i+1]))
+		if err != nil {
+			val, _ = json.Marshal(fmt.Sprint(fields[i+1]))
+		}
+		b.Write(val)
+	}
+	b.WriteByte('}')
+}
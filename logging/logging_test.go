@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogfmt(t *testing.T) {
+	tests := []struct {
+		name string
+		kv   []interface{}
+		want string
+	}{
+		{"bare values", []interface{}{"symbol", "AAPL", "rows", 5}, "symbol=AAPL rows=5"},
+		{"spaces are quoted", []interface{}{"name", "Apple Inc"}, `name="Apple Inc"`},
+		{"quotes are escaped", []interface{}{"detail", `say "hi"`}, `detail="say \"hi\""`},
+		{"equals is quoted", []interface{}{"q", "a=b"}, `q="a=b"`},
+		{"empty is quoted", []interface{}{"err", ""}, `err=""`},
+		{"errors render their message", []interface{}{"err", errors.New("no rows")}, `err="no rows"`},
+		{"durations render as text", []interface{}{"took", 1500 * time.Millisecond}, "took=1.5s"},
+		{"odd fields are marked", []interface{}{"symbol"}, "symbol=!MISSING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			New(&buf, Info, Logfmt).Info("fetched", tt.kv...)
+			line := strings.TrimSuffix(buf.String(), "\n")
+			if !strings.Contains(line, " level=info msg=fetched ") || !strings.HasSuffix(line, " "+tt.want) {
+				t.Errorf("got %s, want it to end with %s", line, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	New(&buf, Info, JSON).With("run_id", "r1").Warn("slow", "took", time.Second, "rows", 3, "name", `a "b"`)
+	var rec map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("%s is not json: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"level": "warn", "msg": "slow", "run_id": "r1", "took": "1s", "rows": 3.0, "name": `a "b"`,
+	}
+	for k, v := range want {
+		if rec[k] != v {
+			t.Errorf("%s = %v, want %v", k, rec[k], v)
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, rec["time"].(string)); err != nil {
+		t.Errorf("time %v: %v", rec["time"], err)
+	}
+}
+
+func TestLevelFilter(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, Warn, Logfmt)
+	l.Debug("d")
+	l.Info("i")
+	l.Warn("w")
+	l.With("k", "v").Error("e")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "msg=w") || !strings.Contains(lines[1], "msg=e k=v") {
+		t.Errorf("got %q, want only the warn and error records", lines)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]Level{"": Info, "debug": Debug, "WARN": Warn, "error": Error} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("ParseLevel(loud) succeeded")
+	}
+	for in, want := range map[string]Format{"": Logfmt, "logfmt": Logfmt, "json": JSON} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) succeeded")
+	}
+}
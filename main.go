@@ -1,14 +1,31 @@
 package main
 
 import (
+	"context"
+	"defcor/adjust"
 	"defcor/app"
 	"defcor/db"
+	"defcor/export"
 	"defcor/iex"
+	"defcor/logging"
+	"defcor/metrics"
+	"defcor/provider"
+	"defcor/quality"
+	"defcor/rest"
+	"defcor/rotation"
+	"defcor/rpc"
+	"defcor/webhook"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 )
 
@@ -18,100 +35,1067 @@ var environment = app.Environment{
 	Lookback: "5d",
 	Duration: 60 * time.Millisecond,
 	DbURL:    os.Getenv("DATABASE_URL_PROD"),
+	CacheDir: os.Getenv("IEXCLOUD_CACHE_DIR"),
+	Archive:  os.Getenv("DEFCOR_ARCHIVE") != "",
+	StooqURL: os.Getenv("DEFCOR_STOOQ_URL"),
+	// materialize total_return_index during seeding
+	TotalReturn: os.Getenv("DEFCOR_TOTAL_RETURN") != "",
+	// batches land in staging unless explicitly disabled
+	Staging: os.Getenv("DEFCOR_NO_STAGING") == "",
+	Burst:   1,
 }
 
+// logger is configured by DEFCOR_LOG_LEVEL and DEFCOR_LOG_FORMAT
+var logger = logging.Default()
+
 func main() {
-	log.SetOutput(os.Stdout)
-	log.SetFlags(log.Lmicroseconds | log.LUTC)
-	if err := run(); err != nil {
+	if err := run(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
+func run(args []string) error {
+	cmd := "seed"
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+	mode, err := iex.ParseCacheMode(os.Getenv("IEXCLOUD_CACHE_MODE"))
+	if err != nil {
+		return err
+	}
+	environment.CacheMode = mode
+	// e.g. DEFCOR_SOURCES="stocks=iex;dividends=iex"
+	priority, err := provider.ParsePriority(os.Getenv("DEFCOR_SOURCES"))
+	if err != nil {
+		return err
+	}
+	environment.Priority = priority
+	// e.g. DEFCOR_STOOQ_SYMBOLS="BRK.B=brk-b.us,BF.B=bf-b.us"
+	tickers, err := provider.ParseTickers(os.Getenv("DEFCOR_STOOQ_SYMBOLS"))
+	if err != nil {
+		return err
+	}
+	environment.StooqSymbols = tickers
+	level, err := logging.ParseLevel(os.Getenv("DEFCOR_LOG_LEVEL"))
+	if err != nil {
+		return err
+	}
+	format, err := logging.ParseFormat(os.Getenv("DEFCOR_LOG_FORMAT"))
+	if err != nil {
+		return err
+	}
+	logger = logging.New(os.Stdout, level, format)
+	environment.Logger = logger
+	// DEFCOR_RATE_LIMIT=shared paces requests across every process
+	switch limit := os.Getenv("DEFCOR_RATE_LIMIT"); limit {
+	case "", "local":
+	case "shared":
+		environment.SharedLimit = true
+	default:
+		return fmt.Errorf("unknown rate limit %q, want local or shared", limit)
+	}
+	// DEFCOR_RATE_BURST lets a shared bucket save up that many requests
+	if burst := os.Getenv("DEFCOR_RATE_BURST"); burst != "" {
+		n, err := strconv.Atoi(burst)
+		if err != nil || n < 1 {
+			return fmt.Errorf("bad rate burst %q, want a positive number", burst)
+		}
+		environment.Burst = n
+	}
 	myapp, err := app.Start(environment)
 	if err != nil {
 		return fmt.Errorf("setting up app: %w", err)
 	}
 	defer myapp.End()
+	err = dispatch(myapp, cmd, args)
+	if longRunning[cmd] {
+		return err
+	}
+	if ingestion[cmd] {
+		if nerr := myapp.DB.RunComplete(myapp.RunID, cmd, err); nerr != nil {
+			logger.Warn("notifying run completion failed", "err", nerr)
+		}
+	}
+	// one-shot runs hand their metrics to a pushgateway, e.g. http://pushgateway:9091
+	if url := os.Getenv("DEFCOR_PUSHGATEWAY"); url != "" {
+		if perr := metrics.Push(url, "defcor", cmd); perr != nil {
+			logger.Warn("pushing metrics failed", "url", url, "err", perr)
+		}
+	}
+	return err
+}
+
+// longRunning commands serve until stopped rather than completing a run
+var longRunning = map[string]bool{
+	"serve": true, "daemon": true, "api": true, "grpc": true, "watch": true, "deliver": true,
+}
+
+// ingestion commands write stored data, so their runs notify run_complete
+var ingestion = map[string]bool{
+	"seed": true, "load": true, "update": true, "backfill": true, "financials": true,
+	"adjust": true, "tri": true, "release": true, "reprocess": true,
+}
+
+func dispatch(myapp *app.Application, cmd string, args []string) error {
+	switch cmd {
+	case "seed":
+		return seed(myapp, args)
+	case "load":
+		return load(myapp, args)
+	case "compare":
+		return compare(myapp, args)
+	case "adjust":
+		return adjustPrices(myapp, args)
+	case "validate":
+		return validate(myapp, args)
+	case "verify":
+		return verify(myapp, args)
+	case "update":
+		symbols, err := myapp.DB.Symbols()
+		if err != nil {
+			return fmt.Errorf("getting symbols: %w", err)
+		}
+		return myapp.Update(symbols)
+	case "backfill":
+		return backfillGaps(myapp, args)
+	case "serve", "daemon":
+		return serve(myapp, args)
+	case "api":
+		return api(myapp, args)
+	case "grpc":
+		return grpcServe(myapp, args)
+	case "export":
+		return exportData(myapp, args)
+	case "webhook":
+		return webhooks(myapp, args)
+	case "deliver":
+		return deliver(myapp, args)
+	case "watch":
+		return watch(myapp, args)
+	case "token":
+		return token(myapp, args)
+	case "enqueue":
+		return enqueue(myapp, args)
+	case "work":
+		return work(myapp, args)
+	case "jobs":
+		return jobs(myapp)
+	case "requeue":
+		// requeue job-id...
+		for _, arg := range args {
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return fmt.Errorf("bad job id %q", arg)
+			}
+			if err := myapp.DB.RequeueJob(id); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "financials":
+		return financials(myapp, args)
+	case "freshness":
+		return freshness(myapp, args)
+	case "plan":
+		return planCost(myapp, args)
+	case "tri":
+		return totalReturn(myapp, args)
+	case "quarantine":
+		return quarantine(myapp, args)
+	case "release":
+		// release qid...
+		for _, arg := range args {
+			qid, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return fmt.Errorf("bad quarantine id %q", arg)
+			}
+			if err := myapp.DB.ReleaseQuarantine(qid); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "reprocess":
+		// reprocess [run-id...]
+		return myapp.Reprocess(args...)
+	}
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+// seed refreshes the stocks table and seeds every symbol, or with -shard or
+// -budget updates every symbol and deep refreshes today's rotation
+// usage: seed [-shard oldest|N/M|M] [-budget credits] [-deep 5y]
+func seed(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	shard := fs.String("shard", "", "deep refresh oldest first, day N of an M day cycle (N/M) or today's day of M")
+	budget := fs.Int("budget", 0, "credits available to the run, daily updates included; required for -shard oldest")
+	deep := fs.String("deep", "5y", "range of a deep refresh")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
 	if err := myapp.RefreshStocks(); err != nil {
-		panic(err)
+		return fmt.Errorf("refreshing stocks: %w", err)
 	}
 	symbols, err := myapp.DB.Symbols()
 	if err != nil {
 		return fmt.Errorf("getting symbols: %w", err)
 	}
 	// revised := restOfStocks("WUBA", symbols)
+	logger.Info("seeding", "run_id", myapp.RunID)
+	if *shard != "" || *budget > 0 {
+		spec, err := rotation.ParseSpec(*shard, time.Now())
+		if err != nil {
+			return err
+		}
+		r := app.Rotation{Spec: spec, Budget: *budget, Range: *deep}
+		if err := myapp.SeedRotation(symbols, r); err != nil {
+			return fmt.Errorf("seeding problem: %w", err)
+		}
+		return nil
+	}
 	if err := myapp.Seed(symbols); err != nil {
 		return fmt.Errorf("seeding problem: %w", err)
 	}
 	return nil
 }
 
-func restOfStocks(element string, data []string) []string {
-	var x int
-	for k, v := range data {
-		if v == element {
-			x = k
+// load seeds the database from csv or json exports
+// usage: load [-format csv|json] [-columns field=column,...] dir [symbol...]
+func load(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("load", flag.ContinueOnError)
+	format := fs.String("format", "csv", "file format: csv or json")
+	columns := fs.String("columns", "", "column mapping, e.g. date=Date,close=Close")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("load: missing directory")
+	}
+	mapping, err := provider.ParseMapping(*columns)
+	if err != nil {
+		return err
+	}
+	files := provider.NewFile(fs.Arg(0), provider.Format(*format), mapping)
+	symbols := fs.Args()[1:]
+	if len(symbols) == 0 {
+		if symbols, err = files.Symbols(); err != nil {
+			return fmt.Errorf("listing symbols: %w", err)
 		}
 	}
-	return data[x+1:]
+	myapp.UseSource(files)
+	if err := myapp.CompleteStocks(symbols); err != nil {
+		return fmt.Errorf("loading stocks: %w", err)
+	}
+	return myapp.Seed(symbols)
 }
 
-func readtesterfile() ([]iex.Dividend, error) {
-	f, err := os.Open("tester.json")
+// compare flags price disagreements between sources
+// usage: compare [-sources iex,stooq] [-from date] [-to date] [-close 0.005] [-volume 0.25] [symbol...]
+func compare(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	sources := fs.String("sources", "iex,stooq", "sources to compare, the first is the reference")
+	from := fs.String("from", "-infinity", "first date")
+	to := fs.String("to", "infinity", "last date")
+	closeTol := fs.Float64("close", 0.005, "relative close tolerance")
+	volumeTol := fs.Float64("volume", 0.25, "relative volume tolerance")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	symbols := fs.Args()
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = myapp.DB.Symbols(); err != nil {
+			return fmt.Errorf("getting symbols: %w", err)
+		}
+	}
+	suspects, err := myapp.Compare(strings.Split(*sources, ","), symbols, *from, *to,
+		db.Tolerance{Close: *closeTol, Volume: *volumeTol},
+	)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer f.Close()
-	bytedata, err := ioutil.ReadAll(f)
+	for _, s := range suspects {
+		fmt.Printf("%-6s %4d flagged %s..%s max deviation %.4f\n",
+			s.Symbol, s.Count, s.First.Format("2006-01-02"), s.Last.Format("2006-01-02"), s.MaxDeviation)
+	}
+	return nil
+}
+
+// adjustPrices prints a locally adjusted price series as csv
+// usage: adjust [-asof date] [-mode split|total] symbol
+func adjustPrices(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("adjust", flag.ContinueOnError)
+	asOf := fs.String("asof", "", "ignore corporate actions after this date")
+	modeName := fs.String("mode", "split", "split or total")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("adjust: expected one symbol")
+	}
+	mode, err := adjust.ParseMode(*modeName)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var ds []iex.Dividend
-	if err := json.Unmarshal(bytedata, &ds); err != nil {
-		return nil, err
+	ph, err := myapp.AdjustedPrices(fs.Arg(0), *asOf, mode)
+	if err != nil {
+		return err
 	}
-	return ds, nil
+	fmt.Println("date,open,high,low,close,volume")
+	for _, p := range ph.Prices {
+		fmt.Printf("%s,%.4f,%.4f,%.4f,%.4f,%d\n", p.Date, p.Aopen, p.Ahigh, p.Alow, p.Aclose, p.Avolume)
+	}
+	return nil
 }
 
-func customupload(filename string) ([]iex.PriceHistory, error) {
-	f, err := os.Open(filename)
+// verify reports dates where stored adjusted closes disagree with local factors
+// usage: verify [-mode split|total] [-tol 0.001] [symbol...]
+func verify(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	modeName := fs.String("mode", "split", "split or total")
+	tol := fs.Float64("tol", 0.001, "relative tolerance")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	mode, err := adjust.ParseMode(*modeName)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer f.Close()
-	bytedata, err := ioutil.ReadAll(f)
+	symbols := fs.Args()
+	if len(symbols) == 0 {
+		if symbols, err = myapp.DB.Symbols(); err != nil {
+			return fmt.Errorf("getting symbols: %w", err)
+		}
+	}
+	for _, symb := range symbols {
+		findings, err := myapp.Verify(symb, mode, *tol)
+		if err != nil {
+			return fmt.Errorf("verifying %s: %w", symb, err)
+		}
+		for _, f := range findings {
+			fmt.Printf("%-6s %s %-8s observed %.6f expected %.6f: %s\n",
+				f.Symbol, f.Date, f.Kind, f.Observed, f.Expected, f.Suggestion)
+		}
+	}
+	return nil
+}
+
+// totalReturn materializes the total return index
+// usage: tri [-full] [symbol...]
+func totalReturn(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("tri", flag.ContinueOnError)
+	full := fs.Bool("full", false, "rebuild from the first bar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	symbols := fs.Args()
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = myapp.DB.Symbols(); err != nil {
+			return fmt.Errorf("getting symbols: %w", err)
+		}
+	}
+	for _, symb := range symbols {
+		if err := myapp.UpdateTotalReturn(symb, *full); err != nil {
+			return fmt.Errorf("total return %s: %w", symb, err)
+		}
+	}
+	return nil
+}
+
+// validate runs the data quality rules over the prices table
+// usage: validate [-rules ohlc,gaps,...] [-from date] [-to date] [-extreme 0.5] [-stale 5] [symbol...]
+func validate(myapp *app.Application, args []string) error {
+	cfg := quality.DefaultConfig()
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	ruleNames := fs.String("rules", "", "comma separated rules, all when empty")
+	from := fs.String("from", "-infinity", "first date")
+	to := fs.String("to", "infinity", "last date")
+	fs.Float64Var(&cfg.ExtremeReturn, "extreme", cfg.ExtremeReturn, "absolute daily return flagged as extreme")
+	fs.IntVar(&cfg.StaleRun, "stale", cfg.StaleRun, "identical consecutive closes flagged as stale")
+	fs.Float64Var(&cfg.RatioTolerance, "ratio", cfg.RatioTolerance, "tolerated spread of adjustment ratios within a bar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var names []string
+	if *ruleNames != "" {
+		names = strings.Split(*ruleNames, ",")
+	}
+	rules, err := quality.Rules(cfg, names...)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var this map[string][]iex.Prices
-	if err := json.Unmarshal(bytedata, &this); err != nil {
-		return nil, err
+	symbols := fs.Args()
+	if len(symbols) == 0 {
+		if symbols, err = myapp.DB.Symbols(); err != nil {
+			return fmt.Errorf("getting symbols: %w", err)
+		}
+	}
+	counts, err := myapp.Validate(symbols, *from, *to, rules)
+	if err != nil {
+		return err
+	}
+	for _, sev := range []quality.Severity{quality.Error, quality.Warning, quality.Info} {
+		fmt.Printf("%-8s %d\n", sev, counts[sev])
+	}
+	return nil
+}
+
+// quarantine lists rows held back by staging validation
+// usage: quarantine [-all]
+func quarantine(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("quarantine", flag.ContinueOnError)
+	all := fs.Bool("all", false, "include released rows")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	rows, err := myapp.DB.Quarantined(*all)
+	if err != nil {
+		return err
+	}
+	for _, q := range rows {
+		released := ""
+		if q.ReleasedAt != nil {
+			released = " released " + q.ReleasedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%6d %-6s %-9s batch %d: %s%s\n  %s\n", q.QID, q.Symbol, q.Kind, q.BatchID, q.Reasons, released, q.Payload)
+	}
+	return nil
+}
+
+// backfillGaps fills holes in the stored price history
+// usage: backfill [-dry-run] [-call-cost 20] [symbol...]
+func backfillGaps(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "print the plan without calling iex")
+	callCost := fs.Int("call-cost", 20, "credit equivalent charged per request when choosing calls")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	symbols := fs.Args()
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = myapp.DB.Symbols(); err != nil {
+			return fmt.Errorf("getting symbols: %w", err)
+		}
+	}
+	plans, err := myapp.PlanBackfill(symbols, *callCost)
+	if err != nil {
+		return err
+	}
+	var calls, credits int
+	for _, plan := range plans {
+		fmt.Printf("%s: %d gaps, %d credits\n", plan.Symbol, len(plan.Gaps), plan.Credits())
+		for _, g := range plan.Gaps {
+			fmt.Printf("  gap %s..%s (%d sessions)\n", g.From.Format("2006-01-02"), g.To.Format("2006-01-02"), g.Days)
+		}
+		for _, c := range plan.Calls {
+			fmt.Printf("  call %s (%d credits)\n", c.Endpoint(), c.Credits)
+		}
+		calls += len(plan.Calls)
+		credits += plan.Credits()
+	}
+	fmt.Printf("total: %d symbols, %d calls, %d credits\n", len(plans), calls, credits)
+	if *dryRun {
+		return nil
+	}
+	return myapp.Backfill(plans)
+}
+
+// serve runs the ingestion jobs on their schedules until SIGTERM or SIGINT,
+// letting the running job finish its current symbol
+// usage: serve [-listen :9090] [sla flags] [-stocks cron] [-prices cron] [-events cron] [-financials cron]
+func serve(myapp *app.Application, args []string) error {
+	s := app.DefaultSchedules
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("listen", ":9090", "address serving /metrics and /health; empty disables")
+	sla := slaFlags(fs)
+	fs.StringVar(&s.Stocks, "stocks", s.Stocks, "reference data refresh, exchange time; empty disables")
+	fs.StringVar(&s.Prices, "prices", s.Prices, "daily price update, exchange time; empty disables")
+	fs.StringVar(&s.Events, "events", s.Events, "dividend and split refresh, exchange time; empty disables")
+	fs.StringVar(&s.Financials, "financials", s.Financials, "financial statements refresh, exchange time; empty disables")
+	fs.StringVar(&s.EventRange, "event-range", s.EventRange, "range of the dividend and split refresh")
+	fs.IntVar(&s.Quarters, "quarters", s.Quarters, "fiscal quarters fetched per statement")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	sched, err := myapp.Scheduler(s)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	served := make(chan error, 1)
+	if *addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		mux.Handle("/health", myapp.FreshnessHandler(*sla))
+		go func() {
+			err := listen(ctx, *addr, mux)
+			// the scheduler stops with the endpoint
+			cancel()
+			served <- err
+		}()
+	} else {
+		served <- nil
+	}
+	err = sched.Run(ctx)
+	cancel()
+	if serr := <-served; serr != nil {
+		return serr
+	}
+	return err
+}
+
+// api serves the read-only rest api until SIGTERM or SIGINT
+// usage: api [-listen :8080]
+func api(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	addr := fs.String("listen", ":8080", "address serving the api")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	return listen(ctx, *addr, rest.NewServer(myapp.DB, logger))
+}
+
+// grpcServe serves the grpc api until SIGTERM or SIGINT, letting open streams finish
+// usage: grpc [-listen :9092]
+func grpcServe(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("grpc", flag.ContinueOnError)
+	addr := fs.String("listen", ":9092", "address serving the grpc api")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
-	var allPrices []iex.PriceHistory
-	for k, entry := range this {
-		allPrices = append(allPrices, iex.PriceHistory{
-			Symbol: k,
-			Prices: entry,
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	srv := rpc.NewServer(myapp.DB, logger)
+	ctx, cancel := signalContext()
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+	logger.Info("listening", "addr", *addr, "protocol", "grpc")
+	return srv.Serve(lis)
+}
+
+// watch prints ingestion events as they are committed until SIGTERM or SIGINT
+// usage: watch [-json] [-kinds bars,dividend,split,stock_added,stock_deactivated,stock_renamed] [symbol...]
+func watch(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print each event as a json line")
+	kindList := fs.String("kinds", "", "comma separated event kinds to print; empty prints all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	kinds := make(map[string]bool)
+	for _, k := range strings.Split(*kindList, ",") {
+		if k != "" {
+			kinds[k] = true
+		}
+	}
+	symbols := make(map[string]bool)
+	for _, s := range fs.Args() {
+		symbols[strings.ToUpper(s)] = true
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	enc := json.NewEncoder(os.Stdout)
+	return myapp.DB.Subscribe(ctx, func(e db.Event) error {
+		if (len(kinds) > 0 && !kinds[e.Kind]) || (len(symbols) > 0 && !symbols[e.Symbol] && !symbols[e.PrevSymbol]) {
+			return nil
+		}
+		if *asJSON {
+			return enc.Encode(e)
+		}
+		stamp := time.Now().Format(time.RFC3339)
+		switch e.Kind {
+		case db.EventBars:
+			fmt.Printf("%s %-17s %-6s %s..%s (%d bars)\n", stamp, e.Kind, e.Symbol, e.From, e.To, e.Rows)
+		case db.EventDividend, db.EventSplit:
+			fmt.Printf("%s %-17s %-6s ex %s\n", stamp, e.Kind, e.Symbol, e.ExDate)
+		case db.EventStockRenamed:
+			fmt.Printf("%s %-17s %-6s was %s\n", stamp, e.Kind, e.Symbol, e.PrevSymbol)
+		case db.EventRunComplete:
+			fmt.Printf("%s %-17s %s %s %s\n", stamp, e.Kind, e.Command, e.RunID, e.Error)
+		default:
+			fmt.Printf("%s %-17s %-6s secid %d\n", stamp, e.Kind, e.Symbol, e.Secid)
+		}
+		return nil
+	})
+}
+
+// token manages api tokens; a new token is printed once and only its hash kept
+// usage: token add name | token revoke name | token list
+func token(myapp *app.Application, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("token: missing add, revoke or list")
+	}
+	switch args[0] {
+	case "add", "revoke":
+		if len(args) != 2 {
+			return fmt.Errorf("token %s: want a single name", args[0])
+		}
+		if args[0] == "revoke" {
+			if err := myapp.DB.RevokeToken(args[1]); err == db.ErrNotFound {
+				return fmt.Errorf("no live token named %q", args[1])
+			} else if err != nil {
+				return err
+			}
+			return nil
+		}
+		t, err := rest.GenerateToken()
+		if err != nil {
+			return err
+		}
+		if err := myapp.DB.CreateToken(args[1], rest.HashToken(t)); err != nil {
+			return fmt.Errorf("storing token %q: %w", args[1], err)
+		}
+		fmt.Println(t)
+		return nil
+	case "list":
+		tokens, err := myapp.DB.Tokens()
+		if err != nil {
+			return err
+		}
+		for _, t := range tokens {
+			status := "live"
+			if t.RevokedAt != nil {
+				status = "revoked " + t.RevokedAt.Format(time.RFC3339)
+			}
+			used := "never used"
+			if t.LastUsedAt != nil {
+				used = "used " + t.LastUsedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%-20s created %s, %s, %s\n", t.Name, t.CreatedAt.Format(time.RFC3339), used, status)
+		}
+		return nil
+	}
+	return fmt.Errorf("token: unknown subcommand %q", args[0])
+}
+
+// listen serves handler on addr until ctx is done; a shutdown returns nil,
+// failing to serve (e.g. the address is in use) returns the error
+func listen(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{Addr: addr, Handler: handler}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+	logger.Info("listening", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("listening on %s: %w", addr, err)
+	}
+	return nil
+}
+
+// signalContext is cancelled on SIGTERM or SIGINT
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		select {
+		case sig := <-sigs:
+			logger.Info("shutting down, finishing current work", "signal", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(sigs)
+	}()
+	return ctx, cancel
+}
+
+// enqueue queues ingestion jobs for workers
+// usage: enqueue [-kinds prices,dividends,splits] [-range 5y] [-max-attempts 5] [symbol...]
+func enqueue(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("enqueue", flag.ContinueOnError)
+	kinds := fs.String("kinds", "prices,dividends,splits", "comma separated job kinds: "+strings.Join(app.JobKinds, ", "))
+	rng := fs.String("range", "5y", "iex range, or quarters for financials")
+	maxAttempts := fs.Int("max-attempts", 5, "attempts before a job is dead-lettered")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	symbols, err := symbolsOrAll(myapp, fs.Args())
+	if err != nil {
+		return err
+	}
+	n, err := myapp.Enqueue(symbols, strings.Split(*kinds, ","), *rng, *maxAttempts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("queued %d jobs\n", n)
+	return nil
+}
+
+// work consumes the job queue until SIGTERM or SIGINT
+// usage: work [-name worker] [-lease 5m] [-once]
+func work(myapp *app.Application, args []string) error {
+	host, _ := os.Hostname()
+	var w app.Worker
+	fs := flag.NewFlagSet("work", flag.ContinueOnError)
+	fs.StringVar(&w.Name, "name", fmt.Sprintf("%s-%d", host, os.Getpid()), "worker name recorded on claimed jobs")
+	fs.DurationVar(&w.Lease, "lease", 5*time.Minute, "how long a claimed job is held")
+	fs.DurationVar(&w.Backoff, "backoff", 30*time.Second, "delay before the first retry, doubled per attempt")
+	fs.DurationVar(&w.Idle, "idle", 10*time.Second, "pause between polls of an empty queue")
+	fs.BoolVar(&w.Once, "once", false, "exit when nothing is runnable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	myapp.SetContext(ctx)
+	// workers only add throughput if they share one rate limit
+	myapp.ShareRateLimit()
+	return myapp.Work(w)
+}
+
+// exportData writes extracts of the stored data for research
+// usage: export [-dir export] [-format csv|jsonl|parquet] [-partition symbol|year]
+// [-datasets stocks,prices,dividends,splits,financials] [-from date] [-to date]
+// [-adjusted raw|iex|split|total] [symbol...]
+func exportData(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	dir := fs.String("dir", "export", "directory the files are written under")
+	formatName := fs.String("format", "csv", "csv, jsonl or parquet")
+	partitionName := fs.String("partition", "symbol", "a file per symbol or per year")
+	datasets := fs.String("datasets", "stocks,prices,dividends,splits,financials", "comma separated datasets")
+	from := fs.String("from", "", "first date exported, yyyy-mm-dd")
+	to := fs.String("to", "", "last date exported, yyyy-mm-dd")
+	adjusted := fs.String("adjusted", "iex", "price adjustment: raw, iex, split or total")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	partition, err := export.ParsePartition(*partitionName)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	result, err := export.Run(ctx, myapp.DB, export.Options{
+		Dir:       *dir,
+		Format:    format,
+		Partition: partition,
+		Datasets:  strings.Split(*datasets, ","),
+		Symbols:   fs.Args(),
+		From:      *from,
+		To:        *to,
+		Adjusted:  *adjusted,
+	})
+	if result != nil {
+		for _, d := range export.AllDatasets {
+			if n, ok := result.Rows[d]; ok {
+				logger.Info("exported", "dataset", d, "rows", n)
+			}
+		}
+		logger.Info("export complete", "dir", *dir, "files", len(result.Files))
+	}
+	return err
+}
+
+// deliver posts queued webhook deliveries until SIGTERM or SIGINT
+// usage: deliver [-name host-pid] [-lease 1m] [-backoff 30s] [-idle 5s] [-timeout 10s] [-once]
+func deliver(myapp *app.Application, args []string) error {
+	host, _ := os.Hostname()
+	var w app.Worker
+	fs := flag.NewFlagSet("deliver", flag.ContinueOnError)
+	fs.StringVar(&w.Name, "name", fmt.Sprintf("%s-%d", host, os.Getpid()), "worker name recorded on claimed deliveries")
+	fs.DurationVar(&w.Lease, "lease", time.Minute, "how long a claimed delivery is held")
+	fs.DurationVar(&w.Backoff, "backoff", 30*time.Second, "delay before the first retry, doubled per attempt")
+	fs.DurationVar(&w.Idle, "idle", 5*time.Second, "pause between polls of an empty queue")
+	fs.BoolVar(&w.Once, "once", false, "exit when nothing is due")
+	timeout := fs.Duration("timeout", 10*time.Second, "longest wait for a consumer to answer")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx, cancel := signalContext()
+	defer cancel()
+	myapp.SetContext(ctx)
+	return myapp.DeliverWebhooks(w, *timeout)
+}
+
+// webhooks manages webhook subscriptions and their delivery log
+// usage: webhook add [-events dividend,split] [-secret s] url | webhook list | webhook remove id |
+// webhook log [-n 50] | webhook retry delivery-id... | webhook receive [-listen :8081] -secret s
+func webhooks(myapp *app.Application, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("webhook: missing add, list, remove, log, retry or receive")
+	}
+	sub, args := args[0], args[1:]
+	switch sub {
+	case "add":
+		fs := flag.NewFlagSet("webhook add", flag.ContinueOnError)
+		events := fs.String("events", "", "comma separated event kinds to deliver; empty delivers all")
+		secret := fs.String("secret", "", "signing secret; generated when empty")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("webhook add: want a single url")
+		}
+		var kinds []string
+		for _, k := range strings.Split(*events, ",") {
+			if k != "" {
+				kinds = append(kinds, k)
+			}
+		}
+		if *secret == "" {
+			s, err := webhook.GenerateSecret()
+			if err != nil {
+				return err
+			}
+			*secret = s
+		}
+		id, err := myapp.DB.CreateWebhook(fs.Arg(0), kinds, *secret)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("webhook %d secret %s\n", id, *secret)
+		return nil
+	case "list":
+		hooks, err := myapp.DB.Webhooks()
+		if err != nil {
+			return err
+		}
+		for _, h := range hooks {
+			events := "all"
+			if len(h.Events) > 0 {
+				events = strings.Join(h.Events, ",")
+			}
+			status := "active"
+			if h.DisabledAt != nil {
+				status = "disabled " + h.DisabledAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%4d %-40s %-30s %s\n", h.ID, h.URL, events, status)
+		}
+		return nil
+	case "remove":
+		for _, arg := range args {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("bad webhook id %q", arg)
+			}
+			if err := myapp.DB.DisableWebhook(id); err == db.ErrNotFound {
+				return fmt.Errorf("no active webhook %d", id)
+			} else if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "log":
+		fs := flag.NewFlagSet("webhook log", flag.ContinueOnError)
+		n := fs.Int("n", 50, "attempts to show")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		log, err := myapp.DB.DeliveryLog(*n)
+		if err != nil {
+			return err
+		}
+		for _, a := range log {
+			status := "-"
+			if a.StatusCode != nil {
+				status = strconv.Itoa(*a.StatusCode)
+			}
+			fmt.Printf("%s delivery %d webhook %d %-17s attempt %d: %s in %s %s\n",
+				a.AttemptedAt.Format(time.RFC3339), a.DeliveryID, a.WebhookID, a.Kind, a.Attempt, status, a.Duration, a.Error)
+		}
+		return nil
+	case "retry":
+		for _, arg := range args {
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				return fmt.Errorf("bad delivery id %q", arg)
+			}
+			if err := myapp.DB.Redeliver(id); err == db.ErrNotFound {
+				return fmt.Errorf("no dead delivery %d", id)
+			} else if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "receive":
+		// a local consumer printing verified deliveries, for trying out subscriptions
+		fs := flag.NewFlagSet("webhook receive", flag.ContinueOnError)
+		addr := fs.String("listen", ":8081", "address to receive deliveries on")
+		secret := fs.String("secret", "", "signing secret of the subscription")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if *secret == "" {
+			return fmt.Errorf("webhook receive: -secret is required")
+		}
+		ctx, cancel := signalContext()
+		defer cancel()
+		return listen(ctx, *addr, &webhook.Receiver{
+			Secret:    *secret,
+			Tolerance: 5 * time.Minute,
+			Handle: func(event, delivery string, body []byte) error {
+				fmt.Printf("delivery %s %s %s\n", delivery, event, body)
+				return nil
+			},
 		})
 	}
-	return allPrices, nil
+	return fmt.Errorf("webhook: unknown subcommand %q", sub)
+}
+
+// jobs prints queue counts and the dead-lettered jobs
+func jobs(myapp *app.Application) error {
+	counts, err := myapp.DB.JobCounts()
+	if err != nil {
+		return err
+	}
+	for _, status := range []string{db.JobQueued, db.JobRunning, db.JobDone, db.JobDead} {
+		fmt.Printf("%-8s %d\n", status, counts[status])
+	}
+	dead, err := myapp.DB.DeadJobs()
+	if err != nil {
+		return err
+	}
+	for _, j := range dead {
+		fmt.Printf("%6d %-6s %-10s %-4s after %d attempts: %s\n", j.ID, j.Symbol, j.Kind, j.Range, j.Attempts, j.LastError)
+	}
+	return nil
+}
+
+// slaFlags registers the freshness thresholds on fs
+func slaFlags(fs *flag.FlagSet) *app.SLA {
+	sla := app.DefaultSLA
+	fs.IntVar(&sla.PriceLag, "price-lag", sla.PriceLag, "sessions the latest bar may trail the last closed session")
+	fs.DurationVar(&sla.DividendAge, "dividend-age", sla.DividendAge, "longest time since dividends were fetched")
+	fs.DurationVar(&sla.ReportAge, "report-age", sla.ReportAge, "oldest latest income statement")
+	fs.IntVar(&sla.MaxStale, "max-stale", sla.MaxStale, "stale symbols tolerated before the SLA is breached")
+	return &sla
+}
+
+// freshness reports stale symbols and fails when the SLA is breached
+// usage: freshness [-price-lag 1] [-dividend-age 192h] [-report-age 3240h] [-max-stale 0]
+func freshness(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("freshness", flag.ContinueOnError)
+	sla := slaFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	report, err := myapp.Freshness(*sla)
+	if err != nil {
+		return err
+	}
+	for _, s := range report.Stale {
+		fmt.Printf("%-6s %s\n", s.Symbol, strings.Join(s.Reasons, "; "))
+	}
+	fmt.Printf("session %s: %d of %d symbols stale\n", report.Session, len(report.Stale), report.Checked)
+	if report.Breached {
+		return fmt.Errorf("freshness SLA breached: %d stale symbols, %d tolerated", len(report.Stale), sla.MaxStale)
+	}
+	return nil
 }
 
-func insertPriceUpload(filename string) error {
-	conn, err := db.CreateConn(environment.DbURL)
+// financials refreshes quarterly statements
+// usage: financials [-quarters 4] [symbol...]
+func financials(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("financials", flag.ContinueOnError)
+	quarters := fs.Int("quarters", 4, "fiscal quarters to fetch per statement")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	symbols, err := symbolsOrAll(myapp, fs.Args())
 	if err != nil {
-		return fmt.Errorf("connecting to db: %w", err)
+		return err
+	}
+	return myapp.Financials(symbols, *quarters)
+}
+
+// planCost prints the credits and time a command would spend without calling iex
+// usage: plan [-v] [-call-cost 20] [-quarters 4] seed|update|backfill|financials [symbol...]
+func planCost(myapp *app.Application, args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "list every planned call")
+	callCost := fs.Int("call-cost", 20, "credit equivalent charged per request when choosing backfill calls")
+	quarters := fs.Int("quarters", 4, "fiscal quarters to fetch per statement")
+	if err := fs.Parse(args); err != nil {
+		return err
 	}
-	defer conn.Close()
-	this, err := customupload(filename)
+	if fs.NArg() < 1 {
+		return fmt.Errorf("plan: missing command")
+	}
+	symbols, err := symbolsOrAll(myapp, fs.Args()[1:])
 	if err != nil {
-		return fmt.Errorf("loading file: %w", err)
+		return err
+	}
+	var plan app.CostPlan
+	switch cmd := fs.Arg(0); cmd {
+	case "seed":
+		plan = myapp.PlanSeed(symbols)
+	case "update":
+		if plan, err = myapp.PlanUpdate(symbols); err != nil {
+			return err
+		}
+	case "backfill":
+		plans, err := myapp.PlanBackfill(symbols, *callCost)
+		if err != nil {
+			return err
+		}
+		plan = myapp.PlanBackfillCost(plans)
+	case "financials":
+		plan = myapp.PlanFinancials(symbols, *quarters)
+	default:
+		return fmt.Errorf("plan: cannot plan %q", cmd)
 	}
-	for _, entry := range this {
-		conn.InsertPriceHistory(&entry)
+	if *verbose {
+		for _, c := range plan.Calls {
+			fmt.Printf("  %s (%d credits)\n", c.Endpoint, c.Credits)
+		}
 	}
+	fmt.Printf("%s: %d symbols, %d calls, %d credits, ~%s at %s per call\n",
+		plan.Command, len(symbols), len(plan.Calls), plan.Credits(), plan.Wall(), plan.Interval)
 	return nil
 }
+
+// symbolsOrAll returns args, or every active symbol when args is empty
+func symbolsOrAll(myapp *app.Application, args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	symbols, err := myapp.DB.Symbols()
+	if err != nil {
+		return nil, fmt.Errorf("getting symbols: %w", err)
+	}
+	return symbols, nil
+}
+
+func restOfStocks(element string, data []string) []string {
+	var x int
+	for k, v := range data {
+		if v == element {
+			x = k
+		}
+	}
+	return data[x+1:]
+}
+
+func readtesterfile() ([]iex.Dividend, error) {
+	f, err := os.Open("tester.json")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	bytedata, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	var ds []iex.Dividend
+	if err := json.Unmarshal(bytedata, &ds); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
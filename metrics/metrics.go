@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Registry holds every defcor metric
+var Registry = prometheus.NewRegistry()
+
+// iex api usage
+var (
+	Requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "defcor_iex_requests_total",
+		Help: "IEX requests by endpoint kind and http status (error when no response).",
+	}, []string{"endpoint", "status"})
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "defcor_iex_request_duration_seconds",
+		Help:    "IEX request latency by endpoint kind.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+	LimiterWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "defcor_iex_limiter_wait_seconds",
+		Help:    "Time requests spent waiting on the rate limiter.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+	})
+	Credits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "defcor_iex_credits_total",
+		Help: "IEX credits charged, as reported by the iexcloud-messages-used header.",
+	}, []string{"endpoint"})
+)
+
+// database writes
+var (
+	Rows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "defcor_db_rows_total",
+		Help: "Rows written by table and operation (inserted, updated or deleted).",
+	}, []string{"table", "op"})
+	TxDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "defcor_db_transaction_duration_seconds",
+		Help:    "Duration of write transactions by table, committed or not.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"table"})
+)
+
+// data freshness
+var StaleSymbols = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "defcor_stale_symbols",
+	Help: "Symbols breaking the freshness SLA at the last check.",
+})
+
+func init() {
+	Registry.MustRegister(
+		Requests, RequestDuration, LimiterWait, Credits,
+		Rows, TxDuration,
+		StaleSymbols,
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the registry in the prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Push sends the registry to a pushgateway at url under job, grouped by
+// command so different one-shot commands don't overwrite each other
+func Push(url, job, command string) error {
+	return push.New(url, job).Gatherer(Registry).Grouping("command", command).Push()
+}
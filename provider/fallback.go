@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"defcor/iex"
+	"defcor/logging"
+	"fmt"
+	"strings"
+)
+
+// DataType names a kind of data a provider serves
+type DataType string
+
+// Data types used in a Priority
+const (
+	Stocks    DataType = "stocks"
+	Prices    DataType = "prices"
+	Dividends DataType = "dividends"
+	Splits    DataType = "splits"
+)
+
+// Registry names the configured providers
+type Registry map[string]Provider
+
+// Priority lists source names in the order they are tried per data type
+type Priority map[DataType][]string
+
+// ParsePriority reads "stocks=iex;dividends=iex" into a Priority
+func ParsePriority(s string) (Priority, error) {
+	p := make(Priority)
+	if s == "" {
+		return p, nil
+	}
+	for _, entry := range strings.Split(s, ";") {
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("bad source priority %q", entry)
+		}
+		var names []string
+		for _, n := range strings.Split(kv[1], ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				names = append(names, n)
+			}
+		}
+		p[DataType(strings.TrimSpace(kv[0]))] = names
+	}
+	return p, nil
+}
+
+// Fallback tries each source in priority order until one succeeds
+type Fallback struct {
+	sources  Registry
+	priority Priority
+	primary  string
+	log      *logging.Logger
+}
+
+// NewFallback combines sources; data types without a priority use primary alone
+func NewFallback(sources Registry, priority Priority, primary string) (*Fallback, error) {
+	if _, ok := sources[primary]; !ok {
+		return nil, fmt.Errorf("unknown primary source %q", primary)
+	}
+	for dt, names := range priority {
+		switch dt {
+		case Stocks, Prices, Dividends, Splits:
+		default:
+			return nil, fmt.Errorf("unknown data type %q", dt)
+		}
+		for _, n := range names {
+			if _, ok := sources[n]; !ok {
+				return nil, fmt.Errorf("%s: unknown source %q", dt, n)
+			}
+		}
+	}
+	return &Fallback{sources: sources, priority: priority, primary: primary, log: logging.Default()}, nil
+}
+
+// SetLogger replaces the logger source failures are recorded with
+func (f *Fallback) SetLogger(l *logging.Logger) {
+	f.log = l
+}
+
+// Source returns the named provider
+func (f *Fallback) Source(name string) (Provider, bool) {
+	p, ok := f.sources[name]
+	return p, ok
+}
+
+func (f *Fallback) order(dt DataType) []string {
+	if names := f.priority[dt]; len(names) > 0 {
+		return names
+	}
+	return []string{f.primary}
+}
+
+// try calls fn against every source for dt until one succeeds
+func (f *Fallback) try(dt DataType, symbol string, fn func(Provider) error) error {
+	var err error
+	for _, name := range f.order(dt) {
+		if err = fn(f.sources[name]); err == nil {
+			return nil
+		}
+		f.log.Warn("source failed", "symbol", symbol, "data", string(dt), "source", name, "err", err)
+	}
+	return fmt.Errorf("%s %s: all sources failed: %w", symbol, dt, err)
+}
+
+// Stocks satisfies Provider
+func (f *Fallback) Stocks(ctx context.Context) ([]iex.Stock, error) {
+	var stks []iex.Stock
+	err := f.try(Stocks, "*", func(p Provider) (err error) {
+		stks, err = p.Stocks(ctx)
+		return err
+	})
+	return stks, err
+}
+
+// Prices satisfies Provider
+func (f *Fallback) Prices(ctx context.Context, symbol string) (*iex.PriceHistory, error) {
+	var ph *iex.PriceHistory
+	err := f.try(Prices, symbol, func(p Provider) (err error) {
+		ph, err = p.Prices(ctx, symbol)
+		return err
+	})
+	return ph, err
+}
+
+// Dividends satisfies Provider
+func (f *Fallback) Dividends(ctx context.Context, symbol string) (*iex.DividendHistory, error) {
+	var dh *iex.DividendHistory
+	err := f.try(Dividends, symbol, func(p Provider) (err error) {
+		dh, err = p.Dividends(ctx, symbol)
+		return err
+	})
+	return dh, err
+}
+
+// Splits satisfies Provider
+func (f *Fallback) Splits(ctx context.Context, symbol string) (*iex.SplitHistory, error) {
+	var sh *iex.SplitHistory
+	err := f.try(Splits, symbol, func(p Provider) (err error) {
+		sh, err = p.Splits(ctx, symbol)
+		return err
+	})
+	return sh, err
+}
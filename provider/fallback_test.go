@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"defcor/iex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// down stands in for a source that fails every call
+type down struct{ calls int }
+
+func (d *down) Stocks(ctx context.Context) ([]iex.Stock, error) {
+	d.calls++
+	return nil, errors.New("down")
+}
+
+func (d *down) Prices(ctx context.Context, symbol string) (*iex.PriceHistory, error) {
+	d.calls++
+	return nil, errors.New("down")
+}
+
+func (d *down) Dividends(ctx context.Context, symbol string) (*iex.DividendHistory, error) {
+	d.calls++
+	return nil, errors.New("down")
+}
+
+func (d *down) Splits(ctx context.Context, symbol string) (*iex.SplitHistory, error) {
+	d.calls++
+	return nil, errors.New("down")
+}
+
+func TestFallbackToStooqPrices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("s") != "aapl.us" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "Date,Open,High,Low,Close,Volume\n2020-01-02,5,5.5,4.5,5.25,200\n")
+	}))
+	defer srv.Close()
+	iexSource := &down{}
+	f, err := NewFallback(Registry{"iex": iexSource, "stooq": NewStooq(srv.URL, "max", nil)},
+		Priority{Prices: {"iex", "stooq"}}, "iex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ph, err := f.Prices(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := iex.Prices{Date: "2020-01-02", Aopen: 5, Ahigh: 5.5, Alow: 4.5, Aclose: 5.25, Avolume: 200, AdjustedOnly: true}
+	if iexSource.calls != 1 || ph.Symbol != "AAPL" || len(ph.Prices) != 1 || ph.Prices[0] != want {
+		t.Errorf("after %d iex calls got %+v, want %+v", iexSource.calls, ph, want)
+	}
+	// stooq is not in the dividends priority, so iex alone answers
+	if _, err := f.Dividends(context.Background(), "AAPL"); err == nil || iexSource.calls != 2 {
+		t.Errorf("dividends: got %v after %d iex calls", err, iexSource.calls)
+	}
+	if _, err := f.Prices(context.Background(), "IBM"); !errors.Is(err, ErrNoData) {
+		t.Errorf("prices no source has: got %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestNewFallbackRejects(t *testing.T) {
+	sources := Registry{"iex": &down{}}
+	for name, p := range map[string]Priority{
+		"unknown source":    {Prices: {"iex", "yahoo"}},
+		"unknown data type": {"quotes": {"iex"}},
+	} {
+		if _, err := NewFallback(sources, p, "iex"); err == nil {
+			t.Errorf("%s: NewFallback succeeded", name)
+		}
+	}
+	if _, err := NewFallback(sources, nil, "stooq"); err == nil {
+		t.Error("unknown primary: NewFallback succeeded")
+	}
+}
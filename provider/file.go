@@ -0,0 +1,340 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"defcor/iex"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Format is the encoding of an exported file
+type Format string
+
+// Supported file formats
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+)
+
+// Mapping renames fields to the column names used in a file.
+// Fields are the iex json names, e.g. date, uClose, close, exDate, toFactor.
+type Mapping map[string]string
+
+// ParseMapping reads field=column pairs separated by commas
+func ParseMapping(s string) (Mapping, error) {
+	m := make(Mapping)
+	if s == "" {
+		return m, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("bad column mapping %q", pair)
+		}
+		m[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return m, nil
+}
+
+func (m Mapping) column(field string) string {
+	if col, ok := m[field]; ok {
+		return col
+	}
+	return field
+}
+
+// record is a single row keyed by column name
+type record map[string]string
+
+// numeric fields are passed to the iex types as json numbers, the rest as strings
+var (
+	floatFields = map[string]bool{
+		"uOpen": true, "uHigh": true, "uLow": true, "uClose": true,
+		"open": true, "high": true, "low": true, "close": true,
+		"amount": true, "toFactor": true, "fromFactor": true,
+	}
+	intFields = map[string]bool{"uVolume": true, "volume": true}
+	// unadjusted columns fall back to their adjusted counterparts
+	unadjusted = map[string]string{
+		"uOpen": "open", "uHigh": "high", "uLow": "low", "uClose": "close", "uVolume": "volume",
+	}
+)
+
+var (
+	stockFields    = []string{"symbol", "name", "type", "iexId", "region", "currency", "figi", "cik"}
+	priceFields    = []string{"date", "uOpen", "uHigh", "uLow", "uClose", "uVolume", "open", "high", "low", "close", "volume"}
+	dividendFields = []string{"declaredDate", "exDate", "recordDate", "paymentDate", "amount", "flag", "currency", "frequency"}
+	splitFields    = []string{"declaredDate", "exDate", "toFactor", "fromFactor"}
+)
+
+// File reads vendor exports or historical dumps from a directory holding
+// stocks, prices, dividends and splits files (e.g. prices.csv). CSV files
+// carry a symbol column; JSON files are either an array of rows with a symbol
+// field or an object of rows keyed by symbol.
+type File struct {
+	Dir     string
+	Format  Format
+	Columns Mapping
+
+	mu     sync.Mutex
+	tables map[string]map[string][]record
+}
+
+// NewFile creates a file provider rooted at dir
+func NewFile(dir string, format Format, columns Mapping) *File {
+	if columns == nil {
+		columns = make(Mapping)
+	}
+	return &File{
+		Dir:     dir,
+		Format:  format,
+		Columns: columns,
+		tables:  make(map[string]map[string][]record),
+	}
+}
+
+// Stocks returns every stock in the stocks file; a directory without one
+// has no stock master and yields none
+func (f *File) Stocks(ctx context.Context) ([]iex.Stock, error) {
+	table, err := f.table("stocks")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stks []iex.Stock
+	for _, rows := range table {
+		for _, r := range rows {
+			var s iex.Stock
+			if err := f.decode(r, stockFields, &s); err != nil {
+				return nil, err
+			}
+			stks = append(stks, s)
+		}
+	}
+	return stks, nil
+}
+
+// Symbols lists the symbols present in the prices file
+func (f *File) Symbols() ([]string, error) {
+	table, err := f.table("prices")
+	if err != nil {
+		return nil, err
+	}
+	syms := make([]string, 0, len(table))
+	for s := range table {
+		syms = append(syms, s)
+	}
+	return syms, nil
+}
+
+// Prices returns the price rows for symbol
+func (f *File) Prices(ctx context.Context, symbol string) (*iex.PriceHistory, error) {
+	rows, err := f.rows("prices", symbol)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s prices: %w", symbol, ErrNoData)
+	}
+	ph := iex.PriceHistory{Symbol: symbol, Prices: make([]iex.Prices, len(rows))}
+	for i, r := range rows {
+		if err := f.decode(r, priceFields, &ph.Prices[i]); err != nil {
+			return nil, fmt.Errorf("%s prices: %w", symbol, err)
+		}
+	}
+	return &ph, nil
+}
+
+// Dividends returns the dividend rows for symbol
+func (f *File) Dividends(ctx context.Context, symbol string) (*iex.DividendHistory, error) {
+	rows, err := f.rows("dividends", symbol)
+	if err != nil {
+		return nil, err
+	}
+	dh := iex.DividendHistory{Symbol: symbol, Dividends: make([]iex.Dividend, len(rows))}
+	for i, r := range rows {
+		if err := f.decode(r, dividendFields, &dh.Dividends[i]); err != nil {
+			return nil, fmt.Errorf("%s dividends: %w", symbol, err)
+		}
+	}
+	return &dh, nil
+}
+
+// Splits returns the split rows for symbol
+func (f *File) Splits(ctx context.Context, symbol string) (*iex.SplitHistory, error) {
+	rows, err := f.rows("splits", symbol)
+	if err != nil {
+		return nil, err
+	}
+	sh := iex.SplitHistory{Symbol: symbol, Splits: make([]iex.Split, len(rows))}
+	for i, r := range rows {
+		if err := f.decode(r, splitFields, &sh.Splits[i]); err != nil {
+			return nil, fmt.Errorf("%s splits: %w", symbol, err)
+		}
+	}
+	return &sh, nil
+}
+
+// rows returns the rows of a table for symbol; a missing file means no rows
+func (f *File) rows(name, symbol string) ([]record, error) {
+	table, err := f.table(name)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return table[symbol], nil
+}
+
+// table lazily loads and groups a file by symbol
+func (f *File) table(name string) (map[string][]record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if t, ok := f.tables[name]; ok {
+		return t, nil
+	}
+	file, err := os.Open(filepath.Join(f.Dir, name+"."+string(f.Format)))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rows []record
+	switch f.Format {
+	case CSV:
+		rows, err = readCSV(file)
+	case JSON:
+		rows, err = readJSON(file, f.Columns.column("symbol"))
+	default:
+		err = fmt.Errorf("unknown file format %q", f.Format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	t := make(map[string][]record)
+	symcol := f.Columns.column("symbol")
+	for _, r := range rows {
+		t[r[symcol]] = append(t[r[symcol]], r)
+	}
+	f.tables[name] = t
+	return t, nil
+}
+
+// decode maps a row onto the iex json names and unmarshals it into v
+func (f *File) decode(r record, fields []string, v interface{}) error {
+	obj := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		val, ok := r[f.Columns.column(field)]
+		if !ok {
+			if adj, isU := unadjusted[field]; isU {
+				val, ok = r[f.Columns.column(adj)]
+			}
+		}
+		if !ok {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		switch {
+		case intFields[field]:
+			if val == "" {
+				continue
+			}
+			x, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+			obj[field] = int64(x)
+		case floatFields[field]:
+			if val == "" {
+				continue
+			}
+			obj[field] = json.Number(val)
+		default:
+			obj[field] = val
+		}
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func readCSV(r io.Reader) ([]record, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	var rows []record
+	for {
+		line, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rec := make(record, len(header))
+		for i, col := range header {
+			rec[col] = line[i]
+		}
+		rows = append(rows, rec)
+	}
+	return rows, nil
+}
+
+// readJSON accepts either [{...}] or {"SYM": [{...}]} and stores the key in symcol
+func readJSON(r io.Reader, symcol string) ([]record, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	var objs []map[string]interface{}
+	if bytes.HasPrefix(data, []byte("{")) {
+		var keyed map[string][]map[string]interface{}
+		if err := unmarshalNumbers(data, &keyed); err != nil {
+			return nil, err
+		}
+		for sym, entries := range keyed {
+			for _, o := range entries {
+				o[symcol] = sym
+				objs = append(objs, o)
+			}
+		}
+	} else if err := unmarshalNumbers(data, &objs); err != nil {
+		return nil, err
+	}
+	rows := make([]record, len(objs))
+	for i, o := range objs {
+		rec := make(record, len(o))
+		for k, v := range o {
+			if v != nil {
+				rec[k] = fmt.Sprint(v)
+			}
+		}
+		rows[i] = rec
+	}
+	return rows, nil
+}
+
+func unmarshalNumbers(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
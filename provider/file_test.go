@@ -0,0 +1,179 @@
+package provider
+
+import (
+	"context"
+	"defcor/iex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// dump writes files named like prices.csv into a temporary directory
+func dump(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFilePrices(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		columns Mapping
+		file    string
+		want    []iex.Prices
+		err     bool
+	}{
+		{
+			name:   "iex column names",
+			format: CSV,
+			file: "symbol,date,uOpen,uHigh,uLow,uClose,uVolume,open,high,low,close,volume\n" +
+				"AAPL,2020-01-02,10,11,9,10.5,100,5,5.5,4.5,5.25,200\n" +
+				"IBM,2020-01-02,1,1,1,1,1,1,1,1,1,1\n",
+			want: []iex.Prices{{Date: "2020-01-02", Uopen: 10, Uhigh: 11, Ulow: 9, Uclose: 10.5, Uvolume: 100,
+				Aopen: 5, Ahigh: 5.5, Alow: 4.5, Aclose: 5.25, Avolume: 200}},
+		},
+		{
+			name:    "mapped vendor headers",
+			format:  CSV,
+			columns: Mapping{"symbol": "Ticker", "date": "Date", "close": "Adj Close", "uClose": "Close"},
+			file:    "Ticker,Date,Close,Adj Close\nAAPL,2020-01-02,10,5\n",
+			want:    []iex.Prices{{Date: "2020-01-02", Uclose: 10, Aclose: 5}},
+		},
+		{
+			name:   "missing unadjusted columns fall back to adjusted ones",
+			format: CSV,
+			file:   "symbol,date,close,volume\nAAPL,2020-01-02,5,200\n",
+			want:   []iex.Prices{{Date: "2020-01-02", Uclose: 5, Uvolume: 200, Aclose: 5, Avolume: 200}},
+		},
+		{
+			name:   "empty numbers are left zero",
+			format: CSV,
+			file:   "symbol,date,close,volume\nAAPL,2020-01-02,,\n",
+			want:   []iex.Prices{{Date: "2020-01-02"}},
+		},
+		{
+			name:   "bad number",
+			format: CSV,
+			file:   "symbol,date,close\nAAPL,2020-01-02,abc\n",
+			err:    true,
+		},
+		{
+			name:   "bad volume",
+			format: CSV,
+			file:   "symbol,date,close,volume\nAAPL,2020-01-02,5,lots\n",
+			err:    true,
+		},
+		{
+			name:   "ragged row",
+			format: CSV,
+			file:   "symbol,date,close\nAAPL,2020-01-02\n",
+			err:    true,
+		},
+		{
+			name:   "json array",
+			format: JSON,
+			file:   `[{"symbol":"AAPL","date":"2020-01-02","close":5.25,"volume":200},{"symbol":"IBM","date":"2020-01-02","close":1}]`,
+			want:   []iex.Prices{{Date: "2020-01-02", Uclose: 5.25, Uvolume: 200, Aclose: 5.25, Avolume: 200}},
+		},
+		{
+			name:   "json keyed by symbol",
+			format: JSON,
+			file:   `{"AAPL":[{"date":"2020-01-02","uClose":10,"close":5,"open":null}]}`,
+			want:   []iex.Prices{{Date: "2020-01-02", Uclose: 10, Aclose: 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := dump(t, map[string]string{"prices." + string(tt.format): tt.file})
+			ph, err := NewFile(dir, tt.format, tt.columns).Prices(context.Background(), "AAPL")
+			if tt.err {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", ph)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ph.Prices) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", ph.Prices, tt.want)
+			}
+			for i, p := range ph.Prices {
+				if p != tt.want[i] {
+					t.Errorf("bar %d: got %+v, want %+v", i, p, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFileMissingData(t *testing.T) {
+	dir := dump(t, map[string]string{"prices.csv": "symbol,date,close\nIBM,2020-01-02,1\n"})
+	f := NewFile(dir, CSV, nil)
+	if _, err := f.Prices(context.Background(), "AAPL"); !errors.Is(err, ErrNoData) {
+		t.Errorf("prices of an absent symbol: got %v, want %v", err, ErrNoData)
+	}
+	// files that are not there hold no rows rather than failing the load
+	if dh, err := f.Dividends(context.Background(), "IBM"); err != nil || len(dh.Dividends) != 0 {
+		t.Errorf("dividends without a file: got %+v, %v", dh, err)
+	}
+	if stks, err := f.Stocks(context.Background()); err != nil || stks != nil {
+		t.Errorf("stocks without a file: got %+v, %v", stks, err)
+	}
+}
+
+func TestFileStocksAndActions(t *testing.T) {
+	dir := dump(t, map[string]string{
+		"stocks.csv":    "symbol,name,type,iexId,region,currency,figi,cik\nAAPL,Apple Inc,cs,IEX_A,US,USD,BBG000B9XRY4,320193\n",
+		"dividends.csv": "symbol,exDate,amount,flag\nAAPL,2020-02-07,0.77,Cash\nAAPL,2020-05-08,,Cash\n",
+		"splits.csv":    "symbol,exDate,toFactor,fromFactor\nAAPL,2020-08-31,4,1\n",
+	})
+	f := NewFile(dir, CSV, nil)
+	stks, err := f.Stocks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := iex.Stock{Symbol: "AAPL", Name: "Apple Inc", Type: "cs", IexID: "IEX_A", Region: "US",
+		Curr: "USD", Figi: "BBG000B9XRY4", Cik: 320193}
+	if len(stks) != 1 || stks[0] != want {
+		t.Errorf("stocks: got %+v, want %+v", stks, want)
+	}
+	dh, err := f.Dividends(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dh.Dividends) != 2 || dh.Dividends[0].Amount.Float64 != 0.77 || !dh.Dividends[0].Amount.Valid ||
+		dh.Dividends[1].Amount.Valid || dh.Dividends[1].ExDate != "2020-05-08" {
+		t.Errorf("dividends: got %+v", dh.Dividends)
+	}
+	sh, err := f.Splits(context.Background(), "AAPL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sh.Splits) != 1 || sh.Splits[0].ToFactor != 4 || sh.Splits[0].FromFactor != 1 {
+		t.Errorf("splits: got %+v", sh.Splits)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	m, err := ParseMapping("close=Adj Close, date=Date")
+	if err != nil || m["close"] != "Adj Close" || m["date"] != "Date" {
+		t.Errorf("got %v, %v", m, err)
+	}
+	for _, bad := range []string{"close", "=Date", "close="} {
+		if _, err := ParseMapping(bad); err == nil {
+			t.Errorf("ParseMapping(%q) succeeded", bad)
+		}
+	}
+}
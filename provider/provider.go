@@ -0,0 +1,24 @@
+package provider
+
+import (
+	"context"
+	"defcor/iex"
+	"errors"
+)
+
+var (
+	// ErrNoData signals that a provider has nothing for the requested symbol
+	ErrNoData = errors.New("provider: no data")
+	// ErrNotSupported signals that a provider does not serve a data type
+	ErrNotSupported = errors.New("provider: not supported")
+)
+
+// Provider supplies the security master and per symbol market history
+type Provider interface {
+	Stocks(ctx context.Context) ([]iex.Stock, error)
+	Prices(ctx context.Context, symbol string) (*iex.PriceHistory, error)
+	Dividends(ctx context.Context, symbol string) (*iex.DividendHistory, error)
+	Splits(ctx context.Context, symbol string) (*iex.SplitHistory, error)
+}
+
+var _ Provider = (*iex.APIConnection)(nil)
@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"defcor/iex"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stooq downloads daily bars in the Stooq/Yahoo style csv layout
+// (Date,Open,High,Low,Close,Volume) from a configurable base url.
+// The vendor publishes a single adjusted series, so only the adjusted
+// columns are filled and every bar is marked AdjustedOnly.
+type Stooq struct {
+	BaseURL string
+	// Suffix is appended to lower cased symbols, e.g. ".us"
+	Suffix string
+	// Symbols overrides the vendor ticker for individual symbols
+	Symbols map[string]string
+	// Lookback is the iex chart range downloads are limited to; max or
+	// empty fetches the full history
+	Lookback string
+	client   *http.Client
+}
+
+// NewStooq creates a Stooq adapter for baseURL, e.g. https://stooq.com/q/d/l/
+func NewStooq(baseURL, lookback string, symbols map[string]string) *Stooq {
+	if symbols == nil {
+		symbols = make(map[string]string)
+	}
+	return &Stooq{
+		BaseURL:  baseURL,
+		Suffix:   ".us",
+		Symbols:  symbols,
+		Lookback: lookback,
+		client:   &http.Client{},
+	}
+}
+
+// ParseTickers reads "BRK.B=brk-b.us,BF.B=bf-b.us" into ticker overrides
+func ParseTickers(s string) (map[string]string, error) {
+	tickers := make(map[string]string)
+	if s == "" {
+		return tickers, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" || strings.TrimSpace(kv[1]) == "" {
+			return nil, fmt.Errorf("bad ticker override %q", pair)
+		}
+		tickers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return tickers, nil
+}
+
+// Ticker maps an iex symbol onto the vendor ticker
+func (s *Stooq) Ticker(symbol string) string {
+	if t, ok := s.Symbols[symbol]; ok {
+		return t
+	}
+	return strings.ToLower(strings.ReplaceAll(symbol, ".", "-")) + s.Suffix
+}
+
+// Stocks is not served by Stooq
+func (s *Stooq) Stocks(ctx context.Context) ([]iex.Stock, error) {
+	return nil, fmt.Errorf("stooq stocks: %w", ErrNotSupported)
+}
+
+// Dividends is not served by Stooq
+func (s *Stooq) Dividends(ctx context.Context, symbol string) (*iex.DividendHistory, error) {
+	return nil, fmt.Errorf("stooq dividends: %w", ErrNotSupported)
+}
+
+// Splits is not served by Stooq
+func (s *Stooq) Splits(ctx context.Context, symbol string) (*iex.SplitHistory, error) {
+	return nil, fmt.Errorf("stooq splits: %w", ErrNotSupported)
+}
+
+// Prices downloads the daily bars for symbol
+func (s *Stooq) Prices(ctx context.Context, symbol string) (*iex.PriceHistory, error) {
+	endpoint, err := url.Parse(s.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	qparams := endpoint.Query()
+	qparams.Set("s", s.Ticker(symbol))
+	qparams.Set("i", "d")
+	if s.Lookback != "" && s.Lookback != "max" {
+		// trading days to calendar days, with a week to spare for holidays
+		days := iex.RangeDays(s.Lookback)*7/5 + 7
+		qparams.Set("d1", time.Now().AddDate(0, 0, -days).Format("20060102"))
+		qparams.Set("d2", time.Now().Format("20060102"))
+	}
+	endpoint.RawQuery = qparams.Encode()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("stooq %s: %w", symbol, ErrNoData)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("stooq %s: %s", symbol, resp.Status)
+	}
+	prices, err := readBars(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("stooq %s: %w", symbol, err)
+	}
+	if len(prices) == 0 {
+		return nil, fmt.Errorf("stooq %s: %w", symbol, ErrNoData)
+	}
+	return &iex.PriceHistory{Symbol: symbol, Prices: prices}, nil
+}
+
+// readBars parses Date,Open,High,Low,Close,Volume rows; a body of
+// "No data" (how the vendor reports unknown tickers) yields no rows
+func readBars(r io.Reader) ([]iex.Prices, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	// every row must reach the last required column
+	var width int
+	for _, c := range []string{"date", "open", "high", "low", "close"} {
+		i, ok := cols[c]
+		if !ok {
+			return nil, nil
+		}
+		if i >= width {
+			width = i + 1
+		}
+	}
+	var prices []iex.Prices
+	for row := 2; ; row++ {
+		line, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(line) < width {
+			return nil, fmt.Errorf("line %d: %d fields, want at least %d", row, len(line), width)
+		}
+		var vals [4]float64
+		for i, c := range []string{"open", "high", "low", "close"} {
+			if vals[i], err = strconv.ParseFloat(line[cols[c]], 64); err != nil {
+				return nil, err
+			}
+		}
+		var volume int
+		if i, ok := cols["volume"]; ok && i < len(line) && line[i] != "" {
+			v, err := strconv.ParseFloat(line[i], 64)
+			if err != nil {
+				return nil, err
+			}
+			volume = int(v)
+		}
+		prices = append(prices, iex.Prices{
+			Date:         line[cols["date"]],
+			Aopen:        vals[0],
+			Ahigh:        vals[1],
+			Alow:         vals[2],
+			Aclose:       vals[3],
+			Avolume:      volume,
+			AdjustedOnly: true,
+		})
+	}
+	return prices, nil
+}
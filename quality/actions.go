@@ -0,0 +1,43 @@
+package quality
+
+import (
+	"defcor/calendar"
+	"defcor/iex"
+)
+
+// CheckDividends validates dividend records
+func CheckDividends(dh *iex.DividendHistory) []Issue {
+	ph := &iex.PriceHistory{Symbol: dh.Symbol}
+	var issues []Issue
+	for _, d := range dh.Dividends {
+		if _, err := calendar.ParseDate(d.ExDate); err != nil {
+			issues = append(issues, issue(ph, d.ExDate, "dividend", Error, 0, "unparseable exdate %q", d.ExDate))
+			continue
+		}
+		switch {
+		case !d.Amount.Valid:
+			issues = append(issues, issue(ph, d.ExDate, "dividend", Warning, 0, "missing amount"))
+		case d.Amount.Float64 <= 0:
+			issues = append(issues, issue(ph, d.ExDate, "dividend", Error, d.Amount.Float64,
+				"non positive amount %.4f", d.Amount.Float64))
+		}
+	}
+	return issues
+}
+
+// CheckSplits validates split records
+func CheckSplits(sh *iex.SplitHistory) []Issue {
+	ph := &iex.PriceHistory{Symbol: sh.Symbol}
+	var issues []Issue
+	for _, s := range sh.Splits {
+		if _, err := calendar.ParseDate(s.ExDate); err != nil {
+			issues = append(issues, issue(ph, s.ExDate, "split", Error, 0, "unparseable exdate %q", s.ExDate))
+			continue
+		}
+		if s.ToFactor <= 0 || s.FromFactor <= 0 {
+			issues = append(issues, issue(ph, s.ExDate, "split", Error, s.ToFactor,
+				"non positive factors %v/%v", s.ToFactor, s.FromFactor))
+		}
+	}
+	return issues
+}
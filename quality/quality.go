@@ -0,0 +1,115 @@
+package quality
+
+import (
+	"defcor/calendar"
+	"defcor/iex"
+	"fmt"
+	"strings"
+)
+
+// Severity grades an Issue
+type Severity string
+
+// Severities in increasing order of concern
+const (
+	Info    Severity = "info"
+	Warning Severity = "warning"
+	Error   Severity = "error"
+)
+
+// Issue is a single data quality finding on one bar
+type Issue struct {
+	Symbol   string
+	Date     string
+	Rule     string
+	Severity Severity
+	Detail   string
+	// Value is the measured magnitude, NaN when the finding has none
+	Value float64
+}
+
+// Rule checks the bars of one security; bars are ordered by date
+type Rule interface {
+	Name() string
+	Check(ph *iex.PriceHistory) []Issue
+}
+
+// Config holds the thresholds used by the price rules
+type Config struct {
+	// ExtremeReturn is the absolute daily adjusted return flagged as extreme
+	ExtremeReturn float64
+	// StaleRun is the number of identical consecutive closes flagged as stale
+	StaleRun int
+	// RatioTolerance is the relative spread allowed between the
+	// adjusted/unadjusted ratios of a bar's open, high, low and close
+	RatioTolerance float64
+	Calendar       *calendar.Calendar
+}
+
+// DefaultConfig returns the thresholds used when none are given
+func DefaultConfig() Config {
+	return Config{
+		ExtremeReturn:  0.5,
+		StaleRun:       5,
+		RatioTolerance: 0.01,
+		Calendar:       calendar.NYSE,
+	}
+}
+
+// Rules builds the named rules; an empty list selects every rule
+func Rules(cfg Config, names ...string) ([]Rule, error) {
+	all := []Rule{
+		ohlcRule{},
+		ohlcRule{adjusted: true},
+		nonNegativeRule{},
+		gapRule{cfg.Calendar},
+		staleRule{cfg.StaleRun},
+		extremeRule{cfg.ExtremeReturn},
+		ratioRule{cfg.RatioTolerance},
+	}
+	if len(names) == 0 {
+		return all, nil
+	}
+	byName := make(map[string]Rule, len(all))
+	for _, r := range all {
+		byName[r.Name()] = r
+	}
+	rules := make([]Rule, 0, len(names))
+	for _, n := range names {
+		r, ok := byName[strings.TrimSpace(n)]
+		if !ok {
+			return nil, fmt.Errorf("unknown rule %q", n)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// RuleNames lists the names of rules
+func RuleNames(rules []Rule) []string {
+	names := make([]string, len(rules))
+	for i, r := range rules {
+		names[i] = r.Name()
+	}
+	return names
+}
+
+// Check runs every rule over ph
+func Check(ph *iex.PriceHistory, rules []Rule) []Issue {
+	var issues []Issue
+	for _, r := range rules {
+		issues = append(issues, r.Check(ph)...)
+	}
+	return issues
+}
+
+func issue(ph *iex.PriceHistory, date, rule string, sev Severity, value float64, format string, args ...interface{}) Issue {
+	return Issue{
+		Symbol:   ph.Symbol,
+		Date:     date,
+		Rule:     rule,
+		Severity: sev,
+		Detail:   fmt.Sprintf(format, args...),
+		Value:    value,
+	}
+}
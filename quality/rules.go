@@ -0,0 +1,199 @@
+package quality
+
+import (
+	"defcor/calendar"
+	"defcor/iex"
+	"math"
+)
+
+// ohlcRule requires low <= open, close <= high for one price set. Each set is
+// its own rule so both can be recorded for the same bar.
+type ohlcRule struct {
+	adjusted bool
+}
+
+func (r ohlcRule) Name() string {
+	if r.adjusted {
+		return "ohlc_adjusted"
+	}
+	return "ohlc"
+}
+
+func (r ohlcRule) Check(ph *iex.PriceHistory) []Issue {
+	var issues []Issue
+	for _, p := range ph.Prices {
+		if p.AdjustedOnly && !r.adjusted {
+			continue
+		}
+		set, open, high, low, close := "unadjusted", p.Uopen, p.Uhigh, p.Ulow, p.Uclose
+		if r.adjusted {
+			set, open, high, low, close = "adjusted", p.Aopen, p.Ahigh, p.Alow, p.Aclose
+		}
+		switch {
+		case low > high:
+			issues = append(issues, issue(ph, p.Date, r.Name(), Error, low-high,
+				"%s low %.2f above high %.2f", set, low, high))
+		case open < low || open > high || close < low || close > high:
+			issues = append(issues, issue(ph, p.Date, r.Name(), Error, close,
+				"%s open %.2f or close %.2f outside [%.2f, %.2f]", set, open, close, low, high))
+		}
+	}
+	return issues
+}
+
+// nonNegativeRule rejects negative or zero prices and flags zero volume
+type nonNegativeRule struct{}
+
+func (nonNegativeRule) Name() string { return "nonnegative" }
+
+func (r nonNegativeRule) Check(ph *iex.PriceHistory) []Issue {
+	var issues []Issue
+	for _, p := range ph.Prices {
+		low := math.Min(math.Min(p.Uopen, p.Uhigh), math.Min(p.Ulow, p.Uclose))
+		alow := math.Min(math.Min(p.Aopen, p.Ahigh), math.Min(p.Alow, p.Aclose))
+		volume := p.Uvolume
+		// adjusted only bars have no unadjusted columns to check
+		if p.AdjustedOnly {
+			low, volume = alow, p.Avolume
+		}
+		switch {
+		case low <= 0 || alow <= 0:
+			issues = append(issues, issue(ph, p.Date, r.Name(), Error, math.Min(low, alow),
+				"non positive price"))
+		case p.Uvolume < 0 || p.Avolume < 0:
+			issues = append(issues, issue(ph, p.Date, r.Name(), Error, float64(p.Uvolume),
+				"negative volume"))
+		case volume == 0:
+			issues = append(issues, issue(ph, p.Date, r.Name(), Warning, 0, "zero volume"))
+		}
+	}
+	return issues
+}
+
+// gapRule compares the bars with the trading calendar
+type gapRule struct {
+	cal *calendar.Calendar
+}
+
+func (gapRule) Name() string { return "gaps" }
+
+func (r gapRule) Check(ph *iex.PriceHistory) []Issue {
+	if len(ph.Prices) == 0 {
+		return nil
+	}
+	have := make(map[string]bool, len(ph.Prices))
+	var issues []Issue
+	for _, p := range ph.Prices {
+		have[p.Date] = true
+		d, err := calendar.ParseDate(p.Date)
+		if err != nil {
+			issues = append(issues, issue(ph, p.Date, r.Name(), Error, 0, "unparseable date"))
+			continue
+		}
+		if !r.cal.IsTradingDay(d) {
+			issues = append(issues, issue(ph, p.Date, r.Name(), Warning, 0, "bar on a non trading day"))
+		}
+	}
+	first, err1 := calendar.ParseDate(ph.Prices[0].Date)
+	last, err2 := calendar.ParseDate(ph.Prices[len(ph.Prices)-1].Date)
+	if err1 != nil || err2 != nil {
+		return issues
+	}
+	for _, d := range r.cal.TradingDaysBetween(first, last) {
+		if date := d.Format(calendar.DateFormat); !have[date] {
+			issues = append(issues, issue(ph, date, r.Name(), Warning, 0, "missing bar on a trading day"))
+		}
+	}
+	return issues
+}
+
+// staleRule flags runs of identical closes
+type staleRule struct {
+	run int
+}
+
+func (staleRule) Name() string { return "stale" }
+
+func (r staleRule) Check(ph *iex.PriceHistory) []Issue {
+	if r.run < 2 {
+		return nil
+	}
+	var issues []Issue
+	n := 1
+	for i := 1; i < len(ph.Prices); i++ {
+		if rawClose(ph.Prices[i]) != rawClose(ph.Prices[i-1]) {
+			n = 1
+			continue
+		}
+		if n++; n == r.run {
+			p := ph.Prices[i]
+			issues = append(issues, issue(ph, p.Date, r.Name(), Warning, rawClose(p),
+				"close %.2f repeated for %d sessions", rawClose(p), n))
+		}
+	}
+	return issues
+}
+
+// rawClose is the close a stale run is judged on, the vendor adjusted one
+// when the bar has no unadjusted series
+func rawClose(p iex.Prices) float64 {
+	if p.AdjustedOnly {
+		return p.Aclose
+	}
+	return p.Uclose
+}
+
+// extremeRule flags large daily moves in the adjusted close
+type extremeRule struct {
+	limit float64
+}
+
+func (extremeRule) Name() string { return "extreme" }
+
+func (r extremeRule) Check(ph *iex.PriceHistory) []Issue {
+	var issues []Issue
+	for i := 1; i < len(ph.Prices); i++ {
+		prev, cur := ph.Prices[i-1], ph.Prices[i]
+		if prev.Aclose <= 0 {
+			continue
+		}
+		if ret := cur.Aclose/prev.Aclose - 1; math.Abs(ret) > r.limit {
+			issues = append(issues, issue(ph, cur.Date, r.Name(), Warning, ret,
+				"adjusted return %.1f%%", ret*100))
+		}
+	}
+	return issues
+}
+
+// ratioRule requires one adjustment factor per bar across open, high, low and close
+type ratioRule struct {
+	tol float64
+}
+
+func (ratioRule) Name() string { return "ratio" }
+
+func (r ratioRule) Check(ph *iex.PriceHistory) []Issue {
+	var issues []Issue
+	for _, p := range ph.Prices {
+		if p.Uopen <= 0 || p.Uhigh <= 0 || p.Ulow <= 0 || p.Uclose <= 0 {
+			continue
+		}
+		ratios := []float64{p.Aopen / p.Uopen, p.Ahigh / p.Uhigh, p.Alow / p.Ulow, p.Aclose / p.Uclose}
+		lo, hi := ratios[0], ratios[0]
+		for _, x := range ratios[1:] {
+			lo, hi = math.Min(lo, x), math.Max(hi, x)
+		}
+		// two decimal rounding moves a ratio by up to 0.01/price
+		allowance := r.tol + 0.01/p.Ulow
+		switch {
+		case lo <= 0:
+			// the spread is undefined without a positive adjusted price
+			issues = append(issues, issue(ph, p.Date, r.Name(), Warning, math.NaN(),
+				"adjusted/unadjusted ratios span %.4f..%.4f", lo, hi))
+		case hi/lo-1 > allowance:
+			issues = append(issues, issue(ph, p.Date, r.Name(), Warning, hi/lo-1,
+				"adjusted/unadjusted ratios span %.4f..%.4f", lo, hi))
+		}
+	}
+	return issues
+}
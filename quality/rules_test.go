@@ -0,0 +1,112 @@
+package quality
+
+import (
+	"defcor/iex"
+	"math"
+	"testing"
+)
+
+func TestOHLCRules(t *testing.T) {
+	ph := &iex.PriceHistory{Symbol: "AAPL", Prices: []iex.Prices{
+		{Date: "2020-01-02", Uopen: 10, Uhigh: 11, Ulow: 9, Uclose: 10, Aopen: 5, Ahigh: 5.5, Alow: 4.5, Aclose: 5},
+		{Date: "2020-01-03", Uopen: 10, Uhigh: 9, Ulow: 11, Uclose: 10, Aopen: 5, Ahigh: 5.5, Alow: 4.5, Aclose: 6},
+	}}
+	tests := []struct {
+		rule  Rule
+		name  string
+		dates []string
+	}{
+		{ohlcRule{}, "ohlc", []string{"2020-01-03"}},
+		{ohlcRule{adjusted: true}, "ohlc_adjusted", []string{"2020-01-03"}},
+	}
+	for _, tt := range tests {
+		if tt.rule.Name() != tt.name {
+			t.Errorf("Name() = %q, want %q", tt.rule.Name(), tt.name)
+		}
+		issues := tt.rule.Check(ph)
+		if len(issues) != len(tt.dates) {
+			t.Fatalf("%s: got %v, want issues on %v", tt.name, issues, tt.dates)
+		}
+		for i, is := range issues {
+			if is.Date != tt.dates[i] || is.Rule != tt.name || is.Severity != Error {
+				t.Errorf("%s: issue %d = %+v", tt.name, i, is)
+			}
+		}
+	}
+}
+
+func TestRatioRule(t *testing.T) {
+	tests := []struct {
+		name  string
+		bar   iex.Prices
+		found bool
+		value float64
+	}{
+		{
+			name: "consistent factor",
+			bar:  iex.Prices{Uopen: 100, Uhigh: 110, Ulow: 90, Uclose: 105, Aopen: 50, Ahigh: 55, Alow: 45, Aclose: 52.5},
+		},
+		{
+			name:  "inconsistent factor",
+			bar:   iex.Prices{Uopen: 100, Uhigh: 110, Ulow: 90, Uclose: 100, Aopen: 50, Ahigh: 55, Alow: 45, Aclose: 60},
+			found: true,
+			value: 0.2,
+		},
+		{
+			name:  "non positive adjusted price has no spread",
+			bar:   iex.Prices{Uopen: 100, Uhigh: 110, Ulow: 90, Uclose: 100, Aopen: 50, Ahigh: 55, Alow: 0, Aclose: 50},
+			found: true,
+			value: math.NaN(),
+		},
+		{
+			name: "unadjusted prices missing",
+			bar:  iex.Prices{Aopen: 50, Ahigh: 55, Alow: 45, Aclose: 50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.bar.Date = "2020-01-02"
+			issues := ratioRule{tol: 0.01}.Check(&iex.PriceHistory{Prices: []iex.Prices{tt.bar}})
+			if !tt.found {
+				if len(issues) != 0 {
+					t.Errorf("got %v, want none", issues)
+				}
+				return
+			}
+			if len(issues) != 1 {
+				t.Fatalf("got %v, want one issue", issues)
+			}
+			got := issues[0].Value
+			if math.IsNaN(tt.value) {
+				if !math.IsNaN(got) {
+					t.Errorf("value %v, want NaN", got)
+				}
+				return
+			}
+			if math.IsInf(got, 0) || math.Abs(got-tt.value) > 1e-9 {
+				t.Errorf("value %v, want %v", got, tt.value)
+			}
+		})
+	}
+}
+
+func TestAdjustedOnlyBarsPassStagingRules(t *testing.T) {
+	ph := &iex.PriceHistory{Symbol: "AAPL", Prices: []iex.Prices{
+		{Date: "2020-01-02", Aopen: 5, Ahigh: 5.5, Alow: 4.5, Aclose: 5.25, Avolume: 200, AdjustedOnly: true},
+		{Date: "2020-01-03", Aopen: 5, Ahigh: 5.5, Alow: 4.5, Aclose: 5.25, Avolume: 200, AdjustedOnly: true},
+	}}
+	rules, err := Rules(DefaultConfig(), "ohlc", "ohlc_adjusted", "nonnegative", "ratio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range rules {
+		if issues := r.Check(ph); len(issues) != 0 {
+			t.Errorf("%s: got %v, want none", r.Name(), issues)
+		}
+	}
+	// a missing adjusted price is still an error
+	ph.Prices[1].Alow = 0
+	if issues := (nonNegativeRule{}).Check(ph); len(issues) != 1 || issues[0].Date != "2020-01-03" {
+		t.Errorf("nonnegative: got %v, want an issue on 2020-01-03", issues)
+	}
+}
@@ -0,0 +1,262 @@
+package rest
+
+import (
+	"defcor/adjust"
+	"defcor/calendar"
+	"defcor/db"
+	"defcor/iex"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// stockView is a stock as the api presents it
+type stockView struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	IexID    string `json:"iexId"`
+	Region   string `json:"region"`
+	Currency string `json:"currency"`
+	Figi     string `json:"figi"`
+	Cik      int    `json:"cik"`
+}
+
+func viewStock(s iex.Stock) stockView {
+	return stockView{s.Symbol, s.Name, s.Type, s.IexID, s.Region, s.Curr, s.Figi, s.Cik}
+}
+
+// dividendView is a dividend as the api presents it; a missing amount is
+// null rather than the empty string iex sends
+type dividendView struct {
+	DecDate iex.NullString `json:"declaredDate"`
+	ExDate  string         `json:"exDate"`
+	RecDate iex.NullString `json:"recordDate"`
+	PayDate iex.NullString `json:"paymentDate"`
+	Amount  *float64       `json:"amount"`
+	Flag    iex.NullString `json:"flag"`
+	Curr    iex.NullString `json:"currency"`
+	Freq    iex.NullString `json:"frequency"`
+}
+
+func viewDividend(d iex.Dividend) dividendView {
+	v := dividendView{d.DecDate, d.ExDate, d.RecDate, d.PayDate, nil, d.Flag, d.Curr, d.Freq}
+	if d.Amount.Valid {
+		amount := d.Amount.Float64
+		v.Amount = &amount
+	}
+	return v
+}
+
+// bar is a daily price in the adjustment the client asked for
+type bar struct {
+	Date   string  `json:"date"`
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume int     `json:"volume"`
+}
+
+func (s *Server) stocks(w http.ResponseWriter, r *http.Request) {
+	stks, err := s.db.Stocks()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	sort.Slice(stks, func(i, j int) bool { return stks[i].Symbol < stks[j].Symbol })
+	views := make([]stockView, len(stks))
+	for i, stk := range stks {
+		views[i] = viewStock(stk)
+	}
+	s.list(w, r, views)
+}
+
+func (s *Server) stock(w http.ResponseWriter, r *http.Request) {
+	symbol, ok := s.symbol(w, r, "/stocks/", 1)
+	if !ok {
+		return
+	}
+	stk, err := s.db.Stock(symbol)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.one(w, r, viewStock(stk))
+}
+
+// symbol reads the symbol from a path of want segments after prefix and
+// checks it is a known stock, answering 404 otherwise
+func (s *Server) symbol(w http.ResponseWriter, r *http.Request, prefix string, want int) (string, bool) {
+	args := pathArgs(r, prefix)
+	if len(args) != want {
+		writeError(w, http.StatusNotFound, "not found")
+		return "", false
+	}
+	symbol := strings.ToUpper(args[0])
+	_, err := s.db.Stock(symbol)
+	if err == db.ErrNotFound {
+		writeError(w, http.StatusNotFound, "unknown symbol "+symbol)
+		return "", false
+	}
+	if err != nil {
+		s.fail(w, err)
+		return "", false
+	}
+	return symbol, true
+}
+
+// prices serves bars between from and to; adjusted is false for raw bars,
+// true (the default) for the adjustment iex supplied, or split or total to
+// recompute it from the stored corporate actions
+func (s *Server) prices(w http.ResponseWriter, r *http.Request) {
+	symbol, ok := s.symbol(w, r, "/prices/", 1)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	from, to := q.Get("from"), q.Get("to")
+	for _, d := range []string{from, to} {
+		if d == "" {
+			continue
+		}
+		if _, err := calendar.ParseDate(d); err != nil {
+			writeError(w, http.StatusBadRequest, "dates must be yyyy-mm-dd")
+			return
+		}
+	}
+	adjusted := q.Get("adjusted")
+	switch adjusted {
+	case "", "true", "false", "split", "total":
+	default:
+		writeError(w, http.StatusBadRequest, "adjusted must be true, false, split or total")
+		return
+	}
+	limit, offset, ok := paging(w, r)
+	if !ok {
+		return
+	}
+	// one extra bar tells whether another page follows
+	ph, err := s.db.PricePage(symbol, orDefault(from, "-infinity"), orDefault(to, "infinity"), limit+1, offset)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	more := len(ph.Prices) > limit
+	if more {
+		ph.Prices = ph.Prices[:limit]
+	}
+	if adjusted == "split" || adjusted == "total" {
+		if ph, err = s.recompute(ph, adjusted); err != nil {
+			s.fail(w, err)
+			return
+		}
+	}
+	bars := make([]bar, 0, len(ph.Prices))
+	for _, p := range ph.Prices {
+		if adjusted == "false" {
+			bars = append(bars, bar{p.Date, p.Uopen, p.Uhigh, p.Ulow, p.Uclose, p.Uvolume})
+		} else {
+			bars = append(bars, bar{p.Date, p.Aopen, p.Ahigh, p.Alow, p.Aclose, p.Avolume})
+		}
+	}
+	s.page(w, r, reflect.ValueOf(bars), more, limit, offset)
+}
+
+// recompute adjusts a page of raw bars from every stored action of its symbol;
+// dividends are measured against closes read from outside the page as needed
+func (s *Server) recompute(ph *iex.PriceHistory, modeName string) (*iex.PriceHistory, error) {
+	mode, err := adjust.ParseMode(modeName)
+	if err != nil {
+		return nil, err
+	}
+	sh, err := s.db.SplitHistory(ph.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := s.db.DividendHistory(ph.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	var exdates []string
+	for _, d := range dh.Dividends {
+		exdates = append(exdates, d.ExDate)
+	}
+	closes, err := s.db.ClosesBefore(ph.Symbol, exdates)
+	if err != nil {
+		return nil, err
+	}
+	factors := adjust.PageFactors(ph.Prices, sh.Splits, dh.Dividends, "", closes)
+	return &iex.PriceHistory{Symbol: ph.Symbol, Prices: adjust.Adjust(ph.Prices, factors, mode)}, nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+func (s *Server) dividends(w http.ResponseWriter, r *http.Request) {
+	symbol, ok := s.symbol(w, r, "/dividends/", 1)
+	if !ok {
+		return
+	}
+	dh, err := s.db.DividendHistory(symbol)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	views := make([]dividendView, len(dh.Dividends))
+	for i, d := range dh.Dividends {
+		views[i] = viewDividend(d)
+	}
+	s.list(w, r, views)
+}
+
+func (s *Server) splits(w http.ResponseWriter, r *http.Request) {
+	symbol, ok := s.symbol(w, r, "/splits/", 1)
+	if !ok {
+		return
+	}
+	sh, err := s.db.SplitHistory(symbol)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	s.list(w, r, append([]iex.Split{}, sh.Splits...))
+}
+
+// financials serves the income, balance-sheet or cash-flow statements of a symbol, latest first
+func (s *Server) financials(w http.ResponseWriter, r *http.Request) {
+	symbol, ok := s.symbol(w, r, "/financials/", 2)
+	if !ok {
+		return
+	}
+	switch statement := pathArgs(r, "/financials/")[1]; statement {
+	case iex.KindIncome:
+		ih, err := s.db.IncomeHistory(symbol)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		s.list(w, r, append([]iex.IncomeStatement{}, ih.Income...))
+	case iex.KindBalance:
+		bh, err := s.db.BalanceHistory(symbol)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		s.list(w, r, append([]iex.BalanceSheet{}, bh.Balancesheet...))
+	case iex.KindCashFlow:
+		ch, err := s.db.CashFlowHistory(symbol)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		s.list(w, r, append([]iex.CashFlow{}, ch.Cashflow...))
+	default:
+		writeError(w, http.StatusNotFound, "statement must be income, balance-sheet or cash-flow")
+	}
+}
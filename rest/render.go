@@ -0,0 +1,201 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// page size bounds
+const (
+	DefaultLimit = 100
+	MaxLimit     = 5000
+)
+
+// list writes one page of items, a slice of structs, as a json envelope
+// {"data": [...], "next": url} or as csv with a Link header to the next page
+func (s *Server) list(w http.ResponseWriter, r *http.Request, items interface{}) {
+	limit, offset, ok := paging(w, r)
+	if !ok {
+		return
+	}
+	all := reflect.ValueOf(items)
+	n := all.Len()
+	lo, hi := offset, offset+limit
+	if lo > n {
+		lo = n
+	}
+	if hi > n {
+		hi = n
+	}
+	s.page(w, r, all.Slice(lo, hi), hi < n, limit, offset)
+}
+
+// paging reads the limit and offset parameters, answering 400 when invalid
+func paging(w http.ResponseWriter, r *http.Request) (limit, offset int, ok bool) {
+	q := r.URL.Query()
+	limit = DefaultLimit
+	var err error
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > MaxLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", MaxLimit))
+			return 0, 0, false
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			writeError(w, http.StatusBadRequest, "offset must not be negative")
+			return 0, 0, false
+		}
+	}
+	return limit, offset, true
+}
+
+// page writes items, the page of a slice found at offset, linking the next
+// page when more follow
+func (s *Server) page(w http.ResponseWriter, r *http.Request, page reflect.Value, more bool, limit, offset int) {
+	var next string
+	if more {
+		q := r.URL.Query()
+		q.Set("offset", strconv.Itoa(offset+page.Len()))
+		q.Set("limit", strconv.Itoa(limit))
+		next = (&url.URL{Path: r.URL.Path, RawQuery: q.Encode()}).String()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+	}
+	if wantCSV(r) {
+		s.sendCSV(w, r, page)
+		return
+	}
+	envelope := struct {
+		Data interface{} `json:"data"`
+		Next string      `json:"next,omitempty"`
+	}{page.Interface(), next}
+	s.sendJSON(w, r, envelope)
+}
+
+// one writes a single struct as a json object or a one row csv
+func (s *Server) one(w http.ResponseWriter, r *http.Request, item interface{}) {
+	if wantCSV(r) {
+		v := reflect.ValueOf(item)
+		rows := reflect.MakeSlice(reflect.SliceOf(v.Type()), 1, 1)
+		rows.Index(0).Set(v)
+		s.sendCSV(w, r, rows)
+		return
+	}
+	s.sendJSON(w, r, item)
+}
+
+func wantCSV(r *http.Request) bool {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return f == "csv"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/csv")
+}
+
+func (s *Server) sendJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		s.fail(w, err)
+		return
+	}
+	send(w, r, "application/json", buf.Bytes())
+}
+
+// sendCSV writes rows, a slice of structs, with the json names of their fields as header
+func (s *Server) sendCSV(w http.ResponseWriter, r *http.Request, rows reflect.Value) {
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	typ := rows.Type().Elem()
+	var header []string
+	var fields []int
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if name == "-" || typ.Field(i).PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = typ.Field(i).Name
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+	cw.Write(header)
+	record := make([]string, len(fields))
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		for j, f := range fields {
+			cell, err := cellString(row.Field(f))
+			if err != nil {
+				s.fail(w, err)
+				return
+			}
+			record[j] = cell
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		s.fail(w, err)
+		return
+	}
+	send(w, r, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// cellString renders a field as csv text; null values are empty
+func cellString(v reflect.Value) (string, error) {
+	if m, ok := v.Interface().(json.Marshaler); ok {
+		data, err := m.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+		var s string
+		if json.Unmarshal(data, &s) == nil {
+			return s, nil
+		}
+		if string(data) == "null" {
+			return "", nil
+		}
+		return string(data), nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "", nil
+		}
+		return cellString(v.Elem())
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// send writes body with a strong ETag, answering 304 when the client has it
+func send(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Vary", "Accept, Authorization")
+	for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if t := strings.TrimSpace(tag); t == etag || t == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body)
+}
@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"defcor/db"
+	"defcor/iex"
+	"defcor/logging"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Server is a read-only http api over the stored market data. Every
+// request must carry a live token from api_tokens as a bearer token.
+type Server struct {
+	db  store
+	log *logging.Logger
+	mux *http.ServeMux
+}
+
+// store is what the api reads from the database
+type store interface {
+	CheckToken(hash string) (string, error)
+	Stocks() ([]iex.Stock, error)
+	Stock(symbol string) (iex.Stock, error)
+	PricePage(symbol, from, to string, limit, offset int) (*iex.PriceHistory, error)
+	ClosesBefore(symbol string, dates []string) (map[string]float64, error)
+	DividendHistory(symbol string) (*iex.DividendHistory, error)
+	SplitHistory(symbol string) (*iex.SplitHistory, error)
+	IncomeHistory(symbol string) (*iex.IncomeHistory, error)
+	BalanceHistory(symbol string) (*iex.BalanceHistory, error)
+	CashFlowHistory(symbol string) (*iex.CashFlowHistory, error)
+}
+
+var _ store = (*db.Conn)(nil)
+
+// NewServer routes the api over conn
+func NewServer(conn *db.Conn, log *logging.Logger) *Server {
+	return newServer(conn, log)
+}
+
+func newServer(conn store, log *logging.Logger) *Server {
+	s := &Server{db: conn, log: log, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/stocks", s.stocks)
+	s.mux.HandleFunc("/stocks/", s.stock)
+	s.mux.HandleFunc("/prices/", s.prices)
+	s.mux.HandleFunc("/dividends/", s.dividends)
+	s.mux.HandleFunc("/splits/", s.splits)
+	s.mux.HandleFunc("/financials/", s.financials)
+	return s
+}
+
+// GenerateToken makes a new random api token
+func GenerateToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken is the form a token is stored and looked up in
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ServeHTTP authenticates, routes and logs a request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	client := s.serve(rec, r)
+	s.log.Info("api request", "client", client, "method", r.Method, "path", r.URL.Path,
+		"status", rec.status, "duration", time.Since(start))
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) string {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "read only api")
+		return ""
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="defcor"`)
+		writeError(w, http.StatusUnauthorized, "missing bearer token")
+		return ""
+	}
+	client, err := s.db.CheckToken(HashToken(token))
+	if err == db.ErrNotFound {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="defcor", error="invalid_token"`)
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return ""
+	}
+	if err != nil {
+		s.fail(w, err)
+		return ""
+	}
+	s.mux.ServeHTTP(w, r)
+	return client
+}
+
+// fail reports an unexpected error without leaking its detail
+func (s *Server) fail(w http.ResponseWriter, err error) {
+	s.log.Error("api request failed", "err", err)
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// pathArgs splits the path after prefix into its segments
+func pathArgs(r *http.Request, prefix string) []string {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if rest == "" {
+		return nil
+	}
+	return strings.Split(rest, "/")
+}
@@ -0,0 +1,289 @@
+package rest
+
+import (
+	"database/sql"
+	"defcor/db"
+	"defcor/iex"
+	"defcor/logging"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const token = "secret"
+
+// memory serves the api from fixed histories
+type memory struct {
+	stocks    []iex.Stock
+	prices    map[string][]iex.Prices
+	dividends map[string][]iex.Dividend
+	splits    map[string][]iex.Split
+	// closed records the exdates ClosesBefore was asked for
+	closed []string
+}
+
+func (m *memory) CheckToken(hash string) (string, error) {
+	if hash != HashToken(token) {
+		return "", db.ErrNotFound
+	}
+	return "tester", nil
+}
+
+func (m *memory) Stocks() ([]iex.Stock, error) {
+	return append([]iex.Stock{}, m.stocks...), nil
+}
+
+func (m *memory) Stock(symbol string) (iex.Stock, error) {
+	for _, s := range m.stocks {
+		if s.Symbol == symbol {
+			return s, nil
+		}
+	}
+	return iex.Stock{}, db.ErrNotFound
+}
+
+func (m *memory) PricePage(symbol, from, to string, limit, offset int) (*iex.PriceHistory, error) {
+	var page []iex.Prices
+	for _, p := range m.prices[symbol] {
+		if (from == "-infinity" || p.Date >= from) && (to == "infinity" || p.Date <= to) {
+			page = append(page, p)
+		}
+	}
+	if offset > len(page) {
+		offset = len(page)
+	}
+	page = page[offset:]
+	if len(page) > limit {
+		page = page[:limit]
+	}
+	return &iex.PriceHistory{Symbol: symbol, Prices: page}, nil
+}
+
+func (m *memory) ClosesBefore(symbol string, dates []string) (map[string]float64, error) {
+	m.closed = append(m.closed, dates...)
+	closes := make(map[string]float64)
+	for _, d := range dates {
+		for _, p := range m.prices[symbol] {
+			if p.Date < d {
+				closes[d] = p.Uclose
+			}
+		}
+	}
+	return closes, nil
+}
+
+func (m *memory) DividendHistory(symbol string) (*iex.DividendHistory, error) {
+	return &iex.DividendHistory{Symbol: symbol, Dividends: m.dividends[symbol]}, nil
+}
+
+func (m *memory) SplitHistory(symbol string) (*iex.SplitHistory, error) {
+	return &iex.SplitHistory{Symbol: symbol, Splits: m.splits[symbol]}, nil
+}
+
+func (m *memory) IncomeHistory(symbol string) (*iex.IncomeHistory, error) {
+	return &iex.IncomeHistory{Symbol: symbol}, nil
+}
+
+func (m *memory) BalanceHistory(symbol string) (*iex.BalanceHistory, error) {
+	return &iex.BalanceHistory{Symbol: symbol}, nil
+}
+
+func (m *memory) CashFlowHistory(symbol string) (*iex.CashFlowHistory, error) {
+	return &iex.CashFlowHistory{Symbol: symbol}, nil
+}
+
+func fixture() *memory {
+	bar := func(date string, close float64) iex.Prices {
+		return iex.Prices{Date: date, Uopen: close, Uhigh: close, Ulow: close, Uclose: close, Uvolume: 100,
+			Aopen: close, Ahigh: close, Alow: close, Aclose: close, Avolume: 100}
+	}
+	return &memory{
+		stocks: []iex.Stock{{Symbol: "IBM", Name: "IBM"}, {Symbol: "AAPL", Name: "Apple Inc"}},
+		prices: map[string][]iex.Prices{"AAPL": {
+			bar("2020-08-27", 400), bar("2020-08-28", 500), bar("2020-08-31", 130), bar("2020-09-01", 134),
+		}},
+		dividends: map[string][]iex.Dividend{"AAPL": {
+			{ExDate: "2020-08-07", Amount: iex.NullNumber{NullFloat64: sql.NullFloat64{Float64: 0.82, Valid: true}}},
+			{ExDate: "2020-11-06", Flag: iex.NullString{NullString: sql.NullString{String: "Cash", Valid: true}}},
+		}},
+		splits: map[string][]iex.Split{"AAPL": {{ExDate: "2020-08-31", ToFactor: 4, FromFactor: 1}}},
+	}
+}
+
+func get(t *testing.T, srv http.Handler, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	for k, v := range header {
+		if v == "" {
+			req.Header.Del(k)
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func discard() *logging.Logger {
+	return logging.New(ioutil.Discard, logging.Error, logging.Logfmt)
+}
+
+func TestAuth(t *testing.T) {
+	srv := newServer(fixture(), discard())
+	tests := []struct {
+		name   string
+		header map[string]string
+		status int
+		detail string
+	}{
+		{"no token", map[string]string{"Authorization": ""}, http.StatusUnauthorized, `Bearer realm="defcor"`},
+		{"not a bearer token", map[string]string{"Authorization": "Basic " + token}, http.StatusUnauthorized, `Bearer realm="defcor"`},
+		{"unknown token", map[string]string{"Authorization": "Bearer other"}, http.StatusUnauthorized, `error="invalid_token"`},
+		{"live token", nil, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		rec := get(t, srv, "/stocks", tt.header)
+		if rec.Code != tt.status || !strings.Contains(rec.Header().Get("WWW-Authenticate"), tt.detail) {
+			t.Errorf("%s: got %d %q, want %d %q", tt.name, rec.Code, rec.Header().Get("WWW-Authenticate"), tt.status, tt.detail)
+		}
+	}
+	req := httptest.NewRequest(http.MethodPost, "/stocks", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD" {
+		t.Errorf("post: got %d, allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func TestETag(t *testing.T) {
+	srv := newServer(fixture(), discard())
+	first := get(t, srv, "/stocks/AAPL", nil)
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("got %d with etag %q", first.Code, etag)
+	}
+	if again := get(t, srv, "/stocks/AAPL", nil); again.Header().Get("ETag") != etag {
+		t.Errorf("etag changed between identical responses: %q, %q", etag, again.Header().Get("ETag"))
+	}
+	cached := get(t, srv, "/stocks/AAPL", map[string]string{"If-None-Match": `"stale", ` + etag})
+	if cached.Code != http.StatusNotModified || cached.Body.Len() != 0 {
+		t.Errorf("matching If-None-Match: got %d with %d bytes", cached.Code, cached.Body.Len())
+	}
+	if other := get(t, srv, "/stocks/IBM", map[string]string{"If-None-Match": etag}); other.Code != http.StatusOK {
+		t.Errorf("another resource with the etag: got %d", other.Code)
+	}
+	if csv := get(t, srv, "/stocks/AAPL?format=csv", nil); csv.Header().Get("ETag") == etag {
+		t.Error("csv and json share an etag")
+	}
+}
+
+type envelope struct {
+	Data []map[string]interface{} `json:"data"`
+	Next string                   `json:"next"`
+}
+
+func TestPaging(t *testing.T) {
+	srv := newServer(fixture(), discard())
+	var env envelope
+	rec := get(t, srv, "/stocks?limit=1", nil)
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatal(err)
+	}
+	if len(env.Data) != 1 || env.Data[0]["symbol"] != "AAPL" || env.Next != "/stocks?limit=1&offset=1" {
+		t.Fatalf("first page: got %+v", env)
+	}
+	if link := rec.Header().Get("Link"); link != `</stocks?limit=1&offset=1>; rel="next"` {
+		t.Errorf("Link = %q", link)
+	}
+	next := env.Next
+	env = envelope{}
+	rec = get(t, srv, next, nil)
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatal(err)
+	}
+	if len(env.Data) != 1 || env.Data[0]["symbol"] != "IBM" || env.Next != "" || rec.Header().Get("Link") != "" {
+		t.Errorf("last page: got %+v, link %q", env, rec.Header().Get("Link"))
+	}
+
+	// prices page in the database; one extra bar tells another page follows
+	env = envelope{}
+	rec = get(t, srv, "/prices/aapl?limit=3&adjusted=false", nil)
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatal(err)
+	}
+	if len(env.Data) != 3 || env.Next != "/prices/aapl?adjusted=false&limit=3&offset=3" {
+		t.Errorf("prices: got %+v", env)
+	}
+
+	for _, path := range []string{"/stocks?limit=0", "/stocks?limit=5001", "/stocks?offset=-1", "/prices/AAPL?from=2020-13-01"} {
+		if rec := get(t, srv, path, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got %d, want %d", path, rec.Code, http.StatusBadRequest)
+		}
+	}
+	if rec := get(t, srv, "/prices/MSFT", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown symbol: got %d", rec.Code)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	srv := newServer(fixture(), discard())
+	tests := []struct {
+		path   string
+		accept string
+		want   string
+	}{
+		{
+			path: "/dividends/AAPL?format=csv",
+			want: "declaredDate,exDate,recordDate,paymentDate,amount,flag,currency,frequency\n" +
+				",2020-08-07,,,0.82,,,\n" +
+				",2020-11-06,,,,Cash,,\n",
+		},
+		{
+			path:   "/splits/AAPL",
+			accept: "text/csv",
+			want:   "declaredDate,exDate,toFactor,fromFactor\n,2020-08-31,4,1\n",
+		},
+		{
+			path: "/prices/AAPL?format=csv&limit=2&adjusted=false",
+			want: "date,open,high,low,close,volume\n2020-08-27,400,400,400,400,100\n2020-08-28,500,500,500,500,100\n",
+		},
+	}
+	for _, tt := range tests {
+		rec := get(t, srv, tt.path, map[string]string{"Accept": tt.accept})
+		if got := rec.Body.String(); got != tt.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", tt.path, got, tt.want)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+			t.Errorf("%s: Content-Type %q", tt.path, ct)
+		}
+	}
+	if rec := get(t, srv, "/prices/AAPL?format=csv&limit=2", nil); !strings.Contains(rec.Header().Get("Link"), "offset=2") {
+		t.Errorf("csv page without a Link header to the next one")
+	}
+}
+
+func TestRecomputedPrices(t *testing.T) {
+	m := fixture()
+	srv := newServer(m, discard())
+	var env envelope
+	if err := json.Unmarshal(get(t, srv, "/prices/AAPL?adjusted=split", nil).Body.Bytes(), &env); err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{100, 125, 130, 134}
+	if len(env.Data) != len(want) {
+		t.Fatalf("got %+v", env.Data)
+	}
+	for i, b := range env.Data {
+		if b["close"] != want[i] {
+			t.Errorf("%v: close %v, want %v", b["date"], b["close"], want[i])
+		}
+	}
+	if len(m.closed) != 2 || m.closed[0] != "2020-08-07" || m.closed[1] != "2020-11-06" {
+		t.Errorf("closes asked for %v, want every dividend exdate", m.closed)
+	}
+}
@@ -0,0 +1,101 @@
+package rotation
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Spec decides which symbols are due for a deep refresh.
+// A zero Cycle ranks the whole universe oldest refreshed first; otherwise
+// symbols are hashed into Cycle shards and only shard Day is considered.
+type Spec struct {
+	Day   int
+	Cycle int
+}
+
+// Oldest ranks every symbol by its last deep refresh
+var Oldest = Spec{}
+
+// String renders the spec as accepted by ParseSpec
+func (s Spec) String() string {
+	if s.Cycle == 0 {
+		return "oldest"
+	}
+	return fmt.Sprintf("%d/%d", s.Day, s.Cycle)
+}
+
+// ParseSpec reads "oldest", "N/M" (day N of an M day cycle, counted from 0)
+// or "M" (today's day of an M day cycle)
+func ParseSpec(s string, today time.Time) (Spec, error) {
+	if s == "" || s == "oldest" {
+		return Oldest, nil
+	}
+	parts := strings.SplitN(s, "/", 2)
+	cycle, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || cycle < 1 {
+		return Spec{}, fmt.Errorf("bad shard spec %q", s)
+	}
+	if len(parts) == 1 {
+		return Spec{Day: DayOfCycle(today, cycle), Cycle: cycle}, nil
+	}
+	day, err := strconv.Atoi(parts[0])
+	if err != nil || day < 0 || day >= cycle {
+		return Spec{}, fmt.Errorf("bad shard spec %q", s)
+	}
+	return Spec{Day: day, Cycle: cycle}, nil
+}
+
+// DayOfCycle numbers calendar days so consecutive days walk every shard
+func DayOfCycle(t time.Time, cycle int) int {
+	days := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400
+	return int(days % int64(cycle))
+}
+
+// Shard places symbol in one of cycle shards; the assignment is stable
+// across runs and independent of the rest of the universe
+func Shard(symbol string, cycle int) int {
+	h := fnv.New32a()
+	h.Write([]byte(symbol))
+	return int(h.Sum32() % uint32(cycle))
+}
+
+// Candidate is a symbol that may be deep refreshed
+type Candidate struct {
+	Symbol string
+	// Refreshed is the last deep refresh, zero if never
+	Refreshed time.Time
+	Credits   int
+}
+
+// Select picks the candidates to deep refresh today: those in the spec's
+// shard, oldest refreshed first (ties by symbol), until the next one would
+// exceed budget. A budget of zero or less is unlimited.
+func Select(cands []Candidate, spec Spec, budget int) []Candidate {
+	var pool []Candidate
+	for _, c := range cands {
+		if spec.Cycle == 0 || Shard(c.Symbol, spec.Cycle) == spec.Day {
+			pool = append(pool, c)
+		}
+	}
+	sort.Slice(pool, func(i, j int) bool {
+		if !pool[i].Refreshed.Equal(pool[j].Refreshed) {
+			return pool[i].Refreshed.Before(pool[j].Refreshed)
+		}
+		return pool[i].Symbol < pool[j].Symbol
+	})
+	if budget <= 0 {
+		return pool
+	}
+	var spent int
+	for i, c := range pool {
+		if spent+c.Credits > budget {
+			return pool[:i]
+		}
+		spent += c.Credits
+	}
+	return pool
+}
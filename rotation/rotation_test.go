@@ -0,0 +1,102 @@
+package rotation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSpec(t *testing.T) {
+	today := time.Date(2021, 1, 8, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want Spec
+		err  bool
+	}{
+		{in: "", want: Oldest},
+		{in: "oldest", want: Oldest},
+		{in: "2/5", want: Spec{Day: 2, Cycle: 5}},
+		{in: "5", want: Spec{Day: DayOfCycle(today, 5), Cycle: 5}},
+		{in: "5/5", err: true},
+		{in: "-1/5", err: true},
+		{in: "0", err: true},
+		{in: "x", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpec(tt.in, today)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseSpec(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSpec(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDayOfCycleWalksEveryShard(t *testing.T) {
+	seen := make(map[int]bool)
+	start := time.Date(2021, 1, 8, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		seen[DayOfCycle(start.AddDate(0, 0, i), 7)] = true
+	}
+	if len(seen) != 7 {
+		t.Errorf("7 consecutive days hit %d of 7 shards", len(seen))
+	}
+}
+
+func TestSelect(t *testing.T) {
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	cands := []Candidate{
+		{Symbol: "MSFT", Refreshed: recent, Credits: 10},
+		{Symbol: "AAPL", Refreshed: old, Credits: 10},
+		{Symbol: "IBM", Credits: 10},
+		{Symbol: "GE", Refreshed: old, Credits: 10},
+	}
+	tests := []struct {
+		name   string
+		budget int
+		want   []string
+	}{
+		{"unlimited", 0, []string{"IBM", "AAPL", "GE", "MSFT"}},
+		{"budget stops before overspending", 25, []string{"IBM", "AAPL"}},
+		{"budget below one refresh", 5, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Select(cands, Oldest, tt.budget)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i, c := range got {
+				if c.Symbol != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSelectShard(t *testing.T) {
+	var cands []Candidate
+	for _, s := range []string{"AAPL", "MSFT", "IBM", "GE", "F", "T", "KO", "PEP"} {
+		cands = append(cands, Candidate{Symbol: s, Credits: 1})
+	}
+	picked := make(map[string]int)
+	for day := 0; day < 3; day++ {
+		for _, c := range Select(cands, Spec{Day: day, Cycle: 3}, 0) {
+			if Shard(c.Symbol, 3) != day {
+				t.Errorf("%s selected on day %d but sits in shard %d", c.Symbol, day, Shard(c.Symbol, 3))
+			}
+			picked[c.Symbol]++
+		}
+	}
+	for _, c := range cands {
+		if picked[c.Symbol] != 1 {
+			t.Errorf("%s picked %d times over a full cycle", c.Symbol, picked[c.Symbol])
+		}
+	}
+}
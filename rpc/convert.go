@@ -0,0 +1,152 @@
+package rpc
+
+import (
+	"defcor/iex"
+	"defcor/rpc/defcorpb"
+)
+
+func stockMessage(s iex.Stock) *defcorpb.Stock {
+	return &defcorpb.Stock{
+		Symbol:   s.Symbol,
+		Name:     s.Name,
+		Type:     s.Type,
+		IexId:    s.IexID,
+		Region:   s.Region,
+		Currency: s.Curr,
+		Figi:     s.Figi,
+		Cik:      int64(s.Cik),
+	}
+}
+
+// barMessage takes the unadjusted columns of p when raw, else the adjusted ones
+func barMessage(symbol string, p iex.Prices, raw bool) *defcorpb.Bar {
+	if raw {
+		return &defcorpb.Bar{Symbol: symbol, Date: p.Date, Open: p.Uopen, High: p.Uhigh,
+			Low: p.Ulow, Close: p.Uclose, Volume: int64(p.Uvolume)}
+	}
+	return &defcorpb.Bar{Symbol: symbol, Date: p.Date, Open: p.Aopen, High: p.Ahigh,
+		Low: p.Alow, Close: p.Aclose, Volume: int64(p.Avolume)}
+}
+
+func dividendMessage(d iex.Dividend) *defcorpb.Dividend {
+	return &defcorpb.Dividend{
+		DeclaredDate: nullString(d.DecDate),
+		ExDate:       d.ExDate,
+		RecordDate:   nullString(d.RecDate),
+		PaymentDate:  nullString(d.PayDate),
+		Amount:       nullNumber(d.Amount),
+		Flag:         nullString(d.Flag),
+		Currency:     nullString(d.Curr),
+		Frequency:    nullString(d.Freq),
+	}
+}
+
+func splitMessage(s iex.Split) *defcorpb.Split {
+	return &defcorpb.Split{
+		DeclaredDate: nullString(s.DecDate),
+		ExDate:       s.ExDate,
+		ToFactor:     s.ToFactor,
+		FromFactor:   s.FromFactor,
+	}
+}
+
+func incomeMessage(s iex.IncomeStatement) *defcorpb.IncomeStatement {
+	return &defcorpb.IncomeStatement{
+		ReportDate:             s.ReportDate,
+		FiscalDate:             s.FiscalDate,
+		Currency:               s.Currency,
+		TotalRevenue:           s.TotalRevenue,
+		CostOfRevenue:          s.CostOfRevenue,
+		GrossProfit:            s.GrossProfit,
+		ResearchAndDevelopment: s.ResearchAndDevelopment,
+		SellingGeneralAndAdmin: s.SellingGeneralAndAdmin,
+		OperatingExpense:       s.OperatingExpense,
+		OperatingIncome:        s.OperatingIncome,
+		OtherIncomeExpenseNet:  s.OtherIncomeExpenseNet,
+		Ebit:                   s.Ebit,
+		InterestIncome:         s.InterestIncome,
+		PretaxIncome:           s.PretaxIncome,
+		IncomeTax:              s.IncomeTax,
+		MinorityInterest:       s.MinorityInterest,
+		NetIncome:              s.NetIncome,
+		NetIncomeBasic:         s.NetIncomeBasic,
+	}
+}
+
+func balanceMessage(s iex.BalanceSheet) *defcorpb.BalanceSheet {
+	return &defcorpb.BalanceSheet{
+		ReportDate:              s.ReportDate,
+		FiscalDate:              s.FiscalDate,
+		Currency:                s.Currency,
+		CurrentCash:             s.CurrentCash,
+		ShortTermInvestments:    s.ShortTermInvestments,
+		Receivables:             s.Receivables,
+		Inventory:               s.Inventory,
+		OtherCurrentAssets:      s.OtherCurrentAssets,
+		CurrentAssets:           s.CurrentAssets,
+		LongTermInvestments:     s.LongTermInvestments,
+		PropertyPlantEquipment:  s.PropertyPlantEquipment,
+		Goodwill:                nullInt64(s.Goodwill),
+		IntangibleAssets:        nullInt64(s.IntangibleAssets),
+		OtherAssets:             s.OtherAssets,
+		TotalAssets:             s.TotalAssets,
+		AccountsPayable:         s.AccountsPayable,
+		CurrentLongTermDebt:     s.CurrentLongTermDebt,
+		OtherCurrentLiabilities: s.OtherCurrentLiabilities,
+		TotalCurrentLiabilities: s.TotalCurrentLiabilities,
+		LongTermDebt:            s.LongTermDebt,
+		OtherLiabilities:        s.OtherLiabilities,
+		MinorityInterest:        s.MinorityInterest,
+		TotalLiabilities:        s.TotalLiabilities,
+		CommonStock:             s.CommonStock,
+		RetainedEarnings:        s.RetainedEarnings,
+		TreasuryStock:           nullInt64(s.TreasuryStock),
+		CapitalSurplus:          nullInt64(s.CapitalSurplus),
+		ShareholderEquity:       s.ShareholderEquity,
+		NetTangibleAssets:       s.NetTangibleAssets,
+	}
+}
+
+func cashFlowMessage(s iex.CashFlow) *defcorpb.CashFlow {
+	return &defcorpb.CashFlow{
+		ReportDate:              s.ReportDate,
+		FiscalDate:              s.FiscalDate,
+		Currency:                s.Currency,
+		NetIncome:               s.NetIncome,
+		Depreciation:            s.Depreciation,
+		ChangesInReceivables:    s.ChangesInReceivables,
+		ChangesInInventories:    s.ChangesInInventories,
+		CashChange:              s.CashChange,
+		CashFlow:                s.CashFlow,
+		CapitalExpenditures:     s.CapitalExpenditures,
+		Investments:             s.Investments,
+		InvestingActivityOther:  s.InvestingActivityOther,
+		TotalInvestingCashFlows: s.TotalInvestingCashFlows,
+		DividendsPaid:           s.DividendsPaid,
+		NetBorrowings:           s.NetBorrowings,
+		OtherFinancingCashFlows: s.OtherFinancingCashFlows,
+		CashFlowFinancing:       s.CashFlowFinancing,
+		ExchangeRateEffect:      nullInt64(s.ExchangeRateEffect),
+	}
+}
+
+func nullString(ns iex.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	return &ns.String
+}
+
+func nullNumber(nn iex.NullNumber) *float64 {
+	if !nn.Valid {
+		return nil
+	}
+	return &nn.Float64
+}
+
+func nullInt64(ni iex.NullInt64) *int64 {
+	if !ni.Valid {
+		return nil
+	}
+	return &ni.Int64
+}
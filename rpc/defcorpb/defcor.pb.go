@@ -0,0 +1,1810 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        v3.19.1
+// source: defcor.proto
+
+// defcor serves the stored security master, prices, corporate actions and
+// financial statements. Dates are yyyy-mm-dd strings as in the database.
+
+package defcorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Adjustment int32
+
+const (
+	// the adjusted columns supplied by iex
+	Adjustment_ADJUSTMENT_IEX Adjustment = 0
+	// unadjusted bars
+	Adjustment_ADJUSTMENT_RAW Adjustment = 1
+	// recomputed from stored splits
+	Adjustment_ADJUSTMENT_SPLIT Adjustment = 2
+	// recomputed from stored splits and dividends
+	Adjustment_ADJUSTMENT_TOTAL Adjustment = 3
+)
+
+// Enum value maps for Adjustment.
+var (
+	Adjustment_name = map[int32]string{
+		0: "ADJUSTMENT_IEX",
+		1: "ADJUSTMENT_RAW",
+		2: "ADJUSTMENT_SPLIT",
+		3: "ADJUSTMENT_TOTAL",
+	}
+	Adjustment_value = map[string]int32{
+		"ADJUSTMENT_IEX":   0,
+		"ADJUSTMENT_RAW":   1,
+		"ADJUSTMENT_SPLIT": 2,
+		"ADJUSTMENT_TOTAL": 3,
+	}
+)
+
+func (x Adjustment) Enum() *Adjustment {
+	p := new(Adjustment)
+	*p = x
+	return p
+}
+
+func (x Adjustment) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Adjustment) Descriptor() protoreflect.EnumDescriptor {
+	return file_defcor_proto_enumTypes[0].Descriptor()
+}
+
+func (Adjustment) Type() protoreflect.EnumType {
+	return &file_defcor_proto_enumTypes[0]
+}
+
+func (x Adjustment) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Adjustment.Descriptor instead.
+func (Adjustment) EnumDescriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{0}
+}
+
+type ListStocksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListStocksRequest) Reset() {
+	*x = ListStocksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListStocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStocksRequest) ProtoMessage() {}
+
+func (x *ListStocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStocksRequest.ProtoReflect.Descriptor instead.
+func (*ListStocksRequest) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{0}
+}
+
+type GetStockRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+}
+
+func (x *GetStockRequest) Reset() {
+	*x = GetStockRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockRequest) ProtoMessage() {}
+
+func (x *GetStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockRequest.ProtoReflect.Descriptor instead.
+func (*GetStockRequest) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetStockRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type SymbolRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+}
+
+func (x *SymbolRequest) Reset() {
+	*x = SymbolRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SymbolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolRequest) ProtoMessage() {}
+
+func (x *SymbolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolRequest.ProtoReflect.Descriptor instead.
+func (*SymbolRequest) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SymbolRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type StreamBarsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbols []string `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	// inclusive bounds, empty for unbounded
+	From       string     `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To         string     `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Adjustment Adjustment `protobuf:"varint,4,opt,name=adjustment,proto3,enum=defcor.v1.Adjustment" json:"adjustment,omitempty"`
+}
+
+func (x *StreamBarsRequest) Reset() {
+	*x = StreamBarsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamBarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBarsRequest) ProtoMessage() {}
+
+func (x *StreamBarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBarsRequest.ProtoReflect.Descriptor instead.
+func (*StreamBarsRequest) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StreamBarsRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *StreamBarsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *StreamBarsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *StreamBarsRequest) GetAdjustment() Adjustment {
+	if x != nil {
+		return x.Adjustment
+	}
+	return Adjustment_ADJUSTMENT_IEX
+}
+
+type Stock struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol   string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type     string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	IexId    string `protobuf:"bytes,4,opt,name=iex_id,json=iexId,proto3" json:"iex_id,omitempty"`
+	Region   string `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	Currency string `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	Figi     string `protobuf:"bytes,7,opt,name=figi,proto3" json:"figi,omitempty"`
+	Cik      int64  `protobuf:"varint,8,opt,name=cik,proto3" json:"cik,omitempty"`
+}
+
+func (x *Stock) Reset() {
+	*x = Stock{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Stock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stock) ProtoMessage() {}
+
+func (x *Stock) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stock.ProtoReflect.Descriptor instead.
+func (*Stock) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Stock) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Stock) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Stock) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Stock) GetIexId() string {
+	if x != nil {
+		return x.IexId
+	}
+	return ""
+}
+
+func (x *Stock) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Stock) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Stock) GetFigi() string {
+	if x != nil {
+		return x.Figi
+	}
+	return ""
+}
+
+func (x *Stock) GetCik() int64 {
+	if x != nil {
+		return x.Cik
+	}
+	return 0
+}
+
+type Bar struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Symbol string  `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Date   string  `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Open   float64 `protobuf:"fixed64,3,opt,name=open,proto3" json:"open,omitempty"`
+	High   float64 `protobuf:"fixed64,4,opt,name=high,proto3" json:"high,omitempty"`
+	Low    float64 `protobuf:"fixed64,5,opt,name=low,proto3" json:"low,omitempty"`
+	Close  float64 `protobuf:"fixed64,6,opt,name=close,proto3" json:"close,omitempty"`
+	Volume int64   `protobuf:"varint,7,opt,name=volume,proto3" json:"volume,omitempty"`
+}
+
+func (x *Bar) Reset() {
+	*x = Bar{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Bar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bar) ProtoMessage() {}
+
+func (x *Bar) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bar.ProtoReflect.Descriptor instead.
+func (*Bar) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Bar) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Bar) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *Bar) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Bar) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Bar) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Bar) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Bar) GetVolume() int64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type Dividend struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeclaredDate *string  `protobuf:"bytes,1,opt,name=declared_date,json=declaredDate,proto3,oneof" json:"declared_date,omitempty"`
+	ExDate       string   `protobuf:"bytes,2,opt,name=ex_date,json=exDate,proto3" json:"ex_date,omitempty"`
+	RecordDate   *string  `protobuf:"bytes,3,opt,name=record_date,json=recordDate,proto3,oneof" json:"record_date,omitempty"`
+	PaymentDate  *string  `protobuf:"bytes,4,opt,name=payment_date,json=paymentDate,proto3,oneof" json:"payment_date,omitempty"`
+	Amount       *float64 `protobuf:"fixed64,5,opt,name=amount,proto3,oneof" json:"amount,omitempty"`
+	Flag         *string  `protobuf:"bytes,6,opt,name=flag,proto3,oneof" json:"flag,omitempty"`
+	Currency     *string  `protobuf:"bytes,7,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	Frequency    *string  `protobuf:"bytes,8,opt,name=frequency,proto3,oneof" json:"frequency,omitempty"`
+}
+
+func (x *Dividend) Reset() {
+	*x = Dividend{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Dividend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dividend) ProtoMessage() {}
+
+func (x *Dividend) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dividend.ProtoReflect.Descriptor instead.
+func (*Dividend) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Dividend) GetDeclaredDate() string {
+	if x != nil && x.DeclaredDate != nil {
+		return *x.DeclaredDate
+	}
+	return ""
+}
+
+func (x *Dividend) GetExDate() string {
+	if x != nil {
+		return x.ExDate
+	}
+	return ""
+}
+
+func (x *Dividend) GetRecordDate() string {
+	if x != nil && x.RecordDate != nil {
+		return *x.RecordDate
+	}
+	return ""
+}
+
+func (x *Dividend) GetPaymentDate() string {
+	if x != nil && x.PaymentDate != nil {
+		return *x.PaymentDate
+	}
+	return ""
+}
+
+func (x *Dividend) GetAmount() float64 {
+	if x != nil && x.Amount != nil {
+		return *x.Amount
+	}
+	return 0
+}
+
+func (x *Dividend) GetFlag() string {
+	if x != nil && x.Flag != nil {
+		return *x.Flag
+	}
+	return ""
+}
+
+func (x *Dividend) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *Dividend) GetFrequency() string {
+	if x != nil && x.Frequency != nil {
+		return *x.Frequency
+	}
+	return ""
+}
+
+type Split struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeclaredDate *string `protobuf:"bytes,1,opt,name=declared_date,json=declaredDate,proto3,oneof" json:"declared_date,omitempty"`
+	ExDate       string  `protobuf:"bytes,2,opt,name=ex_date,json=exDate,proto3" json:"ex_date,omitempty"`
+	ToFactor     float64 `protobuf:"fixed64,3,opt,name=to_factor,json=toFactor,proto3" json:"to_factor,omitempty"`
+	FromFactor   float64 `protobuf:"fixed64,4,opt,name=from_factor,json=fromFactor,proto3" json:"from_factor,omitempty"`
+}
+
+func (x *Split) Reset() {
+	*x = Split{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Split) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Split) ProtoMessage() {}
+
+func (x *Split) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Split.ProtoReflect.Descriptor instead.
+func (*Split) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Split) GetDeclaredDate() string {
+	if x != nil && x.DeclaredDate != nil {
+		return *x.DeclaredDate
+	}
+	return ""
+}
+
+func (x *Split) GetExDate() string {
+	if x != nil {
+		return x.ExDate
+	}
+	return ""
+}
+
+func (x *Split) GetToFactor() float64 {
+	if x != nil {
+		return x.ToFactor
+	}
+	return 0
+}
+
+func (x *Split) GetFromFactor() float64 {
+	if x != nil {
+		return x.FromFactor
+	}
+	return 0
+}
+
+type IncomeStatement struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReportDate             string `protobuf:"bytes,1,opt,name=report_date,json=reportDate,proto3" json:"report_date,omitempty"`
+	FiscalDate             string `protobuf:"bytes,2,opt,name=fiscal_date,json=fiscalDate,proto3" json:"fiscal_date,omitempty"`
+	Currency               string `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	TotalRevenue           int64  `protobuf:"varint,4,opt,name=total_revenue,json=totalRevenue,proto3" json:"total_revenue,omitempty"`
+	CostOfRevenue          int64  `protobuf:"varint,5,opt,name=cost_of_revenue,json=costOfRevenue,proto3" json:"cost_of_revenue,omitempty"`
+	GrossProfit            int64  `protobuf:"varint,6,opt,name=gross_profit,json=grossProfit,proto3" json:"gross_profit,omitempty"`
+	ResearchAndDevelopment int64  `protobuf:"varint,7,opt,name=research_and_development,json=researchAndDevelopment,proto3" json:"research_and_development,omitempty"`
+	SellingGeneralAndAdmin int64  `protobuf:"varint,8,opt,name=selling_general_and_admin,json=sellingGeneralAndAdmin,proto3" json:"selling_general_and_admin,omitempty"`
+	OperatingExpense       int64  `protobuf:"varint,9,opt,name=operating_expense,json=operatingExpense,proto3" json:"operating_expense,omitempty"`
+	OperatingIncome        int64  `protobuf:"varint,10,opt,name=operating_income,json=operatingIncome,proto3" json:"operating_income,omitempty"`
+	OtherIncomeExpenseNet  int64  `protobuf:"varint,11,opt,name=other_income_expense_net,json=otherIncomeExpenseNet,proto3" json:"other_income_expense_net,omitempty"`
+	Ebit                   int64  `protobuf:"varint,12,opt,name=ebit,proto3" json:"ebit,omitempty"`
+	InterestIncome         int64  `protobuf:"varint,13,opt,name=interest_income,json=interestIncome,proto3" json:"interest_income,omitempty"`
+	PretaxIncome           int64  `protobuf:"varint,14,opt,name=pretax_income,json=pretaxIncome,proto3" json:"pretax_income,omitempty"`
+	IncomeTax              int64  `protobuf:"varint,15,opt,name=income_tax,json=incomeTax,proto3" json:"income_tax,omitempty"`
+	MinorityInterest       int64  `protobuf:"varint,16,opt,name=minority_interest,json=minorityInterest,proto3" json:"minority_interest,omitempty"`
+	NetIncome              int64  `protobuf:"varint,17,opt,name=net_income,json=netIncome,proto3" json:"net_income,omitempty"`
+	NetIncomeBasic         int64  `protobuf:"varint,18,opt,name=net_income_basic,json=netIncomeBasic,proto3" json:"net_income_basic,omitempty"`
+}
+
+func (x *IncomeStatement) Reset() {
+	*x = IncomeStatement{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IncomeStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncomeStatement) ProtoMessage() {}
+
+func (x *IncomeStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncomeStatement.ProtoReflect.Descriptor instead.
+func (*IncomeStatement) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *IncomeStatement) GetReportDate() string {
+	if x != nil {
+		return x.ReportDate
+	}
+	return ""
+}
+
+func (x *IncomeStatement) GetFiscalDate() string {
+	if x != nil {
+		return x.FiscalDate
+	}
+	return ""
+}
+
+func (x *IncomeStatement) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *IncomeStatement) GetTotalRevenue() int64 {
+	if x != nil {
+		return x.TotalRevenue
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetCostOfRevenue() int64 {
+	if x != nil {
+		return x.CostOfRevenue
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetGrossProfit() int64 {
+	if x != nil {
+		return x.GrossProfit
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetResearchAndDevelopment() int64 {
+	if x != nil {
+		return x.ResearchAndDevelopment
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetSellingGeneralAndAdmin() int64 {
+	if x != nil {
+		return x.SellingGeneralAndAdmin
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetOperatingExpense() int64 {
+	if x != nil {
+		return x.OperatingExpense
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetOperatingIncome() int64 {
+	if x != nil {
+		return x.OperatingIncome
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetOtherIncomeExpenseNet() int64 {
+	if x != nil {
+		return x.OtherIncomeExpenseNet
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetEbit() int64 {
+	if x != nil {
+		return x.Ebit
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetInterestIncome() int64 {
+	if x != nil {
+		return x.InterestIncome
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetPretaxIncome() int64 {
+	if x != nil {
+		return x.PretaxIncome
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetIncomeTax() int64 {
+	if x != nil {
+		return x.IncomeTax
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetMinorityInterest() int64 {
+	if x != nil {
+		return x.MinorityInterest
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetNetIncome() int64 {
+	if x != nil {
+		return x.NetIncome
+	}
+	return 0
+}
+
+func (x *IncomeStatement) GetNetIncomeBasic() int64 {
+	if x != nil {
+		return x.NetIncomeBasic
+	}
+	return 0
+}
+
+type BalanceSheet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReportDate              string `protobuf:"bytes,1,opt,name=report_date,json=reportDate,proto3" json:"report_date,omitempty"`
+	FiscalDate              string `protobuf:"bytes,2,opt,name=fiscal_date,json=fiscalDate,proto3" json:"fiscal_date,omitempty"`
+	Currency                string `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	CurrentCash             int64  `protobuf:"varint,4,opt,name=current_cash,json=currentCash,proto3" json:"current_cash,omitempty"`
+	ShortTermInvestments    int64  `protobuf:"varint,5,opt,name=short_term_investments,json=shortTermInvestments,proto3" json:"short_term_investments,omitempty"`
+	Receivables             int64  `protobuf:"varint,6,opt,name=receivables,proto3" json:"receivables,omitempty"`
+	Inventory               int64  `protobuf:"varint,7,opt,name=inventory,proto3" json:"inventory,omitempty"`
+	OtherCurrentAssets      int64  `protobuf:"varint,8,opt,name=other_current_assets,json=otherCurrentAssets,proto3" json:"other_current_assets,omitempty"`
+	CurrentAssets           int64  `protobuf:"varint,9,opt,name=current_assets,json=currentAssets,proto3" json:"current_assets,omitempty"`
+	LongTermInvestments     int64  `protobuf:"varint,10,opt,name=long_term_investments,json=longTermInvestments,proto3" json:"long_term_investments,omitempty"`
+	PropertyPlantEquipment  int64  `protobuf:"varint,11,opt,name=property_plant_equipment,json=propertyPlantEquipment,proto3" json:"property_plant_equipment,omitempty"`
+	Goodwill                *int64 `protobuf:"varint,12,opt,name=goodwill,proto3,oneof" json:"goodwill,omitempty"`
+	IntangibleAssets        *int64 `protobuf:"varint,13,opt,name=intangible_assets,json=intangibleAssets,proto3,oneof" json:"intangible_assets,omitempty"`
+	OtherAssets             int64  `protobuf:"varint,14,opt,name=other_assets,json=otherAssets,proto3" json:"other_assets,omitempty"`
+	TotalAssets             int64  `protobuf:"varint,15,opt,name=total_assets,json=totalAssets,proto3" json:"total_assets,omitempty"`
+	AccountsPayable         int64  `protobuf:"varint,16,opt,name=accounts_payable,json=accountsPayable,proto3" json:"accounts_payable,omitempty"`
+	CurrentLongTermDebt     int64  `protobuf:"varint,17,opt,name=current_long_term_debt,json=currentLongTermDebt,proto3" json:"current_long_term_debt,omitempty"`
+	OtherCurrentLiabilities int64  `protobuf:"varint,18,opt,name=other_current_liabilities,json=otherCurrentLiabilities,proto3" json:"other_current_liabilities,omitempty"`
+	TotalCurrentLiabilities int64  `protobuf:"varint,19,opt,name=total_current_liabilities,json=totalCurrentLiabilities,proto3" json:"total_current_liabilities,omitempty"`
+	LongTermDebt            int64  `protobuf:"varint,20,opt,name=long_term_debt,json=longTermDebt,proto3" json:"long_term_debt,omitempty"`
+	OtherLiabilities        int64  `protobuf:"varint,21,opt,name=other_liabilities,json=otherLiabilities,proto3" json:"other_liabilities,omitempty"`
+	MinorityInterest        int64  `protobuf:"varint,22,opt,name=minority_interest,json=minorityInterest,proto3" json:"minority_interest,omitempty"`
+	TotalLiabilities        int64  `protobuf:"varint,23,opt,name=total_liabilities,json=totalLiabilities,proto3" json:"total_liabilities,omitempty"`
+	CommonStock             int64  `protobuf:"varint,24,opt,name=common_stock,json=commonStock,proto3" json:"common_stock,omitempty"`
+	RetainedEarnings        int64  `protobuf:"varint,25,opt,name=retained_earnings,json=retainedEarnings,proto3" json:"retained_earnings,omitempty"`
+	TreasuryStock           *int64 `protobuf:"varint,26,opt,name=treasury_stock,json=treasuryStock,proto3,oneof" json:"treasury_stock,omitempty"`
+	CapitalSurplus          *int64 `protobuf:"varint,27,opt,name=capital_surplus,json=capitalSurplus,proto3,oneof" json:"capital_surplus,omitempty"`
+	ShareholderEquity       int64  `protobuf:"varint,28,opt,name=shareholder_equity,json=shareholderEquity,proto3" json:"shareholder_equity,omitempty"`
+	NetTangibleAssets       int64  `protobuf:"varint,29,opt,name=net_tangible_assets,json=netTangibleAssets,proto3" json:"net_tangible_assets,omitempty"`
+}
+
+func (x *BalanceSheet) Reset() {
+	*x = BalanceSheet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BalanceSheet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceSheet) ProtoMessage() {}
+
+func (x *BalanceSheet) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceSheet.ProtoReflect.Descriptor instead.
+func (*BalanceSheet) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BalanceSheet) GetReportDate() string {
+	if x != nil {
+		return x.ReportDate
+	}
+	return ""
+}
+
+func (x *BalanceSheet) GetFiscalDate() string {
+	if x != nil {
+		return x.FiscalDate
+	}
+	return ""
+}
+
+func (x *BalanceSheet) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *BalanceSheet) GetCurrentCash() int64 {
+	if x != nil {
+		return x.CurrentCash
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetShortTermInvestments() int64 {
+	if x != nil {
+		return x.ShortTermInvestments
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetReceivables() int64 {
+	if x != nil {
+		return x.Receivables
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetInventory() int64 {
+	if x != nil {
+		return x.Inventory
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetOtherCurrentAssets() int64 {
+	if x != nil {
+		return x.OtherCurrentAssets
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetCurrentAssets() int64 {
+	if x != nil {
+		return x.CurrentAssets
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetLongTermInvestments() int64 {
+	if x != nil {
+		return x.LongTermInvestments
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetPropertyPlantEquipment() int64 {
+	if x != nil {
+		return x.PropertyPlantEquipment
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetGoodwill() int64 {
+	if x != nil && x.Goodwill != nil {
+		return *x.Goodwill
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetIntangibleAssets() int64 {
+	if x != nil && x.IntangibleAssets != nil {
+		return *x.IntangibleAssets
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetOtherAssets() int64 {
+	if x != nil {
+		return x.OtherAssets
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetTotalAssets() int64 {
+	if x != nil {
+		return x.TotalAssets
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetAccountsPayable() int64 {
+	if x != nil {
+		return x.AccountsPayable
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetCurrentLongTermDebt() int64 {
+	if x != nil {
+		return x.CurrentLongTermDebt
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetOtherCurrentLiabilities() int64 {
+	if x != nil {
+		return x.OtherCurrentLiabilities
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetTotalCurrentLiabilities() int64 {
+	if x != nil {
+		return x.TotalCurrentLiabilities
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetLongTermDebt() int64 {
+	if x != nil {
+		return x.LongTermDebt
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetOtherLiabilities() int64 {
+	if x != nil {
+		return x.OtherLiabilities
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetMinorityInterest() int64 {
+	if x != nil {
+		return x.MinorityInterest
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetTotalLiabilities() int64 {
+	if x != nil {
+		return x.TotalLiabilities
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetCommonStock() int64 {
+	if x != nil {
+		return x.CommonStock
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetRetainedEarnings() int64 {
+	if x != nil {
+		return x.RetainedEarnings
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetTreasuryStock() int64 {
+	if x != nil && x.TreasuryStock != nil {
+		return *x.TreasuryStock
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetCapitalSurplus() int64 {
+	if x != nil && x.CapitalSurplus != nil {
+		return *x.CapitalSurplus
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetShareholderEquity() int64 {
+	if x != nil {
+		return x.ShareholderEquity
+	}
+	return 0
+}
+
+func (x *BalanceSheet) GetNetTangibleAssets() int64 {
+	if x != nil {
+		return x.NetTangibleAssets
+	}
+	return 0
+}
+
+type CashFlow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReportDate              string `protobuf:"bytes,1,opt,name=report_date,json=reportDate,proto3" json:"report_date,omitempty"`
+	FiscalDate              string `protobuf:"bytes,2,opt,name=fiscal_date,json=fiscalDate,proto3" json:"fiscal_date,omitempty"`
+	Currency                string `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	NetIncome               int64  `protobuf:"varint,4,opt,name=net_income,json=netIncome,proto3" json:"net_income,omitempty"`
+	Depreciation            int64  `protobuf:"varint,5,opt,name=depreciation,proto3" json:"depreciation,omitempty"`
+	ChangesInReceivables    int64  `protobuf:"varint,6,opt,name=changes_in_receivables,json=changesInReceivables,proto3" json:"changes_in_receivables,omitempty"`
+	ChangesInInventories    int64  `protobuf:"varint,7,opt,name=changes_in_inventories,json=changesInInventories,proto3" json:"changes_in_inventories,omitempty"`
+	CashChange              int64  `protobuf:"varint,8,opt,name=cash_change,json=cashChange,proto3" json:"cash_change,omitempty"`
+	CashFlow                int64  `protobuf:"varint,9,opt,name=cash_flow,json=cashFlow,proto3" json:"cash_flow,omitempty"`
+	CapitalExpenditures     int64  `protobuf:"varint,10,opt,name=capital_expenditures,json=capitalExpenditures,proto3" json:"capital_expenditures,omitempty"`
+	Investments             int64  `protobuf:"varint,11,opt,name=investments,proto3" json:"investments,omitempty"`
+	InvestingActivityOther  int64  `protobuf:"varint,12,opt,name=investing_activity_other,json=investingActivityOther,proto3" json:"investing_activity_other,omitempty"`
+	TotalInvestingCashFlows int64  `protobuf:"varint,13,opt,name=total_investing_cash_flows,json=totalInvestingCashFlows,proto3" json:"total_investing_cash_flows,omitempty"`
+	DividendsPaid           int64  `protobuf:"varint,14,opt,name=dividends_paid,json=dividendsPaid,proto3" json:"dividends_paid,omitempty"`
+	NetBorrowings           int64  `protobuf:"varint,15,opt,name=net_borrowings,json=netBorrowings,proto3" json:"net_borrowings,omitempty"`
+	OtherFinancingCashFlows int64  `protobuf:"varint,16,opt,name=other_financing_cash_flows,json=otherFinancingCashFlows,proto3" json:"other_financing_cash_flows,omitempty"`
+	CashFlowFinancing       int64  `protobuf:"varint,17,opt,name=cash_flow_financing,json=cashFlowFinancing,proto3" json:"cash_flow_financing,omitempty"`
+	ExchangeRateEffect      *int64 `protobuf:"varint,18,opt,name=exchange_rate_effect,json=exchangeRateEffect,proto3,oneof" json:"exchange_rate_effect,omitempty"`
+}
+
+func (x *CashFlow) Reset() {
+	*x = CashFlow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_defcor_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CashFlow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CashFlow) ProtoMessage() {}
+
+func (x *CashFlow) ProtoReflect() protoreflect.Message {
+	mi := &file_defcor_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CashFlow.ProtoReflect.Descriptor instead.
+func (*CashFlow) Descriptor() ([]byte, []int) {
+	return file_defcor_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CashFlow) GetReportDate() string {
+	if x != nil {
+		return x.ReportDate
+	}
+	return ""
+}
+
+func (x *CashFlow) GetFiscalDate() string {
+	if x != nil {
+		return x.FiscalDate
+	}
+	return ""
+}
+
+func (x *CashFlow) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CashFlow) GetNetIncome() int64 {
+	if x != nil {
+		return x.NetIncome
+	}
+	return 0
+}
+
+func (x *CashFlow) GetDepreciation() int64 {
+	if x != nil {
+		return x.Depreciation
+	}
+	return 0
+}
+
+func (x *CashFlow) GetChangesInReceivables() int64 {
+	if x != nil {
+		return x.ChangesInReceivables
+	}
+	return 0
+}
+
+func (x *CashFlow) GetChangesInInventories() int64 {
+	if x != nil {
+		return x.ChangesInInventories
+	}
+	return 0
+}
+
+func (x *CashFlow) GetCashChange() int64 {
+	if x != nil {
+		return x.CashChange
+	}
+	return 0
+}
+
+func (x *CashFlow) GetCashFlow() int64 {
+	if x != nil {
+		return x.CashFlow
+	}
+	return 0
+}
+
+func (x *CashFlow) GetCapitalExpenditures() int64 {
+	if x != nil {
+		return x.CapitalExpenditures
+	}
+	return 0
+}
+
+func (x *CashFlow) GetInvestments() int64 {
+	if x != nil {
+		return x.Investments
+	}
+	return 0
+}
+
+func (x *CashFlow) GetInvestingActivityOther() int64 {
+	if x != nil {
+		return x.InvestingActivityOther
+	}
+	return 0
+}
+
+func (x *CashFlow) GetTotalInvestingCashFlows() int64 {
+	if x != nil {
+		return x.TotalInvestingCashFlows
+	}
+	return 0
+}
+
+func (x *CashFlow) GetDividendsPaid() int64 {
+	if x != nil {
+		return x.DividendsPaid
+	}
+	return 0
+}
+
+func (x *CashFlow) GetNetBorrowings() int64 {
+	if x != nil {
+		return x.NetBorrowings
+	}
+	return 0
+}
+
+func (x *CashFlow) GetOtherFinancingCashFlows() int64 {
+	if x != nil {
+		return x.OtherFinancingCashFlows
+	}
+	return 0
+}
+
+func (x *CashFlow) GetCashFlowFinancing() int64 {
+	if x != nil {
+		return x.CashFlowFinancing
+	}
+	return 0
+}
+
+func (x *CashFlow) GetExchangeRateEffect() int64 {
+	if x != nil && x.ExchangeRateEffect != nil {
+		return *x.ExchangeRateEffect
+	}
+	return 0
+}
+
+var File_defcor_proto protoreflect.FileDescriptor
+
+var file_defcor_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09,
+	0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x22, 0x13, 0x0a, 0x11, 0x4c, 0x69, 0x73,
+	0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x29,
+	0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x22, 0x27, 0x0a, 0x0d, 0x53, 0x79, 0x6d,
+	0x62, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79,
+	0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62,
+	0x6f, 0x6c, 0x22, 0x88, 0x01, 0x0a, 0x11, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x42, 0x61, 0x72,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x79, 0x6d, 0x62,
+	0x6f, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x79, 0x6d, 0x62, 0x6f,
+	0x6c, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x35, 0x0a, 0x0a, 0x61, 0x64, 0x6a, 0x75, 0x73, 0x74,
+	0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x64, 0x65, 0x66,
+	0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x0a, 0x61, 0x64, 0x6a, 0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0xb8, 0x01,
+	0x0a, 0x05, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x69, 0x65, 0x78, 0x5f, 0x69,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x65, 0x78, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x67, 0x69, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x66, 0x69, 0x67, 0x69, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x6b, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x03, 0x63, 0x69, 0x6b, 0x22, 0x99, 0x01, 0x0a, 0x03, 0x42, 0x61, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6f, 0x70, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6f, 0x70, 0x65, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x67, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04,
+	0x68, 0x69, 0x67, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x6c, 0x6f, 0x77, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x03, 0x6c, 0x6f, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x63, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x76, 0x6f,
+	0x6c, 0x75, 0x6d, 0x65, 0x22, 0xf7, 0x02, 0x0a, 0x08, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e,
+	0x64, 0x12, 0x28, 0x0a, 0x0d, 0x64, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x64, 0x61,
+	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x64, 0x65, 0x63, 0x6c,
+	0x61, 0x72, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x07, 0x65,
+	0x78, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x78,
+	0x44, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0a, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x44, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x02, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x01, 0x48, 0x03, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x17, 0x0a, 0x04, 0x66, 0x6c, 0x61, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52,
+	0x04, 0x66, 0x6c, 0x61, 0x67, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48, 0x05, 0x52, 0x08, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x66, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x48, 0x06, 0x52, 0x09,
+	0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e,
+	0x5f, 0x64, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x42, 0x0e,
+	0x0a, 0x0c, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x42, 0x0f,
+	0x0a, 0x0d, 0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x66,
+	0x6c, 0x61, 0x67, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x9a,
+	0x01, 0x0a, 0x05, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x12, 0x28, 0x0a, 0x0d, 0x64, 0x65, 0x63, 0x6c,
+	0x61, 0x72, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x0c, 0x64, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x17, 0x0a, 0x07, 0x65, 0x78, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x78, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74,
+	0x6f, 0x5f, 0x66, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08,
+	0x74, 0x6f, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x72, 0x6f, 0x6d,
+	0x5f, 0x66, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x66,
+	0x72, 0x6f, 0x6d, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x64, 0x65,
+	0x63, 0x6c, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x22, 0xdc, 0x05, 0x0a, 0x0f,
+	0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12,
+	0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x73, 0x63, 0x61, 0x6c, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x69, 0x73, 0x63, 0x61, 0x6c, 0x44, 0x61, 0x74,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x23, 0x0a,
+	0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x6e, 0x75, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x52, 0x65, 0x76, 0x65, 0x6e,
+	0x75, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x63, 0x6f, 0x73, 0x74, 0x5f, 0x6f, 0x66, 0x5f, 0x72, 0x65,
+	0x76, 0x65, 0x6e, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x63, 0x6f, 0x73,
+	0x74, 0x4f, 0x66, 0x52, 0x65, 0x76, 0x65, 0x6e, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x67, 0x72,
+	0x6f, 0x73, 0x73, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0b, 0x67, 0x72, 0x6f, 0x73, 0x73, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x74, 0x12, 0x38, 0x0a,
+	0x18, 0x72, 0x65, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x61, 0x6e, 0x64, 0x5f, 0x64, 0x65,
+	0x76, 0x65, 0x6c, 0x6f, 0x70, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x16, 0x72, 0x65, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x41, 0x6e, 0x64, 0x44, 0x65, 0x76, 0x65,
+	0x6c, 0x6f, 0x70, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x39, 0x0a, 0x19, 0x73, 0x65, 0x6c, 0x6c, 0x69,
+	0x6e, 0x67, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x5f, 0x61, 0x6e, 0x64, 0x5f, 0x61,
+	0x64, 0x6d, 0x69, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x73, 0x65, 0x6c, 0x6c,
+	0x69, 0x6e, 0x67, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x6c, 0x41, 0x6e, 0x64, 0x41, 0x64, 0x6d,
+	0x69, 0x6e, 0x12, 0x2b, 0x0a, 0x11, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f,
+	0x65, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x45, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65, 0x12,
+	0x29, 0x0a, 0x10, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x69, 0x6e, 0x63,
+	0x6f, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x37, 0x0a, 0x18, 0x6f, 0x74,
+	0x68, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x65, 0x6e,
+	0x73, 0x65, 0x5f, 0x6e, 0x65, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x6f, 0x74,
+	0x68, 0x65, 0x72, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x45, 0x78, 0x70, 0x65, 0x6e, 0x73, 0x65,
+	0x4e, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x65, 0x62, 0x69, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x04, 0x65, 0x62, 0x69, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x65, 0x73, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65,
+	0x12, 0x23, 0x0a, 0x0d, 0x70, 0x72, 0x65, 0x74, 0x61, 0x78, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d,
+	0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x70, 0x72, 0x65, 0x74, 0x61, 0x78, 0x49,
+	0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x5f,
+	0x74, 0x61, 0x78, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x69, 0x6e, 0x63, 0x6f, 0x6d,
+	0x65, 0x54, 0x61, 0x78, 0x12, 0x2b, 0x0a, 0x11, 0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x69, 0x74, 0x79,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x10, 0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73,
+	0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x65, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65,
+	0x12, 0x28, 0x0a, 0x10, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x5f, 0x62,
+	0x61, 0x73, 0x69, 0x63, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x6e, 0x65, 0x74, 0x49,
+	0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x42, 0x61, 0x73, 0x69, 0x63, 0x22, 0xbd, 0x0a, 0x0a, 0x0c, 0x42,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x68, 0x65, 0x65, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x72,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
+	0x66, 0x69, 0x73, 0x63, 0x61, 0x6c, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x66, 0x69, 0x73, 0x63, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x43, 0x61, 0x73, 0x68, 0x12, 0x34, 0x0a, 0x16,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x5f, 0x69, 0x6e, 0x76, 0x65, 0x73,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x73, 0x68,
+	0x6f, 0x72, 0x74, 0x54, 0x65, 0x72, 0x6d, 0x49, 0x6e, 0x76, 0x65, 0x73, 0x74, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x61, 0x62, 0x6c, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x61,
+	0x62, 0x6c, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72,
+	0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f,
+	0x72, 0x79, 0x12, 0x30, 0x0a, 0x14, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x12, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x6c,
+	0x6f, 0x6e, 0x67, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x5f, 0x69, 0x6e, 0x76, 0x65, 0x73, 0x74, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x6c, 0x6f, 0x6e, 0x67,
+	0x54, 0x65, 0x72, 0x6d, 0x49, 0x6e, 0x76, 0x65, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x38, 0x0a, 0x18, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x5f, 0x70, 0x6c, 0x61, 0x6e,
+	0x74, 0x5f, 0x65, 0x71, 0x75, 0x69, 0x70, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x16, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x50, 0x6c, 0x61, 0x6e, 0x74,
+	0x45, 0x71, 0x75, 0x69, 0x70, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x08, 0x67, 0x6f, 0x6f,
+	0x64, 0x77, 0x69, 0x6c, 0x6c, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x08, 0x67,
+	0x6f, 0x6f, 0x64, 0x77, 0x69, 0x6c, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x30, 0x0a, 0x11, 0x69, 0x6e,
+	0x74, 0x61, 0x6e, 0x67, 0x69, 0x62, 0x6c, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x03, 0x48, 0x01, 0x52, 0x10, 0x69, 0x6e, 0x74, 0x61, 0x6e, 0x67, 0x69,
+	0x62, 0x6c, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x0c,
+	0x6f, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12,
+	0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x5f, 0x70,
+	0x61, 0x79, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x61, 0x63,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x50, 0x61, 0x79, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x33, 0x0a,
+	0x16, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x6c, 0x6f, 0x6e, 0x67, 0x5f, 0x74, 0x65,
+	0x72, 0x6d, 0x5f, 0x64, 0x65, 0x62, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x6e, 0x67, 0x54, 0x65, 0x72, 0x6d, 0x44, 0x65,
+	0x62, 0x74, 0x12, 0x3a, 0x0a, 0x19, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x5f, 0x6c, 0x69, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18,
+	0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x17, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x43, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x74, 0x4c, 0x69, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x3a,
+	0x0a, 0x19, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f,
+	0x6c, 0x69, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x17, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x4c,
+	0x69, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x6f,
+	0x6e, 0x67, 0x5f, 0x74, 0x65, 0x72, 0x6d, 0x5f, 0x64, 0x65, 0x62, 0x74, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x6c, 0x6f, 0x6e, 0x67, 0x54, 0x65, 0x72, 0x6d, 0x44, 0x65, 0x62, 0x74,
+	0x12, 0x2b, 0x0a, 0x11, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x6c, 0x69, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6f, 0x74, 0x68,
+	0x65, 0x72, 0x4c, 0x69, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x2b, 0x0a,
+	0x11, 0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x65,
+	0x73, 0x74, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x6d, 0x69, 0x6e, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x5f, 0x6c, 0x69, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18,
+	0x17, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x4c, 0x69, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
+	0x6e, 0x5f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x18, 0x18, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63,
+	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x12, 0x2b, 0x0a, 0x11, 0x72, 0x65,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x64, 0x5f, 0x65, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x18,
+	0x19, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x72, 0x65, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x64, 0x45,
+	0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x2a, 0x0a, 0x0e, 0x74, 0x72, 0x65, 0x61, 0x73,
+	0x75, 0x72, 0x79, 0x5f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x02, 0x52, 0x0d, 0x74, 0x72, 0x65, 0x61, 0x73, 0x75, 0x72, 0x79, 0x53, 0x74, 0x6f, 0x63, 0x6b,
+	0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x63, 0x61, 0x70, 0x69, 0x74, 0x61, 0x6c, 0x5f, 0x73,
+	0x75, 0x72, 0x70, 0x6c, 0x75, 0x73, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x03, 0x48, 0x03, 0x52, 0x0e,
+	0x63, 0x61, 0x70, 0x69, 0x74, 0x61, 0x6c, 0x53, 0x75, 0x72, 0x70, 0x6c, 0x75, 0x73, 0x88, 0x01,
+	0x01, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x68, 0x61, 0x72, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72,
+	0x5f, 0x65, 0x71, 0x75, 0x69, 0x74, 0x79, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x45, 0x71, 0x75, 0x69, 0x74, 0x79,
+	0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x65, 0x74, 0x5f, 0x74, 0x61, 0x6e, 0x67, 0x69, 0x62, 0x6c, 0x65,
+	0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x6e,
+	0x65, 0x74, 0x54, 0x61, 0x6e, 0x67, 0x69, 0x62, 0x6c, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x67, 0x6f, 0x6f, 0x64, 0x77, 0x69, 0x6c, 0x6c, 0x42, 0x14, 0x0a,
+	0x12, 0x5f, 0x69, 0x6e, 0x74, 0x61, 0x6e, 0x67, 0x69, 0x62, 0x6c, 0x65, 0x5f, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x73, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x74, 0x72, 0x65, 0x61, 0x73, 0x75, 0x72, 0x79,
+	0x5f, 0x73, 0x74, 0x6f, 0x63, 0x6b, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x63, 0x61, 0x70, 0x69, 0x74,
+	0x61, 0x6c, 0x5f, 0x73, 0x75, 0x72, 0x70, 0x6c, 0x75, 0x73, 0x22, 0xac, 0x06, 0x0a, 0x08, 0x43,
+	0x61, 0x73, 0x68, 0x46, 0x6c, 0x6f, 0x77, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6f, 0x72,
+	0x74, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x73, 0x63,
+	0x61, 0x6c, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66,
+	0x69, 0x73, 0x63, 0x61, 0x6c, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x6e, 0x63,
+	0x6f, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6e, 0x65, 0x74, 0x49, 0x6e,
+	0x63, 0x6f, 0x6d, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x64, 0x65, 0x70, 0x72, 0x65, 0x63, 0x69, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x64, 0x65, 0x70, 0x72,
+	0x65, 0x63, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x34, 0x0a, 0x16, 0x63, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x61, 0x62, 0x6c,
+	0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65,
+	0x73, 0x49, 0x6e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x61, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x34,
+	0x0a, 0x16, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x5f, 0x69, 0x6e, 0x76,
+	0x65, 0x6e, 0x74, 0x6f, 0x72, 0x69, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x49, 0x6e, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f,
+	0x72, 0x69, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x61, 0x73, 0x68, 0x5f, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x63, 0x61, 0x73, 0x68, 0x43,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x61, 0x73, 0x68, 0x5f, 0x66, 0x6c,
+	0x6f, 0x77, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x63, 0x61, 0x73, 0x68, 0x46, 0x6c,
+	0x6f, 0x77, 0x12, 0x31, 0x0a, 0x14, 0x63, 0x61, 0x70, 0x69, 0x74, 0x61, 0x6c, 0x5f, 0x65, 0x78,
+	0x70, 0x65, 0x6e, 0x64, 0x69, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x13, 0x63, 0x61, 0x70, 0x69, 0x74, 0x61, 0x6c, 0x45, 0x78, 0x70, 0x65, 0x6e, 0x64, 0x69,
+	0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x76, 0x65, 0x73, 0x74, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x69, 0x6e, 0x76, 0x65,
+	0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x38, 0x0a, 0x18, 0x69, 0x6e, 0x76, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x5f, 0x6f, 0x74,
+	0x68, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x69, 0x6e, 0x76, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x74, 0x69, 0x76, 0x69, 0x74, 0x79, 0x4f, 0x74, 0x68, 0x65,
+	0x72, 0x12, 0x3b, 0x0a, 0x1a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x76, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x61, 0x73, 0x68, 0x5f, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x17, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x49, 0x6e, 0x76, 0x65,
+	0x73, 0x74, 0x69, 0x6e, 0x67, 0x43, 0x61, 0x73, 0x68, 0x46, 0x6c, 0x6f, 0x77, 0x73, 0x12, 0x25,
+	0x0a, 0x0e, 0x64, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x73, 0x5f, 0x70, 0x61, 0x69, 0x64,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x64, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64,
+	0x73, 0x50, 0x61, 0x69, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x6e, 0x65, 0x74, 0x5f, 0x62, 0x6f, 0x72,
+	0x72, 0x6f, 0x77, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x6e,
+	0x65, 0x74, 0x42, 0x6f, 0x72, 0x72, 0x6f, 0x77, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x3b, 0x0a, 0x1a,
+	0x6f, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x69, 0x6e, 0x67, 0x5f,
+	0x63, 0x61, 0x73, 0x68, 0x5f, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x17, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x46, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x69, 0x6e, 0x67,
+	0x43, 0x61, 0x73, 0x68, 0x46, 0x6c, 0x6f, 0x77, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x63, 0x61, 0x73,
+	0x68, 0x5f, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x66, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x69, 0x6e, 0x67,
+	0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x63, 0x61, 0x73, 0x68, 0x46, 0x6c, 0x6f, 0x77,
+	0x46, 0x69, 0x6e, 0x61, 0x6e, 0x63, 0x69, 0x6e, 0x67, 0x12, 0x35, 0x0a, 0x14, 0x65, 0x78, 0x63,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x65, 0x66, 0x66, 0x65, 0x63,
+	0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x12, 0x65, 0x78, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x61, 0x74, 0x65, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x88, 0x01, 0x01,
+	0x42, 0x17, 0x0a, 0x15, 0x5f, 0x65, 0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x72, 0x61,
+	0x74, 0x65, 0x5f, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x2a, 0x60, 0x0a, 0x0a, 0x41, 0x64, 0x6a,
+	0x75, 0x73, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x44, 0x4a, 0x55, 0x53,
+	0x54, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x49, 0x45, 0x58, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x41,
+	0x44, 0x4a, 0x55, 0x53, 0x54, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x52, 0x41, 0x57, 0x10, 0x01, 0x12,
+	0x14, 0x0a, 0x10, 0x41, 0x44, 0x4a, 0x55, 0x53, 0x54, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x50,
+	0x4c, 0x49, 0x54, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x44, 0x4a, 0x55, 0x53, 0x54, 0x4d,
+	0x45, 0x4e, 0x54, 0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c, 0x10, 0x03, 0x32, 0x9a, 0x04, 0x0a, 0x06,
+	0x44, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x12, 0x3e, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74,
+	0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1c, 0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x10, 0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x6f, 0x63, 0x6b, 0x30, 0x01, 0x12, 0x38, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x6f,
+	0x63, 0x6b, 0x12, 0x1a, 0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x53, 0x74, 0x6f, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10,
+	0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x6f, 0x63, 0x6b,
+	0x12, 0x3c, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x42, 0x61, 0x72, 0x73, 0x12, 0x1c,
+	0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x42, 0x61, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x64,
+	0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x72, 0x30, 0x01, 0x12, 0x40,
+	0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x73, 0x12,
+	0x18, 0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6d, 0x62,
+	0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x64, 0x65, 0x66, 0x63,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x76, 0x69, 0x64, 0x65, 0x6e, 0x64, 0x30, 0x01,
+	0x12, 0x3a, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x73, 0x12, 0x18,
+	0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6d, 0x62, 0x6f,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x14,
+	0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x18, 0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x63, 0x6f, 0x6d,
+	0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x48, 0x0a, 0x11,
+	0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x68, 0x65, 0x65, 0x74,
+	0x73, 0x12, 0x18, 0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79,
+	0x6d, 0x62, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x64, 0x65,
+	0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53,
+	0x68, 0x65, 0x65, 0x74, 0x30, 0x01, 0x12, 0x40, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61,
+	0x73, 0x68, 0x46, 0x6c, 0x6f, 0x77, 0x73, 0x12, 0x18, 0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x13, 0x2e, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61,
+	0x73, 0x68, 0x46, 0x6c, 0x6f, 0x77, 0x30, 0x01, 0x42, 0x15, 0x5a, 0x13, 0x64, 0x65, 0x66, 0x63,
+	0x6f, 0x72, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x64, 0x65, 0x66, 0x63, 0x6f, 0x72, 0x70, 0x62, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_defcor_proto_rawDescOnce sync.Once
+	file_defcor_proto_rawDescData = file_defcor_proto_rawDesc
+)
+
+func file_defcor_proto_rawDescGZIP() []byte {
+	file_defcor_proto_rawDescOnce.Do(func() {
+		file_defcor_proto_rawDescData = protoimpl.X.CompressGZIP(file_defcor_proto_rawDescData)
+	})
+	return file_defcor_proto_rawDescData
+}
+
+var file_defcor_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_defcor_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_defcor_proto_goTypes = []interface{}{
+	(Adjustment)(0),           // 0: defcor.v1.Adjustment
+	(*ListStocksRequest)(nil), // 1: defcor.v1.ListStocksRequest
+	(*GetStockRequest)(nil),   // 2: defcor.v1.GetStockRequest
+	(*SymbolRequest)(nil),     // 3: defcor.v1.SymbolRequest
+	(*StreamBarsRequest)(nil), // 4: defcor.v1.StreamBarsRequest
+	(*Stock)(nil),             // 5: defcor.v1.Stock
+	(*Bar)(nil),               // 6: defcor.v1.Bar
+	(*Dividend)(nil),          // 7: defcor.v1.Dividend
+	(*Split)(nil),             // 8: defcor.v1.Split
+	(*IncomeStatement)(nil),   // 9: defcor.v1.IncomeStatement
+	(*BalanceSheet)(nil),      // 10: defcor.v1.BalanceSheet
+	(*CashFlow)(nil),          // 11: defcor.v1.CashFlow
+}
+var file_defcor_proto_depIdxs = []int32{
+	0,  // 0: defcor.v1.StreamBarsRequest.adjustment:type_name -> defcor.v1.Adjustment
+	1,  // 1: defcor.v1.Defcor.ListStocks:input_type -> defcor.v1.ListStocksRequest
+	2,  // 2: defcor.v1.Defcor.GetStock:input_type -> defcor.v1.GetStockRequest
+	4,  // 3: defcor.v1.Defcor.StreamBars:input_type -> defcor.v1.StreamBarsRequest
+	3,  // 4: defcor.v1.Defcor.ListDividends:input_type -> defcor.v1.SymbolRequest
+	3,  // 5: defcor.v1.Defcor.ListSplits:input_type -> defcor.v1.SymbolRequest
+	3,  // 6: defcor.v1.Defcor.ListIncomeStatements:input_type -> defcor.v1.SymbolRequest
+	3,  // 7: defcor.v1.Defcor.ListBalanceSheets:input_type -> defcor.v1.SymbolRequest
+	3,  // 8: defcor.v1.Defcor.ListCashFlows:input_type -> defcor.v1.SymbolRequest
+	5,  // 9: defcor.v1.Defcor.ListStocks:output_type -> defcor.v1.Stock
+	5,  // 10: defcor.v1.Defcor.GetStock:output_type -> defcor.v1.Stock
+	6,  // 11: defcor.v1.Defcor.StreamBars:output_type -> defcor.v1.Bar
+	7,  // 12: defcor.v1.Defcor.ListDividends:output_type -> defcor.v1.Dividend
+	8,  // 13: defcor.v1.Defcor.ListSplits:output_type -> defcor.v1.Split
+	9,  // 14: defcor.v1.Defcor.ListIncomeStatements:output_type -> defcor.v1.IncomeStatement
+	10, // 15: defcor.v1.Defcor.ListBalanceSheets:output_type -> defcor.v1.BalanceSheet
+	11, // 16: defcor.v1.Defcor.ListCashFlows:output_type -> defcor.v1.CashFlow
+	9,  // [9:17] is the sub-list for method output_type
+	1,  // [1:9] is the sub-list for method input_type
+	1,  // [1:1] is the sub-list for extension type_name
+	1,  // [1:1] is the sub-list for extension extendee
+	0,  // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_defcor_proto_init() }
+func file_defcor_proto_init() {
+	if File_defcor_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_defcor_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListStocksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetStockRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SymbolRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamBarsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Stock); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Bar); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Dividend); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Split); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IncomeStatement); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BalanceSheet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_defcor_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CashFlow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_defcor_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_defcor_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	file_defcor_proto_msgTypes[9].OneofWrappers = []interface{}{}
+	file_defcor_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_defcor_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_defcor_proto_goTypes,
+		DependencyIndexes: file_defcor_proto_depIdxs,
+		EnumInfos:         file_defcor_proto_enumTypes,
+		MessageInfos:      file_defcor_proto_msgTypes,
+	}.Build()
+	File_defcor_proto = out.File
+	file_defcor_proto_rawDesc = nil
+	file_defcor_proto_goTypes = nil
+	file_defcor_proto_depIdxs = nil
+}
@@ -0,0 +1,553 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package defcorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DefcorClient is the client API for Defcor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DefcorClient interface {
+	// ListStocks streams every stock ordered by symbol
+	ListStocks(ctx context.Context, in *ListStocksRequest, opts ...grpc.CallOption) (Defcor_ListStocksClient, error)
+	// GetStock returns one stock, NOT_FOUND for an unknown symbol
+	GetStock(ctx context.Context, in *GetStockRequest, opts ...grpc.CallOption) (*Stock, error)
+	// StreamBars streams the daily bars of every requested symbol, ordered by
+	// symbol then date, as they are read from the database
+	StreamBars(ctx context.Context, in *StreamBarsRequest, opts ...grpc.CallOption) (Defcor_StreamBarsClient, error)
+	ListDividends(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListDividendsClient, error)
+	ListSplits(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListSplitsClient, error)
+	// statements stream latest first
+	ListIncomeStatements(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListIncomeStatementsClient, error)
+	ListBalanceSheets(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListBalanceSheetsClient, error)
+	ListCashFlows(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListCashFlowsClient, error)
+}
+
+type defcorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDefcorClient(cc grpc.ClientConnInterface) DefcorClient {
+	return &defcorClient{cc}
+}
+
+func (c *defcorClient) ListStocks(ctx context.Context, in *ListStocksRequest, opts ...grpc.CallOption) (Defcor_ListStocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Defcor_ServiceDesc.Streams[0], "/defcor.v1.Defcor/ListStocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &defcorListStocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Defcor_ListStocksClient interface {
+	Recv() (*Stock, error)
+	grpc.ClientStream
+}
+
+type defcorListStocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *defcorListStocksClient) Recv() (*Stock, error) {
+	m := new(Stock)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *defcorClient) GetStock(ctx context.Context, in *GetStockRequest, opts ...grpc.CallOption) (*Stock, error) {
+	out := new(Stock)
+	err := c.cc.Invoke(ctx, "/defcor.v1.Defcor/GetStock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *defcorClient) StreamBars(ctx context.Context, in *StreamBarsRequest, opts ...grpc.CallOption) (Defcor_StreamBarsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Defcor_ServiceDesc.Streams[1], "/defcor.v1.Defcor/StreamBars", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &defcorStreamBarsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Defcor_StreamBarsClient interface {
+	Recv() (*Bar, error)
+	grpc.ClientStream
+}
+
+type defcorStreamBarsClient struct {
+	grpc.ClientStream
+}
+
+func (x *defcorStreamBarsClient) Recv() (*Bar, error) {
+	m := new(Bar)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *defcorClient) ListDividends(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListDividendsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Defcor_ServiceDesc.Streams[2], "/defcor.v1.Defcor/ListDividends", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &defcorListDividendsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Defcor_ListDividendsClient interface {
+	Recv() (*Dividend, error)
+	grpc.ClientStream
+}
+
+type defcorListDividendsClient struct {
+	grpc.ClientStream
+}
+
+func (x *defcorListDividendsClient) Recv() (*Dividend, error) {
+	m := new(Dividend)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *defcorClient) ListSplits(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListSplitsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Defcor_ServiceDesc.Streams[3], "/defcor.v1.Defcor/ListSplits", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &defcorListSplitsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Defcor_ListSplitsClient interface {
+	Recv() (*Split, error)
+	grpc.ClientStream
+}
+
+type defcorListSplitsClient struct {
+	grpc.ClientStream
+}
+
+func (x *defcorListSplitsClient) Recv() (*Split, error) {
+	m := new(Split)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *defcorClient) ListIncomeStatements(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListIncomeStatementsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Defcor_ServiceDesc.Streams[4], "/defcor.v1.Defcor/ListIncomeStatements", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &defcorListIncomeStatementsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Defcor_ListIncomeStatementsClient interface {
+	Recv() (*IncomeStatement, error)
+	grpc.ClientStream
+}
+
+type defcorListIncomeStatementsClient struct {
+	grpc.ClientStream
+}
+
+func (x *defcorListIncomeStatementsClient) Recv() (*IncomeStatement, error) {
+	m := new(IncomeStatement)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *defcorClient) ListBalanceSheets(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListBalanceSheetsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Defcor_ServiceDesc.Streams[5], "/defcor.v1.Defcor/ListBalanceSheets", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &defcorListBalanceSheetsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Defcor_ListBalanceSheetsClient interface {
+	Recv() (*BalanceSheet, error)
+	grpc.ClientStream
+}
+
+type defcorListBalanceSheetsClient struct {
+	grpc.ClientStream
+}
+
+func (x *defcorListBalanceSheetsClient) Recv() (*BalanceSheet, error) {
+	m := new(BalanceSheet)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *defcorClient) ListCashFlows(ctx context.Context, in *SymbolRequest, opts ...grpc.CallOption) (Defcor_ListCashFlowsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Defcor_ServiceDesc.Streams[6], "/defcor.v1.Defcor/ListCashFlows", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &defcorListCashFlowsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Defcor_ListCashFlowsClient interface {
+	Recv() (*CashFlow, error)
+	grpc.ClientStream
+}
+
+type defcorListCashFlowsClient struct {
+	grpc.ClientStream
+}
+
+func (x *defcorListCashFlowsClient) Recv() (*CashFlow, error) {
+	m := new(CashFlow)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DefcorServer is the server API for Defcor service.
+// All implementations must embed UnimplementedDefcorServer
+// for forward compatibility
+type DefcorServer interface {
+	// ListStocks streams every stock ordered by symbol
+	ListStocks(*ListStocksRequest, Defcor_ListStocksServer) error
+	// GetStock returns one stock, NOT_FOUND for an unknown symbol
+	GetStock(context.Context, *GetStockRequest) (*Stock, error)
+	// StreamBars streams the daily bars of every requested symbol, ordered by
+	// symbol then date, as they are read from the database
+	StreamBars(*StreamBarsRequest, Defcor_StreamBarsServer) error
+	ListDividends(*SymbolRequest, Defcor_ListDividendsServer) error
+	ListSplits(*SymbolRequest, Defcor_ListSplitsServer) error
+	// statements stream latest first
+	ListIncomeStatements(*SymbolRequest, Defcor_ListIncomeStatementsServer) error
+	ListBalanceSheets(*SymbolRequest, Defcor_ListBalanceSheetsServer) error
+	ListCashFlows(*SymbolRequest, Defcor_ListCashFlowsServer) error
+	mustEmbedUnimplementedDefcorServer()
+}
+
+// UnimplementedDefcorServer must be embedded to have forward compatible implementations.
+type UnimplementedDefcorServer struct {
+}
+
+func (UnimplementedDefcorServer) ListStocks(*ListStocksRequest, Defcor_ListStocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListStocks not implemented")
+}
+func (UnimplementedDefcorServer) GetStock(context.Context, *GetStockRequest) (*Stock, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStock not implemented")
+}
+func (UnimplementedDefcorServer) StreamBars(*StreamBarsRequest, Defcor_StreamBarsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBars not implemented")
+}
+func (UnimplementedDefcorServer) ListDividends(*SymbolRequest, Defcor_ListDividendsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListDividends not implemented")
+}
+func (UnimplementedDefcorServer) ListSplits(*SymbolRequest, Defcor_ListSplitsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListSplits not implemented")
+}
+func (UnimplementedDefcorServer) ListIncomeStatements(*SymbolRequest, Defcor_ListIncomeStatementsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListIncomeStatements not implemented")
+}
+func (UnimplementedDefcorServer) ListBalanceSheets(*SymbolRequest, Defcor_ListBalanceSheetsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBalanceSheets not implemented")
+}
+func (UnimplementedDefcorServer) ListCashFlows(*SymbolRequest, Defcor_ListCashFlowsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListCashFlows not implemented")
+}
+func (UnimplementedDefcorServer) mustEmbedUnimplementedDefcorServer() {}
+
+// UnsafeDefcorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DefcorServer will
+// result in compilation errors.
+type UnsafeDefcorServer interface {
+	mustEmbedUnimplementedDefcorServer()
+}
+
+func RegisterDefcorServer(s grpc.ServiceRegistrar, srv DefcorServer) {
+	s.RegisterService(&Defcor_ServiceDesc, srv)
+}
+
+func _Defcor_ListStocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListStocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DefcorServer).ListStocks(m, &defcorListStocksServer{stream})
+}
+
+type Defcor_ListStocksServer interface {
+	Send(*Stock) error
+	grpc.ServerStream
+}
+
+type defcorListStocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *defcorListStocksServer) Send(m *Stock) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Defcor_GetStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DefcorServer).GetStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/defcor.v1.Defcor/GetStock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DefcorServer).GetStock(ctx, req.(*GetStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Defcor_StreamBars_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBarsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DefcorServer).StreamBars(m, &defcorStreamBarsServer{stream})
+}
+
+type Defcor_StreamBarsServer interface {
+	Send(*Bar) error
+	grpc.ServerStream
+}
+
+type defcorStreamBarsServer struct {
+	grpc.ServerStream
+}
+
+func (x *defcorStreamBarsServer) Send(m *Bar) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Defcor_ListDividends_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SymbolRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DefcorServer).ListDividends(m, &defcorListDividendsServer{stream})
+}
+
+type Defcor_ListDividendsServer interface {
+	Send(*Dividend) error
+	grpc.ServerStream
+}
+
+type defcorListDividendsServer struct {
+	grpc.ServerStream
+}
+
+func (x *defcorListDividendsServer) Send(m *Dividend) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Defcor_ListSplits_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SymbolRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DefcorServer).ListSplits(m, &defcorListSplitsServer{stream})
+}
+
+type Defcor_ListSplitsServer interface {
+	Send(*Split) error
+	grpc.ServerStream
+}
+
+type defcorListSplitsServer struct {
+	grpc.ServerStream
+}
+
+func (x *defcorListSplitsServer) Send(m *Split) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Defcor_ListIncomeStatements_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SymbolRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DefcorServer).ListIncomeStatements(m, &defcorListIncomeStatementsServer{stream})
+}
+
+type Defcor_ListIncomeStatementsServer interface {
+	Send(*IncomeStatement) error
+	grpc.ServerStream
+}
+
+type defcorListIncomeStatementsServer struct {
+	grpc.ServerStream
+}
+
+func (x *defcorListIncomeStatementsServer) Send(m *IncomeStatement) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Defcor_ListBalanceSheets_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SymbolRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DefcorServer).ListBalanceSheets(m, &defcorListBalanceSheetsServer{stream})
+}
+
+type Defcor_ListBalanceSheetsServer interface {
+	Send(*BalanceSheet) error
+	grpc.ServerStream
+}
+
+type defcorListBalanceSheetsServer struct {
+	grpc.ServerStream
+}
+
+func (x *defcorListBalanceSheetsServer) Send(m *BalanceSheet) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Defcor_ListCashFlows_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SymbolRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DefcorServer).ListCashFlows(m, &defcorListCashFlowsServer{stream})
+}
+
+type Defcor_ListCashFlowsServer interface {
+	Send(*CashFlow) error
+	grpc.ServerStream
+}
+
+type defcorListCashFlowsServer struct {
+	grpc.ServerStream
+}
+
+func (x *defcorListCashFlowsServer) Send(m *CashFlow) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Defcor_ServiceDesc is the grpc.ServiceDesc for Defcor service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Defcor_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "defcor.v1.Defcor",
+	HandlerType: (*DefcorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStock",
+			Handler:    _Defcor_GetStock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListStocks",
+			Handler:       _Defcor_ListStocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamBars",
+			Handler:       _Defcor_StreamBars_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListDividends",
+			Handler:       _Defcor_ListDividends_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListSplits",
+			Handler:       _Defcor_ListSplits_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListIncomeStatements",
+			Handler:       _Defcor_ListIncomeStatements_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListBalanceSheets",
+			Handler:       _Defcor_ListBalanceSheets_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListCashFlows",
+			Handler:       _Defcor_ListCashFlows_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "defcor.proto",
+}
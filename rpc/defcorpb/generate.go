@@ -0,0 +1,4 @@
+// Package defcorpb holds the protobuf messages and grpc stubs of the Defcor service
+package defcorpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative defcor.proto
@@ -0,0 +1,309 @@
+package rpc
+
+import (
+	"context"
+	"defcor/adjust"
+	"defcor/db"
+	"defcor/iex"
+	"defcor/logging"
+	"defcor/rest"
+	"defcor/rpc/defcorpb"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Service implements the Defcor grpc service over the stored data
+type Service struct {
+	defcorpb.UnimplementedDefcorServer
+	db  store
+	log *logging.Logger
+}
+
+// store is what the service reads from the database
+type store interface {
+	CheckToken(hash string) (string, error)
+	Stocks() ([]iex.Stock, error)
+	Stock(symbol string) (iex.Stock, error)
+	EachBar(ctx context.Context, symbols []string, from, to string, fn func(symbol string, p iex.Prices) error) error
+	PriceHistory(symbol, from, to string) (*iex.PriceHistory, error)
+	DividendHistory(symbol string) (*iex.DividendHistory, error)
+	SplitHistory(symbol string) (*iex.SplitHistory, error)
+	IncomeHistory(symbol string) (*iex.IncomeHistory, error)
+	BalanceHistory(symbol string) (*iex.BalanceHistory, error)
+	CashFlowHistory(symbol string) (*iex.CashFlowHistory, error)
+}
+
+var _ store = (*db.Conn)(nil)
+
+// NewServer returns a grpc server with the Defcor service registered; like the
+// rest api every call must carry a live api token as bearer authorization
+func NewServer(conn *db.Conn, log *logging.Logger) *grpc.Server {
+	return newServer(conn, log)
+}
+
+func newServer(conn store, log *logging.Logger) *grpc.Server {
+	s := &Service{db: conn, log: log}
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(s.unary),
+		grpc.StreamInterceptor(s.stream),
+	)
+	defcorpb.RegisterDefcorServer(srv, s)
+	return srv
+}
+
+// authorize checks the bearer token in the call metadata
+func (s *Service) authorize(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	auth := md.Get("authorization")
+	if len(auth) == 0 || !strings.HasPrefix(auth[0], "Bearer ") {
+		return "", status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	client, err := s.db.CheckToken(rest.HashToken(strings.TrimPrefix(auth[0], "Bearer ")))
+	if err == db.ErrNotFound {
+		return "", status.Error(codes.Unauthenticated, "invalid token")
+	}
+	if err != nil {
+		return "", s.internal(err)
+	}
+	return client, nil
+}
+
+func (s *Service) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	client, err := s.authorize(ctx)
+	var resp interface{}
+	if err == nil {
+		resp, err = handler(ctx, req)
+	}
+	s.log.Info("rpc", "client", client, "method", info.FullMethod,
+		"code", status.Code(err), "duration", time.Since(start))
+	return resp, err
+}
+
+func (s *Service) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	client, err := s.authorize(ss.Context())
+	if err == nil {
+		err = handler(srv, ss)
+	}
+	s.log.Info("rpc", "client", client, "method", info.FullMethod,
+		"code", status.Code(err), "duration", time.Since(start))
+	return err
+}
+
+// internal logs err and hides its detail from the client
+func (s *Service) internal(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	s.log.Error("rpc failed", "err", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// known upper cases symbol and checks it is a stored stock
+func (s *Service) known(symbol string) (string, error) {
+	symbol = strings.ToUpper(symbol)
+	if _, err := s.db.Stock(symbol); err == db.ErrNotFound {
+		return "", status.Errorf(codes.NotFound, "unknown symbol %s", symbol)
+	} else if err != nil {
+		return "", s.internal(err)
+	}
+	return symbol, nil
+}
+
+// ListStocks streams every stock ordered by symbol
+func (s *Service) ListStocks(req *defcorpb.ListStocksRequest, stream defcorpb.Defcor_ListStocksServer) error {
+	stks, err := s.db.Stocks()
+	if err != nil {
+		return s.internal(err)
+	}
+	sort.Slice(stks, func(i, j int) bool { return stks[i].Symbol < stks[j].Symbol })
+	for _, stk := range stks {
+		if err := stream.Send(stockMessage(stk)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStock returns one stock
+func (s *Service) GetStock(ctx context.Context, req *defcorpb.GetStockRequest) (*defcorpb.Stock, error) {
+	stk, err := s.db.Stock(strings.ToUpper(req.Symbol))
+	if err == db.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "unknown symbol %s", req.Symbol)
+	}
+	if err != nil {
+		return nil, s.internal(err)
+	}
+	return stockMessage(stk), nil
+}
+
+// StreamBars streams bars symbol by symbol. Stored adjustments are sent as
+// rows are read; recomputed ones hold a single symbol's history at a time.
+func (s *Service) StreamBars(req *defcorpb.StreamBarsRequest, stream defcorpb.Defcor_StreamBarsServer) error {
+	if len(req.Symbols) == 0 {
+		return status.Error(codes.InvalidArgument, "no symbols requested")
+	}
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, sym := range req.Symbols {
+		symbol, err := s.known(sym)
+		if err != nil {
+			return err
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	from, to := req.From, req.To
+	switch req.Adjustment {
+	case defcorpb.Adjustment_ADJUSTMENT_IEX, defcorpb.Adjustment_ADJUSTMENT_RAW:
+		if from == "" {
+			from = "-infinity"
+		}
+		if to == "" {
+			to = "infinity"
+		}
+		raw := req.Adjustment == defcorpb.Adjustment_ADJUSTMENT_RAW
+		err := s.db.EachBar(stream.Context(), symbols, from, to, func(symbol string, p iex.Prices) error {
+			return stream.Send(barMessage(symbol, p, raw))
+		})
+		return s.internal(err)
+	case defcorpb.Adjustment_ADJUSTMENT_SPLIT, defcorpb.Adjustment_ADJUSTMENT_TOTAL:
+		mode := adjust.SplitOnly
+		if req.Adjustment == defcorpb.Adjustment_ADJUSTMENT_TOTAL {
+			mode = adjust.TotalReturn
+		}
+		for _, symbol := range symbols {
+			ph, err := s.recompute(symbol, mode)
+			if err != nil {
+				return s.internal(err)
+			}
+			for _, p := range ph.Prices {
+				if (from != "" && p.Date < from) || (to != "" && p.Date > to) {
+					continue
+				}
+				if err := stream.Send(barMessage(symbol, p, false)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return status.Errorf(codes.InvalidArgument, "unknown adjustment %v", req.Adjustment)
+}
+
+// recompute adjusts the full history of symbol so factors see every action
+func (s *Service) recompute(symbol string, mode adjust.Mode) (*iex.PriceHistory, error) {
+	ph, err := s.db.PriceHistory(symbol, "-infinity", "infinity")
+	if err != nil {
+		return nil, err
+	}
+	sh, err := s.db.SplitHistory(symbol)
+	if err != nil {
+		return nil, err
+	}
+	dh, err := s.db.DividendHistory(symbol)
+	if err != nil {
+		return nil, err
+	}
+	return adjust.Series(ph, sh, dh, "", mode), nil
+}
+
+// ListDividends streams the dividends of a symbol by exdate
+func (s *Service) ListDividends(req *defcorpb.SymbolRequest, stream defcorpb.Defcor_ListDividendsServer) error {
+	symbol, err := s.known(req.Symbol)
+	if err != nil {
+		return err
+	}
+	dh, err := s.db.DividendHistory(symbol)
+	if err != nil {
+		return s.internal(err)
+	}
+	for _, d := range dh.Dividends {
+		if err := stream.Send(dividendMessage(d)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSplits streams the splits of a symbol by exdate
+func (s *Service) ListSplits(req *defcorpb.SymbolRequest, stream defcorpb.Defcor_ListSplitsServer) error {
+	symbol, err := s.known(req.Symbol)
+	if err != nil {
+		return err
+	}
+	sh, err := s.db.SplitHistory(symbol)
+	if err != nil {
+		return s.internal(err)
+	}
+	for _, sp := range sh.Splits {
+		if err := stream.Send(splitMessage(sp)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListIncomeStatements streams the income statements of a symbol, latest first
+func (s *Service) ListIncomeStatements(req *defcorpb.SymbolRequest, stream defcorpb.Defcor_ListIncomeStatementsServer) error {
+	symbol, err := s.known(req.Symbol)
+	if err != nil {
+		return err
+	}
+	ih, err := s.db.IncomeHistory(symbol)
+	if err != nil {
+		return s.internal(err)
+	}
+	for _, st := range ih.Income {
+		if err := stream.Send(incomeMessage(st)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListBalanceSheets streams the balance sheets of a symbol, latest first
+func (s *Service) ListBalanceSheets(req *defcorpb.SymbolRequest, stream defcorpb.Defcor_ListBalanceSheetsServer) error {
+	symbol, err := s.known(req.Symbol)
+	if err != nil {
+		return err
+	}
+	bh, err := s.db.BalanceHistory(symbol)
+	if err != nil {
+		return s.internal(err)
+	}
+	for _, st := range bh.Balancesheet {
+		if err := stream.Send(balanceMessage(st)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListCashFlows streams the cash flow statements of a symbol, latest first
+func (s *Service) ListCashFlows(req *defcorpb.SymbolRequest, stream defcorpb.Defcor_ListCashFlowsServer) error {
+	symbol, err := s.known(req.Symbol)
+	if err != nil {
+		return err
+	}
+	ch, err := s.db.CashFlowHistory(symbol)
+	if err != nil {
+		return s.internal(err)
+	}
+	for _, st := range ch.Cashflow {
+		if err := stream.Send(cashFlowMessage(st)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
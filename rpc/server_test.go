@@ -0,0 +1,242 @@
+package rpc
+
+import (
+	"context"
+	"defcor/db"
+	"defcor/iex"
+	"defcor/logging"
+	"defcor/rest"
+	"defcor/rpc/defcorpb"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const token = "secret"
+
+// memory serves the service from fixed histories
+type memory struct {
+	stocks []iex.Stock
+	prices map[string][]iex.Prices
+	splits map[string][]iex.Split
+}
+
+func (m *memory) CheckToken(hash string) (string, error) {
+	if hash != rest.HashToken(token) {
+		return "", db.ErrNotFound
+	}
+	return "tester", nil
+}
+
+func (m *memory) Stocks() ([]iex.Stock, error) {
+	return append([]iex.Stock{}, m.stocks...), nil
+}
+
+func (m *memory) Stock(symbol string) (iex.Stock, error) {
+	for _, s := range m.stocks {
+		if s.Symbol == symbol {
+			return s, nil
+		}
+	}
+	return iex.Stock{}, db.ErrNotFound
+}
+
+// EachBar walks symbols in the order given, as the query does once sorted
+func (m *memory) EachBar(ctx context.Context, symbols []string, from, to string, fn func(symbol string, p iex.Prices) error) error {
+	for _, symbol := range symbols {
+		for _, p := range m.prices[symbol] {
+			if (from != "-infinity" && p.Date < from) || (to != "infinity" && p.Date > to) {
+				continue
+			}
+			if err := fn(symbol, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memory) PriceHistory(symbol, from, to string) (*iex.PriceHistory, error) {
+	return &iex.PriceHistory{Symbol: symbol, Prices: m.prices[symbol]}, nil
+}
+
+func (m *memory) DividendHistory(symbol string) (*iex.DividendHistory, error) {
+	return &iex.DividendHistory{Symbol: symbol}, nil
+}
+
+func (m *memory) SplitHistory(symbol string) (*iex.SplitHistory, error) {
+	return &iex.SplitHistory{Symbol: symbol, Splits: m.splits[symbol]}, nil
+}
+
+func (m *memory) IncomeHistory(symbol string) (*iex.IncomeHistory, error) {
+	return &iex.IncomeHistory{Symbol: symbol}, nil
+}
+
+func (m *memory) BalanceHistory(symbol string) (*iex.BalanceHistory, error) {
+	return &iex.BalanceHistory{Symbol: symbol}, nil
+}
+
+func (m *memory) CashFlowHistory(symbol string) (*iex.CashFlowHistory, error) {
+	return &iex.CashFlowHistory{Symbol: symbol}, nil
+}
+
+func bar(date string, close float64) iex.Prices {
+	return iex.Prices{Date: date, Uopen: close, Uhigh: close, Ulow: close, Uclose: close, Uvolume: 100,
+		Aopen: close, Ahigh: close, Alow: close, Aclose: close, Avolume: 100}
+}
+
+// dial serves m over an in-memory listener and returns a client of it
+func dial(t *testing.T, m *memory) defcorpb.DefcorClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := newServer(m, logging.New(ioutil.Discard, logging.Error, logging.Logfmt))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	cc, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return defcorpb.NewDefcorClient(cc)
+}
+
+func authorized() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func fixture() *memory {
+	return &memory{
+		stocks: []iex.Stock{{Symbol: "MSFT", Name: "Microsoft"}, {Symbol: "AAPL", Name: "Apple Inc"}},
+		prices: map[string][]iex.Prices{
+			"AAPL": {bar("2020-08-28", 500), bar("2020-08-31", 130), bar("2020-09-01", 134)},
+			"MSFT": {bar("2020-08-28", 228), bar("2020-08-31", 225)},
+		},
+		splits: map[string][]iex.Split{"AAPL": {{ExDate: "2020-08-31", ToFactor: 4, FromFactor: 1}}},
+	}
+}
+
+func TestAuth(t *testing.T) {
+	client := dial(t, fixture())
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{"no token", context.Background(), codes.Unauthenticated},
+		{"not a bearer token", metadata.AppendToOutgoingContext(context.Background(), "authorization", token), codes.Unauthenticated},
+		{"unknown token", metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer other"), codes.Unauthenticated},
+		{"live token", authorized(), codes.OK},
+	}
+	for _, tt := range tests {
+		_, err := client.GetStock(tt.ctx, &defcorpb.GetStockRequest{Symbol: "AAPL"})
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: unary got %v, want %v", tt.name, err, tt.code)
+		}
+		stream, err := client.ListStocks(tt.ctx, &defcorpb.ListStocksRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != tt.code {
+			t.Errorf("%s: stream got %v, want %v", tt.name, err, tt.code)
+		}
+	}
+}
+
+func TestUnknownSymbol(t *testing.T) {
+	client := dial(t, fixture())
+	if _, err := client.GetStock(authorized(), &defcorpb.GetStockRequest{Symbol: "IBM"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetStock: got %v, want %v", err, codes.NotFound)
+	}
+	for name, req := range map[string]*defcorpb.StreamBarsRequest{
+		"alone":          {Symbols: []string{"IBM"}},
+		"among known":    {Symbols: []string{"AAPL", "ibm"}},
+		"recomputed too": {Symbols: []string{"IBM"}, Adjustment: defcorpb.Adjustment_ADJUSTMENT_SPLIT},
+	} {
+		bars, err := collect(client, req)
+		if status.Code(err) != codes.NotFound || len(bars) != 0 {
+			t.Errorf("StreamBars %s: got %d bars, %v; want %v before any bar", name, len(bars), err, codes.NotFound)
+		}
+	}
+	stream, err := client.ListDividends(authorized(), &defcorpb.SymbolRequest{Symbol: "IBM"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("ListDividends: got %v, want %v", err, codes.NotFound)
+	}
+}
+
+func collect(client defcorpb.DefcorClient, req *defcorpb.StreamBarsRequest) ([]*defcorpb.Bar, error) {
+	stream, err := client.StreamBars(authorized(), req)
+	if err != nil {
+		return nil, err
+	}
+	var bars []*defcorpb.Bar
+	for {
+		b, err := stream.Recv()
+		if err == io.EOF {
+			return bars, nil
+		}
+		if err != nil {
+			return bars, err
+		}
+		bars = append(bars, b)
+	}
+}
+
+func TestStreamBarsOrder(t *testing.T) {
+	client := dial(t, fixture())
+	type row struct {
+		symbol, date string
+		close        float64
+	}
+	tests := []struct {
+		name string
+		req  *defcorpb.StreamBarsRequest
+		want []row
+	}{
+		{
+			name: "stored",
+			req:  &defcorpb.StreamBarsRequest{Symbols: []string{"msft", "AAPL", "MSFT"}},
+			want: []row{
+				{"AAPL", "2020-08-28", 500}, {"AAPL", "2020-08-31", 130}, {"AAPL", "2020-09-01", 134},
+				{"MSFT", "2020-08-28", 228}, {"MSFT", "2020-08-31", 225},
+			},
+		},
+		{
+			name: "recomputed within bounds",
+			req: &defcorpb.StreamBarsRequest{Symbols: []string{"MSFT", "AAPL"}, From: "2020-08-28", To: "2020-08-31",
+				Adjustment: defcorpb.Adjustment_ADJUSTMENT_SPLIT},
+			want: []row{
+				{"AAPL", "2020-08-28", 125}, {"AAPL", "2020-08-31", 130},
+				{"MSFT", "2020-08-28", 228}, {"MSFT", "2020-08-31", 225},
+			},
+		},
+	}
+	for _, tt := range tests {
+		bars, err := collect(client, tt.req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(bars) != len(tt.want) {
+			t.Fatalf("%s: got %v, want %v", tt.name, bars, tt.want)
+		}
+		for i, b := range bars {
+			if got := (row{b.Symbol, b.Date, b.Close}); got != tt.want[i] {
+				t.Errorf("%s: bar %d = %v, want %v", tt.name, i, got, tt.want[i])
+			}
+		}
+	}
+	if _, err := collect(client, &defcorpb.StreamBarsRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("no symbols: got %v, want %v", err, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,69 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	at := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			panic(err)
+		}
+		return d
+	}
+	tests := []struct {
+		expr string
+		from string
+		want string
+	}{
+		{"30 16 * * 1-5", "2021-01-08 17:00", "2021-01-11 16:30"},
+		{"30 16 * * 1-5", "2021-01-08 16:29", "2021-01-08 16:30"},
+		{"30 16 * * 1-5", "2021-01-08 16:30", "2021-01-11 16:30"},
+		{"*/15 * * * *", "2021-01-08 10:07", "2021-01-08 10:15"},
+		{"0,30 9-10 * * *", "2021-01-08 10:30", "2021-01-09 09:00"},
+		// either restricted day field matches
+		{"0 0 1 * 1", "2021-01-02 00:00", "2021-01-04 00:00"},
+		{"0 0 1 * 1", "2021-01-29 00:00", "2021-02-01 00:00"},
+		// 7 is sunday
+		{"0 9 * * 7", "2021-01-01 00:00", "2021-01-03 09:00"},
+		{"0 0 29 2 *", "2021-03-01 00:00", "2024-02-29 00:00"},
+		{"0 12 1 */3 *", "2021-01-01 12:00", "2021-04-01 12:00"},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+		}
+		if got := c.Next(at(tt.from)).Format("2006-01-02 15:04"); got != tt.want {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestCronNextNever(t *testing.T) {
+	c, err := ParseCron("0 0 31 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Next(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("Next = %s, want the zero time", got)
+	}
+}
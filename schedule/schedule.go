@@ -0,0 +1,118 @@
+package schedule
+
+import (
+	"context"
+	"defcor/calendar"
+	"defcor/logging"
+	"strings"
+	"time"
+)
+
+// Job is a named task fired on a cron expression in exchange time
+type Job struct {
+	Name string
+	Cron *Cron
+	// TradingDays skips fires on days the exchange is closed
+	TradingDays bool
+	Run         func(ctx context.Context) error
+}
+
+// Locker keeps two processes from running the same job at once
+type Locker interface {
+	TryLock(name string) (bool, error)
+	Unlock(name string) error
+}
+
+// Scheduler fires jobs one at a time in the calendar's time zone. A job
+// still running when its next fire comes round simply misses that fire,
+// so runs never overlap.
+type Scheduler struct {
+	Calendar *calendar.Calendar
+	Jobs     []Job
+	// Locker is optional; without it only this process is serialized
+	Locker Locker
+	Log    *logging.Logger
+}
+
+// Run fires jobs until ctx is cancelled. Cancellation is passed to the
+// running job, which is expected to finish its current unit of work.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		now := time.Now().In(s.Calendar.Location)
+		next, due := s.next(now)
+		if len(due) == 0 {
+			s.Log.Warn("nothing scheduled")
+			<-ctx.Done()
+			return nil
+		}
+		s.Log.Info("next run", "jobs", strings.Join(jobNames(due), ","), "at", next)
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		for _, job := range due {
+			if ctx.Err() != nil {
+				return nil
+			}
+			s.fire(ctx, job, next)
+		}
+	}
+}
+
+// next finds the earliest fire after now and every job due then
+func (s *Scheduler) next(now time.Time) (time.Time, []Job) {
+	var at time.Time
+	var due []Job
+	for _, job := range s.Jobs {
+		t := job.Cron.Next(now)
+		switch {
+		case t.IsZero():
+		case at.IsZero() || t.Before(at):
+			at, due = t, []Job{job}
+		case t.Equal(at):
+			due = append(due, job)
+		}
+	}
+	return at, due
+}
+
+func (s *Scheduler) fire(ctx context.Context, job Job, at time.Time) {
+	if job.TradingDays && !s.Calendar.IsTradingDay(at) {
+		s.Log.Info("job skipped", "job", job.Name, "reason", "not a trading day", "date", at.Format(calendar.DateFormat))
+		return
+	}
+	if s.Locker != nil {
+		ok, err := s.Locker.TryLock(job.Name)
+		if err != nil {
+			s.Log.Error("job lock failed", "job", job.Name, "err", err)
+			return
+		}
+		if !ok {
+			s.Log.Info("job skipped", "job", job.Name, "reason", "running elsewhere")
+			return
+		}
+		defer func() {
+			if err := s.Locker.Unlock(job.Name); err != nil {
+				s.Log.Error("job unlock failed", "job", job.Name, "err", err)
+			}
+		}()
+	}
+	start := time.Now()
+	s.Log.Info("job started", "job", job.Name)
+	if err := job.Run(ctx); err != nil {
+		s.Log.Error("job failed", "job", job.Name, "duration", time.Since(start), "err", err)
+		return
+	}
+	s.Log.Info("job finished", "job", job.Name, "duration", time.Since(start))
+}
+
+func jobNames(jobs []Job) []string {
+	names := make([]string, len(jobs))
+	for i, j := range jobs {
+		names[i] = j.Name
+	}
+	return names
+}
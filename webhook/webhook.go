@@ -0,0 +1,130 @@
+// Package webhook signs, sends and verifies event deliveries. A delivery is a
+// POST of the event json; its signature is the hex hmac-sha256, keyed by the
+// subscription secret, of the timestamp header, a dot and the body.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Delivery headers
+const (
+	EventHeader     = "X-Defcor-Event"
+	DeliveryHeader  = "X-Defcor-Delivery"
+	TimestampHeader = "X-Defcor-Timestamp"
+	SignatureHeader = "X-Defcor-Signature"
+)
+
+// GenerateSecret makes a new random signing secret
+func GenerateSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature header value of body sent at timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrSignature is returned for a delivery that is unsigned, wrongly signed or stale
+var ErrSignature = errors.New("invalid webhook signature")
+
+// Verify checks the signature of a received delivery and that it was sent
+// within tolerance of now, so captured requests cannot be replayed later
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	sent := time.Unix(timestamp, 0)
+	if sent.Before(now.Add(-tolerance)) || sent.After(now.Add(tolerance)) {
+		return ErrSignature
+	}
+	want := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(want)) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// Sender posts signed deliveries
+type Sender struct {
+	Client *http.Client
+}
+
+// Send posts body to url and returns the response status; anything but a
+// 2xx response is an error
+func (s *Sender) Send(ctx context.Context, url, secret, event string, delivery int64, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "defcor-webhook")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// drain so the connection is reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook %s: %s", url, resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Receiver is a minimal consumer endpoint that verifies deliveries before
+// handing them to Handle; it stands in for a real consumer in local testing
+type Receiver struct {
+	Secret    string
+	Tolerance time.Duration
+	Handle    func(event string, delivery string, body []byte) error
+}
+
+// ServeHTTP answers 204 for an accepted delivery, 401 for a bad signature
+// and 500 when Handle fails, so the sender retries
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "deliveries are posted", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := Verify(rc.Secret, r.Header, body, rc.Tolerance, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err := rc.Handle(r.Header.Get(EventHeader), r.Header.Get(DeliveryHeader), body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"kind":"bars","symbol":"AAPL"}`)
+	now := time.Unix(1610000000, 0)
+	signed := func(secret string, sent time.Time, body []byte) http.Header {
+		h := make(http.Header)
+		h.Set(TimestampHeader, strconv.FormatInt(sent.Unix(), 10))
+		h.Set(SignatureHeader, Sign(secret, sent.Unix(), body))
+		return h
+	}
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		ok     bool
+	}{
+		{"valid", signed("s3cret", now, body), body, true},
+		{"within tolerance", signed("s3cret", now.Add(-4*time.Minute), body), body, true},
+		{"stale", signed("s3cret", now.Add(-6*time.Minute), body), body, false},
+		{"from the future", signed("s3cret", now.Add(6*time.Minute), body), body, false},
+		{"wrong secret", signed("other", now, body), body, false},
+		{"body changed", signed("s3cret", now, body), []byte(`{"kind":"split"}`), false},
+		{"unsigned", make(http.Header), body, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify("s3cret", tt.header, tt.body, 5*time.Minute, now)
+			if tt.ok && err != nil {
+				t.Errorf("got %v, want nil", err)
+			}
+			if !tt.ok && err != ErrSignature {
+				t.Errorf("got %v, want %v", err, ErrSignature)
+			}
+		})
+	}
+}
+
+func TestSenderToReceiver(t *testing.T) {
+	body := []byte(`{"kind":"dividend","symbol":"IBM"}`)
+	tests := []struct {
+		name       string
+		secret     string
+		handleErr  error
+		wantStatus int
+		wantErr    bool
+	}{
+		{"accepted", "s3cret", nil, http.StatusNoContent, false},
+		{"bad signature", "other", nil, http.StatusUnauthorized, true},
+		{"consumer fails", "s3cret", errors.New("disk full"), http.StatusInternalServerError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotEvent, gotDelivery, gotBody string
+			srv := httptest.NewServer(&Receiver{
+				Secret:    "s3cret",
+				Tolerance: time.Minute,
+				Handle: func(event, delivery string, b []byte) error {
+					gotEvent, gotDelivery, gotBody = event, delivery, string(b)
+					return tt.handleErr
+				},
+			})
+			defer srv.Close()
+			s := &Sender{Client: srv.Client()}
+			status, err := s.Send(context.Background(), srv.URL, tt.secret, "dividend", 42, body)
+			if status != tt.wantStatus || (err != nil) != tt.wantErr {
+				t.Fatalf("Send = %d, %v, want %d and error %v", status, err, tt.wantStatus, tt.wantErr)
+			}
+			if tt.wantStatus == http.StatusUnauthorized {
+				if gotEvent != "" {
+					t.Errorf("unverified delivery reached the handler")
+				}
+				return
+			}
+			if gotEvent != "dividend" || gotDelivery != "42" || gotBody != string(body) {
+				t.Errorf("handled %q %q %q", gotEvent, gotDelivery, gotBody)
+			}
+		})
+	}
+}
+
+func TestReceiverRejectsGet(t *testing.T) {
+	rec := httptest.NewRecorder()
+	(&Receiver{Secret: "s3cret"}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET answered %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}